@@ -0,0 +1,29 @@
+package omdb
+
+import "context"
+
+//API is the set of lookups of Client. Code depending on API instead of
+//*Client can be tested with a MockClient.
+type API interface {
+	SearchByImdbID(q QueryData) (interface{}, error)
+	SearchByImdbIDContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error)
+	SearchByTitle(q QueryData) (interface{}, error)
+	SearchByTitleContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error)
+	SearchByText(q QueryData) (*SearchResponse, error)
+	SearchByTextContext(ctx context.Context, q QueryData, opts ...CallOption) (*SearchResponse, error)
+
+	GetSeason(ctx context.Context, seriesID string, season int, opts ...CallOption) (*SeasonResult, error)
+	GetEpisode(ctx context.Context, seriesID string, season, episode int, opts ...CallOption) (*EpisodeResult, error)
+
+	GetMovieByID(ctx context.Context, id string, opts ...CallOption) (*MovieResult, error)
+	GetMovieByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*MovieResult, error)
+	GetSeriesByID(ctx context.Context, id string, opts ...CallOption) (*SeriesResult, error)
+	GetSeriesByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*SeriesResult, error)
+	GetEpisodeByID(ctx context.Context, id string, opts ...CallOption) (*EpisodeResult, error)
+	GetEpisodeByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*EpisodeResult, error)
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = (*MockClient)(nil)
+)
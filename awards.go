@@ -0,0 +1,81 @@
+package omdb
+
+import (
+	"regexp"
+	"strconv"
+)
+
+//Awards is the parsed form of the Awards field of a result.
+type Awards struct {
+	OscarWins        int
+	OscarNominations int
+	EmmyWins         int
+	EmmyNominations  int
+	Wins             int
+	Nominations      int
+}
+
+var (
+	awardsWon       = regexp.MustCompile(`Won (\d+) (Oscar|Primetime Emmy)`)
+	awardsNominated = regexp.MustCompile(`Nominated for (\d+) (Oscar|Primetime Emmy)`)
+	awardsWins      = regexp.MustCompile(`(\d+) wins?\b`)
+	awardsNoms      = regexp.MustCompile(`(\d+) nominations?\b`)
+	awardsAnother   = regexp.MustCompile(`\bAnother \d+`)
+)
+
+//ParseAwards parses an Awards value like "Won 3 Oscars. 171 wins & 223
+//nominations total." or "Nominated for 1 Oscar. Another 5 wins & 10
+//nominations.". Wins and Nominations are the totals, which include the Oscars
+//and Emmys: OMDB counts them in the numbers followed by "total" but not in the
+//ones after "Another". ErrNotAvailable is returned for "N/A" or an empty
+//value.
+func ParseAwards(s string) (Awards, error) {
+
+	a := Awards{}
+	if notAvailable(s) == "" {
+		return a, ErrNotAvailable
+	}
+
+	for _, m := range awardsWon.FindAllStringSubmatch(s, -1) {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "Oscar" {
+			a.OscarWins = n
+		} else {
+			a.EmmyWins = n
+		}
+	}
+	for _, m := range awardsNominated.FindAllStringSubmatch(s, -1) {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "Oscar" {
+			a.OscarNominations = n
+		} else {
+			a.EmmyNominations = n
+		}
+	}
+	//without a total, like in "Won 1 Oscar.", only the Oscars and Emmys count.
+	another := awardsAnother.MatchString(s)
+	wins, noms := awardsWins.FindStringSubmatch(s), awardsNoms.FindStringSubmatch(s)
+	if wins != nil {
+		a.Wins, _ = strconv.Atoi(wins[1])
+	}
+	if noms != nil {
+		a.Nominations, _ = strconv.Atoi(noms[1])
+	}
+	if another || wins == nil {
+		a.Wins += a.OscarWins + a.EmmyWins
+	}
+	if another || noms == nil {
+		a.Nominations += a.OscarNominations + a.EmmyNominations
+	}
+
+	return a, nil
+}
+
+//ParsedAwards parses the Awards of the movie, see ParseAwards.
+func (m MovieResult) ParsedAwards() (Awards, error) { return ParseAwards(m.Awards) }
+
+//ParsedAwards parses the Awards of the series, see ParseAwards.
+func (s SeriesResult) ParsedAwards() (Awards, error) { return ParseAwards(s.Awards) }
+
+//ParsedAwards parses the Awards of the episode, see ParseAwards.
+func (e EpisodeResult) ParsedAwards() (Awards, error) { return ParseAwards(e.Awards) }
@@ -0,0 +1,33 @@
+package omdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAwards(t *testing.T) {
+
+	for _, tc := range []struct {
+		s    string
+		want Awards
+	}{
+		{"Won 4 Oscars. 42 wins & 51 nominations total", Awards{OscarWins: 4, Wins: 42, Nominations: 51}},
+		{"Nominated for 1 Oscar. Another 5 wins & 10 nominations.", Awards{OscarNominations: 1, Wins: 5, Nominations: 11}},
+		{"Won 3 Oscars. Another 38 wins & 38 nominations.", Awards{OscarWins: 3, Wins: 41, Nominations: 38}},
+		{"Won 2 Primetime Emmys. Another 1 win & 4 nominations.", Awards{EmmyWins: 2, Wins: 3, Nominations: 4}},
+		{"1 win & 2 nominations", Awards{Wins: 1, Nominations: 2}},
+		{"3 nominations", Awards{Nominations: 3}},
+		{"Won 1 Oscar.", Awards{OscarWins: 1, Wins: 1}},
+		{"Nominated for 2 Primetime Emmys.", Awards{EmmyNominations: 2, Nominations: 2}},
+		{"Won 1 Oscar. 3 nominations total", Awards{OscarWins: 1, Wins: 1, Nominations: 3}},
+	} {
+		got, err := ParseAwards(tc.s)
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got %+v, %v, want %+v", tc.s, got, err, tc.want)
+		}
+	}
+
+	if _, err := ParseAwards("N/A"); !errors.Is(err, ErrNotAvailable) {
+		t.Errorf("got error %v, want ErrNotAvailable", err)
+	}
+}
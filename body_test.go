@@ -0,0 +1,42 @@
+package omdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//flushing sends the body chunked, without Content-Length.
+		w.(http.Flusher).Flush()
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithMaxResponseSize(128))
+	q := QueryData{ImdbID: "tt0133093"}
+
+	body = `{"Title":"The Matrix","imdbID":"tt0133093","Type":"movie","Response":"True"}`
+	res, err := client.SearchByImdbID(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := res.(MovieResult); !ok || m.Title != "The Matrix" {
+		t.Errorf("got %+v", res)
+	}
+
+	body = `{"Title":"` + strings.Repeat("x", 200) + `","Type":"movie","Response":"True"}`
+	if _, err := client.SearchByImdbID(q); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("got error %v, want ErrResponseTooLarge", err)
+	}
+
+	for _, body = range []string{`{"Title":"The Matrix",`, `{"Title" "The Matrix"}`, ``} {
+		if _, err := client.SearchByImdbID(q); err == nil || err.Error() != "omdb: Invalid JSON response" {
+			t.Errorf("%q: got error %v, want an invalid response", body, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+//Package boltcache implements omdb.Cache in a bbolt database file, so that
+//cached lookups survive restarts of CLI and desktop programs.
+package boltcache
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/ahin/omdb"
+	bolt "go.etcd.io/bbolt"
+)
+
+//DefaultSweepInterval is how often expired entries are deleted unless
+//WithSweepInterval is given.
+const DefaultSweepInterval = time.Hour
+
+//Cache is an omdb.Cache stored in a bbolt database. Entries are kept in one
+//bucket per kind of request (id, title, search, season, episode), each value
+//is prefixed by its expiry time, 0 for a value which never expires.
+type Cache struct {
+	db       *bolt.DB
+	interval time.Duration
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+//Option configures a Cache.
+type Option func(*Cache)
+
+//WithSweepInterval sets how often expired entries are deleted from the file,
+//a zero interval disables sweeping; expired entries are never returned anyway.
+func WithSweepInterval(d time.Duration) Option {
+	return func(c *Cache) {
+		c.interval = d
+	}
+}
+
+//Open opens, or creates, the database file at path. The Cache must be closed
+//to release the file.
+func Open(path string, opts ...Option) (*Cache, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		db:       db,
+		interval: DefaultSweepInterval,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.interval > 0 {
+		c.wg.Add(1)
+		go c.sweeper()
+	}
+	return c, nil
+}
+
+//Close stops sweeping and closes the database file. Calling it again returns
+//the error of the first call.
+func (c *Cache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+		c.closeErr = c.db.Close()
+	})
+	return c.closeErr
+}
+
+//Get implements omdb.Cache.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+
+	var value []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket(key))
+		if b == nil {
+			return omdb.ErrCacheMiss
+		}
+		v := b.Get([]byte(key))
+		if v == nil || expired(v, time.Now()) {
+			return omdb.ErrCacheMiss
+		}
+		//v is only valid during the transaction.
+		value = append([]byte(nil), v[8:]...)
+		return nil
+	})
+	return value, err
+}
+
+//Set implements omdb.Cache.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+
+	//an expiry time of 0 never expires.
+	v := make([]byte, 8+len(value))
+	if ttl != 0 {
+		binary.BigEndian.PutUint64(v, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	copy(v[8:], value)
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucket(key))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), v)
+	})
+}
+
+//Delete implements omdb.Cache.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket(key))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+//Sweep deletes every expired entry.
+func (c *Cache) Sweep() error {
+
+	now := time.Now()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(_ []byte, b *bolt.Bucket) error {
+			var keys [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if expired(v, now) {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (c *Cache) sweeper() {
+	defer c.wg.Done()
+
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			//a failed sweep is retried on the next tick.
+			_ = c.Sweep()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+//bucket returns the name of the bucket holding key.
+func bucket(key string) []byte {
+	op := omdb.CacheKeyOp(key)
+	if op == "" {
+		op = "other"
+	}
+	return []byte(op)
+}
+
+func expired(v []byte, now time.Time) bool {
+	if len(v) < 8 {
+		return true
+	}
+	expires := int64(binary.BigEndian.Uint64(v))
+	return expires != 0 && expires < now.UnixNano()
+}
@@ -0,0 +1,70 @@
+package boltcache_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/boltcache"
+)
+
+func TestCache(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := boltcache.Open(path, boltcache.WithSweepInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	key := omdb.CacheKey(omdb.QueryData{ImdbID: "tt0133093"}, omdb.OpByID)
+	if err := cache.Set(ctx, key, []byte("movie"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(ctx, "i=tt0000001", []byte("expired"), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := cache.Get(ctx, key); err != nil || string(data) != "movie" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if _, err := cache.Get(ctx, "i=tt0000001"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v for an expired entry, want ErrCacheMiss", err)
+	}
+
+	//a ttl of 0 never expires, nor is it swept.
+	if err := cache.Set(ctx, "i=tt0000002", []byte("forever"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Sweep(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := cache.Get(ctx, "i=tt0000002"); err != nil || string(data) != "forever" {
+		t.Errorf("got %q, %v for an entry without a ttl", data, err)
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("got error %v closing twice", err)
+	}
+
+	//the entries survive reopening.
+	cache, err = boltcache.Open(path, boltcache.WithSweepInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if data, err := cache.Get(ctx, key); err != nil || string(data) != "movie" {
+		t.Errorf("reopened: got %q, %v", data, err)
+	}
+	if err := cache.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, key); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v after Delete, want ErrCacheMiss", err)
+	}
+}
@@ -0,0 +1,48 @@
+package omdb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//currencies maps the currency symbols seen in BoxOffice to ISO 4217 codes.
+var currencies = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"¥": "JPY",
+	"₹": "INR",
+}
+
+//ParseBoxOffice parses a BoxOffice value like "$123,456,789" into its amount
+//and currency code ("USD"). A symbol without a known code is returned as is.
+//ErrNotAvailable is returned for "N/A" or an empty value.
+func ParseBoxOffice(s string) (amount int64, currency string, err error) {
+
+	s = notAvailable(strings.TrimSpace(s))
+	if s == "" {
+		return 0, "", ErrNotAvailable
+	}
+
+	i := strings.IndexFunc(s, unicode.IsDigit)
+	if i < 0 {
+		return 0, "", errors.New("omdb: Invalid box office value " + strconv.Quote(s))
+	}
+	currency = strings.TrimSpace(s[:i])
+	if code, ok := currencies[currency]; ok {
+		currency = code
+	}
+
+	amount, err = strconv.ParseInt(strings.ReplaceAll(s[i:], ",", ""), 10, 64)
+	if err != nil {
+		return 0, "", errors.New("omdb: Invalid box office value " + strconv.Quote(s))
+	}
+	return amount, currency, nil
+}
+
+//BoxOfficeAmount parses the BoxOffice of the movie, see ParseBoxOffice.
+func (m MovieResult) BoxOfficeAmount() (amount int64, currency string, err error) {
+	return ParseBoxOffice(m.BoxOffice)
+}
@@ -0,0 +1,66 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//breaker is a circuit breaker: it opens after threshold consecutive failed
+//requests, failing requests fast for cooldown, then lets a single trial
+//request through (half-open) whose outcome closes or reopens it.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+//allow reports whether a request may be sent.
+func (b *breaker) allow() bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+//record reports the outcome of an allowed request.
+func (b *breaker) record(failed bool) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+//cancel releases an allowed request which was never sent.
+func (b *breaker) cancel() {
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
+
+//upstreamFailure reports whether err means OMDB is failing, as opposed to
+//rejecting the request or the caller giving up on it.
+func upstreamFailure(err error) bool {
+	return transient(err) && !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,78 @@
+package omdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+
+	var requests, healthy int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Title":"The Matrix","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+	defer srv.Close()
+
+	cooldown := 50 * time.Millisecond
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithCircuitBreaker(2, cooldown))
+	q := QueryData{ImdbID: "tt0133093"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbID(q); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("got error %v, want the failure of OMDB", err)
+		}
+	}
+	if _, err := client.SearchByImdbID(q); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("got error %v, want ErrCircuitOpen", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("got %d requests while the breaker is open, want 2", n)
+	}
+
+	//after the cooldown a failing trial opens the breaker again.
+	time.Sleep(cooldown)
+	if _, err := client.SearchByImdbID(q); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got error %v, want the failure of the trial", err)
+	}
+	if _, err := client.SearchByImdbID(q); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("got error %v, want ErrCircuitOpen after a failed trial", err)
+	}
+
+	//a successful trial closes it.
+	time.Sleep(cooldown)
+	atomic.StoreInt32(&healthy, 1)
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbID(q); err != nil {
+			t.Fatalf("got error %v, want the breaker closed", err)
+		}
+	}
+}
+
+func TestBreakerLetsOneTrialThrough(t *testing.T) {
+
+	b := &breaker{threshold: 1, cooldown: time.Millisecond}
+	b.record(true)
+	if b.allow() {
+		t.Fatal("allowed a request while open")
+	}
+	time.Sleep(2 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("no trial after the cooldown")
+	}
+	if b.allow() {
+		t.Error("allowed a second request during the trial")
+	}
+	b.cancel()
+	if !b.allow() {
+		t.Error("no trial after the first one was cancelled")
+	}
+}
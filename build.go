@@ -0,0 +1,69 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+//BuildRequest validates q and builds the GET request the client would send
+//for op (one of OpByID, OpByTitle, OpSearch, OpSeason or OpEpisode), with the
+//API key, response format and User-Agent of the client, without sending it.
+//It lets callers inspect, sign or queue requests themselves; the response
+//body is the one the matching lookup method decodes.
+//
+//The cache, rate limiters, quota and retries of the client are not involved.
+//With a key pool the key currently in use is set, without counting the
+//request against it, unless WithCallAPIKey overrides it.
+func (c *Client) BuildRequest(ctx context.Context, op string, q QueryData, opts ...CallOption) (*http.Request, error) {
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	_, o := withCallOptions(ctx, opts)
+	if c.apiKey == "" && o.apiKey == "" {
+		return nil, &wrappedError{msg: "Missing OMDB API Key", err: ErrInvalidAPIKey}
+	}
+	q = c.withDefaults(op, q, o)
+	if op == OpByID || op == OpSeason || op == OpEpisode {
+		var err error
+		if q.ImdbID, err = normalizeID(q.ImdbID); err != nil {
+			return nil, err
+		}
+	}
+	if err := q.validate(op); err != nil {
+		return nil, err
+	}
+
+	params := queryParams(op, q)
+	if c.format != FormatJSON {
+		params.Set("r", c.format)
+	}
+
+	key := c.apiKey
+	switch {
+	case o.apiKey != "":
+		key = o.apiKey
+	case c.keys != nil:
+		var err error
+		if _, key, err = c.keys.next(); err != nil {
+			return nil, err
+		}
+	}
+	params.Set("apikey", key)
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return req, nil
+}
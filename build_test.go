@@ -0,0 +1,48 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestBuildRequest(t *testing.T) {
+
+	ctx := context.Background()
+	client := omdb.NewClient("key", nil, omdb.WithBaseURL("https://api.example.com/v1/"), omdb.WithUserAgent("omdb-test"),
+		omdb.WithDefaultPlot(omdb.PlotFull), omdb.WithResponseFormat(omdb.FormatXML))
+
+	req, err := client.BuildRequest(ctx, omdb.OpByID, omdb.QueryData{ImdbID: "0133093"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" || req.URL.Scheme != "https" || req.URL.Host != "api.example.com" || req.URL.Path != "/v1/" {
+		t.Errorf("got request %s %v", req.Method, req.URL)
+	}
+	if got, want := req.URL.RawQuery, "apikey=key&i=tt0133093&plot=full&r=xml"; got != want {
+		t.Errorf("got params %q, want %q", got, want)
+	}
+	if ua := req.Header.Get("User-Agent"); ua != "omdb-test" {
+		t.Errorf("got User-Agent %q", ua)
+	}
+	if req.Context() != ctx {
+		t.Error("the request doesn't carry ctx")
+	}
+
+	req, err = client.BuildRequest(ctx, omdb.OpSearch, omdb.QueryData{Title: "matrix", Page: "2"}, omdb.WithCallAPIKey("tenant"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := req.URL.RawQuery, "apikey=tenant&page=2&r=xml&s=matrix"; got != want {
+		t.Errorf("got params %q, want %q", got, want)
+	}
+
+	if _, err := client.BuildRequest(ctx, omdb.OpSeason, omdb.QueryData{ImdbID: "tt0903747", Season: "0"}); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for season 0, want ErrInvalidRequest", err)
+	}
+	if _, err := omdb.NewClient("", nil).BuildRequest(ctx, omdb.OpByID, omdb.QueryData{ImdbID: "tt0133093"}); !errors.Is(err, omdb.ErrInvalidAPIKey) {
+		t.Errorf("got error %v without a key, want ErrInvalidAPIKey", err)
+	}
+}
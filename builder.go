@@ -0,0 +1,204 @@
+package omdb
+
+import (
+	"net/url"
+	"strconv"
+)
+
+//QueryBuilder builds a QueryData fluently, checking each field as it is set:
+//
+//	q, err := omdb.Query().Title("Dune").Year(2021).Type(omdb.TypeMovie).FullPlot().QueryData()
+//
+//The operation of the query follows from the fields: ID for OpByID (with
+//Season for OpSeason and Episode too for OpEpisode), Title for OpByTitle and
+//Search for OpSearch. Setting a field the operation doesn't send is an error.
+//The first error is kept and reported by QueryData, Values and Err.
+type QueryBuilder struct {
+	q   QueryData
+	op  string
+	err error
+}
+
+//Query starts a QueryBuilder.
+func Query() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+//ID looks up the movie, series or episode with an IMDb id.
+func (b *QueryBuilder) ID(imdbID string) *QueryBuilder {
+	if imdbID == "" {
+		return b.fail("omdb: ImdbID is missing")
+	}
+	id, err := NormalizeImdbID(imdbID)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return b
+	}
+	b.q.ImdbID = id
+	return b.setOp(OpByID)
+}
+
+//Title looks up a movie, series or episode by its title.
+func (b *QueryBuilder) Title(title string) *QueryBuilder {
+	if title == "" {
+		return b.fail("omdb: Title is missing")
+	}
+	b.q.Title = title
+	return b.setOp(OpByTitle)
+}
+
+//Search searches titles containing text.
+func (b *QueryBuilder) Search(text string) *QueryBuilder {
+	if text == "" {
+		return b.fail("omdb: Text to search (Title) is missing")
+	}
+	b.q.Title = text
+	return b.setOp(OpSearch)
+}
+
+//Year restricts a title lookup or search to a year of release.
+func (b *QueryBuilder) Year(year int) *QueryBuilder {
+	if year < 1888 {
+		return b.fail("omdb: Year should be either blank or greater than 1887")
+	}
+	b.q.Year = strconv.Itoa(year)
+	return b
+}
+
+//Type restricts a title lookup or search to TypeMovie, TypeSeries or
+//TypeEpisode.
+func (b *QueryBuilder) Type(searchType SearchType) *QueryBuilder {
+	if !searchType.Valid() {
+		return b.fail("omdb: Searchtype should be either blank or one of following: movie, series, episode")
+	}
+	b.q.SearchType = searchType
+	return b
+}
+
+//FullPlot requests the full plot instead of the short one.
+func (b *QueryBuilder) FullPlot() *QueryBuilder {
+	b.q.Plot = PlotFull
+	return b
+}
+
+//ShortPlot requests the short plot, the default of OMDB.
+func (b *QueryBuilder) ShortPlot() *QueryBuilder {
+	b.q.Plot = PlotShort
+	return b
+}
+
+//Tomatoes requests the Rotten Tomatoes ratings along with a lookup.
+func (b *QueryBuilder) Tomatoes() *QueryBuilder {
+	b.q.Tomatoes = true
+	return b
+}
+
+//Page selects the page of search results, from 1 to 100.
+func (b *QueryBuilder) Page(page int) *QueryBuilder {
+	if page < 1 || page > 100 {
+		return b.fail("omdb: Page should be either blank or between 1 to 100 (inclusive of both)")
+	}
+	b.q.Page = strconv.Itoa(page)
+	return b
+}
+
+//Season turns a lookup by ID into the episode list of a season of the
+//series.
+func (b *QueryBuilder) Season(season int) *QueryBuilder {
+	if season < 1 {
+		return b.fail("omdb: Season should be greater than 0")
+	}
+	b.q.Season = strconv.Itoa(season)
+	return b
+}
+
+//Episode turns a lookup of a season into the lookup of one of its episodes.
+func (b *QueryBuilder) Episode(episode int) *QueryBuilder {
+	if episode < 1 {
+		return b.fail("omdb: Episode should be greater than 0")
+	}
+	b.q.Episode = strconv.Itoa(episode)
+	return b
+}
+
+//Err returns the first error of the fields set so far.
+func (b *QueryBuilder) Err() error {
+	return b.err
+}
+
+//Op returns the operation of the query, see QueryBuilder, or "" when neither
+//ID, Title nor Search was set.
+func (b *QueryBuilder) Op() string {
+	switch {
+	case b.op == OpByID && b.q.Episode != "":
+		return OpEpisode
+	case b.op == OpByID && b.q.Season != "":
+		return OpSeason
+	}
+	return b.op
+}
+
+//QueryData returns the query, once the combination of its fields is checked
+//for its operation, see Op.
+func (b *QueryBuilder) QueryData() (QueryData, error) {
+
+	if b.err != nil {
+		return QueryData{}, b.err
+	}
+
+	op := b.Op()
+	q := b.q
+	var unused string
+	switch {
+	case op == "":
+		return QueryData{}, invalidRequest("omdb: Query needs an ID, a Title or a Search")
+	case q.Episode != "" && q.Season == "":
+		return QueryData{}, invalidRequest("omdb: Episode needs a Season")
+	case op != OpByID && op != OpSeason && op != OpEpisode && q.Season != "":
+		unused = "Season"
+	case (op == OpByID || op == OpSeason || op == OpEpisode) && (q.Year != "" || q.SearchType != ""):
+		unused = "Year and Type"
+	case op != OpSearch && q.Page != "":
+		unused = "Page"
+	case (op == OpSearch || op == OpSeason) && q.Plot != "":
+		unused = "Plot"
+	case (op == OpSearch || op == OpSeason || op == OpEpisode) && q.Tomatoes:
+		unused = "Tomatoes"
+	}
+	if unused != "" {
+		return QueryData{}, invalidRequest("omdb: " + unused + " can't be used with a " + op + " query")
+	}
+
+	if err := q.validate(op); err != nil {
+		return QueryData{}, err
+	}
+	return q, nil
+}
+
+//Values returns the API parameters of the query, without the API key.
+func (b *QueryBuilder) Values() (url.Values, error) {
+	q, err := b.QueryData()
+	if err != nil {
+		return nil, err
+	}
+	return queryParams(b.Op(), q), nil
+}
+
+//setOp sets the operation of the query, which ID, Title and Search each
+//select.
+func (b *QueryBuilder) setOp(op string) *QueryBuilder {
+	if b.op != "" && b.op != op {
+		return b.fail("omdb: Only one of ID, Title or Search can be set")
+	}
+	b.op = op
+	return b
+}
+
+func (b *QueryBuilder) fail(msg string) *QueryBuilder {
+	if b.err == nil {
+		b.err = invalidRequest(msg)
+	}
+	return b
+}
@@ -0,0 +1,82 @@
+package omdb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestQueryBuilder(t *testing.T) {
+
+	for _, tt := range []struct {
+		b      *omdb.QueryBuilder
+		op     string
+		params string
+	}{
+		{omdb.Query().ID("0133093").Tomatoes().FullPlot(), omdb.OpByID, "i=tt0133093&plot=full&tomatoes=true"},
+		{omdb.Query().Title("Dune").Year(2021).Type(omdb.TypeMovie).ShortPlot(), omdb.OpByTitle, "plot=short&t=Dune&type=movie&y=2021"},
+		{omdb.Query().Search("matrix").Type(omdb.TypeSeries).Page(2), omdb.OpSearch, "page=2&s=matrix&type=series"},
+		{omdb.Query().ID("tt0903747").Season(2), omdb.OpSeason, "Season=2&i=tt0903747"},
+		{omdb.Query().ID("tt0903747").Season(2).Episode(3), omdb.OpEpisode, "Episode=3&Season=2&i=tt0903747"},
+	} {
+		params, err := tt.b.Values()
+		if err != nil {
+			t.Errorf("%s: %v", tt.params, err)
+			continue
+		}
+		if got := params.Encode(); got != tt.params {
+			t.Errorf("got params %q, want %q", got, tt.params)
+		}
+		if tt.b.Op() != tt.op {
+			t.Errorf("%s: got op %q, want %q", tt.params, tt.b.Op(), tt.op)
+		}
+	}
+}
+
+func TestQueryBuilderErrors(t *testing.T) {
+
+	for _, tt := range []struct {
+		b   *omdb.QueryBuilder
+		err string
+	}{
+		{omdb.Query(), "needs an ID, a Title or a Search"},
+		{omdb.Query().ID(""), "ImdbID is missing"},
+		{omdb.Query().ID("matrix"), "matrix"},
+		{omdb.Query().Title(""), "Title is missing"},
+		{omdb.Query().Search(""), "Text to search (Title) is missing"},
+		{omdb.Query().Title("Dune").Year(1887), "Year should be"},
+		{omdb.Query().Title("Dune").Type("game"), "Searchtype should be"},
+		{omdb.Query().Search("dune").Page(0), "Page should be"},
+		{omdb.Query().Search("dune").Page(101), "Page should be"},
+		{omdb.Query().ID("tt0903747").Season(0), "Season should be greater than 0"},
+		{omdb.Query().ID("tt0903747").Season(1).Episode(0), "Episode should be greater than 0"},
+		{omdb.Query().ID("tt0903747").Episode(1), "Episode needs a Season"},
+		{omdb.Query().Title("Dune").Search("dune"), "Only one of ID, Title or Search"},
+		{omdb.Query().Title("Dune").Season(1), "Season can't be used with a " + omdb.OpByTitle},
+		{omdb.Query().ID("tt0133093").Year(1999), "Year and Type can't be used"},
+		{omdb.Query().Title("Dune").Page(2), "Page can't be used"},
+		{omdb.Query().Search("dune").FullPlot(), "Plot can't be used"},
+		{omdb.Query().ID("tt0903747").Season(1).Tomatoes(), "Tomatoes can't be used"},
+		//the first error is kept.
+		{omdb.Query().Title("").Year(0), "Title is missing"},
+	} {
+		_, err := tt.b.QueryData()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("got error %v, want %q", err, tt.err)
+			continue
+		}
+		if !errors.Is(err, omdb.ErrInvalidRequest) {
+			t.Errorf("%v doesn't match ErrInvalidRequest", err)
+		}
+		if _, verr := tt.b.Values(); verr == nil {
+			t.Errorf("%q: Values returned no error", tt.err)
+		}
+	}
+
+	//Err reports the errors of the fields as they are set.
+	if err := omdb.Query().Year(1).Err(); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v from Err, want ErrInvalidRequest", err)
+	}
+}
@@ -0,0 +1,250 @@
+package omdb
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//DefaultCacheTTL is how long responses are cached when WithCache is given no
+//ttl.
+const DefaultCacheTTL = 24 * time.Hour
+
+//ErrCacheMiss is returned by Cache.Get when there is no entry for a key.
+var ErrCacheMiss = errors.New("omdb: cache miss")
+
+//Cache stores raw API responses by the keys described in CacheKey.
+//Implementations must be safe for concurrent use.
+type Cache interface {
+	//Get returns the value stored for key, or ErrCacheMiss when there is
+	//none or it expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	//Set stores value for key, it expires after ttl. A ttl of 0 means the
+	//value never expires, a negative ttl that it is expired already.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	//Delete removes the value stored for key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+//LRUCache is an in-memory Cache holding a bounded number of entries, the
+//least recently used entry is evicted to make room for a new one.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+	//expires is zero for an entry which never expires.
+	expires time.Time
+}
+
+//NewLRUCache creates an LRUCache holding up to size entries.
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+//Get implements Cache.
+func (l *LRUCache) Get(ctx context.Context, key string) ([]byte, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	entry := e.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.remove(e)
+		return nil, ErrCacheMiss
+	}
+
+	l.ll.MoveToFront(e)
+	return entry.value, nil
+}
+
+//Set implements Cache.
+func (l *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	//the zero time never expires.
+	var expires time.Time
+	if ttl != 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if e, ok := l.entries[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		l.ll.MoveToFront(e)
+		return nil
+	}
+
+	l.entries[key] = l.ll.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for l.ll.Len() > l.size {
+		l.remove(l.ll.Back())
+	}
+	return nil
+}
+
+//Delete implements Cache.
+func (l *LRUCache) Delete(ctx context.Context, key string) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok {
+		l.remove(e)
+	}
+	return nil
+}
+
+//Len returns the number of entries in the cache, expired ones included.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
+
+func (l *LRUCache) remove(e *list.Element) {
+	l.ll.Remove(e)
+	delete(l.entries, e.Value.(*lruEntry).key)
+}
+
+//entryHeader prefixes the values cached in stale-while-revalidate or
+//conditional requests mode. It is followed by the query encoded time until
+//which the value is fresh (unix nanoseconds) and its validators, then a
+//newline and the response body.
+const entryHeader = "omdb-entry:"
+
+//CachedBody returns the response body of a value the client stored in a
+//Cache, without the header it is prefixed with in stale-while-revalidate or
+//conditional requests mode. Cache implementations can use it to index the
+//responses they store.
+func CachedBody(value []byte) []byte {
+	if !bytes.HasPrefix(value, []byte(entryHeader)) {
+		return value
+	}
+	if i := bytes.IndexByte(value, '\n'); i >= 0 {
+		return value[i+1:]
+	}
+	return nil
+}
+
+//cacheEntry is a cached response.
+type cacheEntry struct {
+	body         []byte
+	fresh        time.Time
+	etag         string
+	lastModified string
+}
+
+//expired reports whether the entry outlived its ttl. Entries without a fresh
+//time never expire, the cache removes them.
+func (e *cacheEntry) expired() bool {
+	return !e.fresh.IsZero() && time.Now().After(e.fresh)
+}
+
+//cached returns the entry cached for key.
+func (c *Client) cached(ctx context.Context, key string) (*cacheEntry, error) {
+
+	data, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	//plain values are stored when the entries don't outlive their ttl, or
+	//were stored before that was enabled.
+	if !bytes.HasPrefix(data, []byte(entryHeader)) {
+		return &cacheEntry{body: data}, nil
+	}
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 {
+		return nil, ErrCacheMiss
+	}
+	header, err := url.ParseQuery(string(data[len(entryHeader):i]))
+	if err != nil {
+		return nil, ErrCacheMiss
+	}
+	fresh, err := strconv.ParseInt(header.Get("fresh"), 10, 64)
+	if err != nil {
+		return nil, ErrCacheMiss
+	}
+
+	return &cacheEntry{
+		body:         data[i+1:],
+		fresh:        time.Unix(0, fresh),
+		etag:         header.Get("etag"),
+		lastModified: header.Get("lm"),
+	}, nil
+}
+
+//store caches entry under key for ttl. When stale entries are served or
+//revalidated, the entry is kept for that extra period.
+func (c *Client) store(ctx context.Context, key string, entry *cacheEntry, ttl time.Duration) {
+
+	data := entry.body
+	if keep := c.staleTTL + c.keepTTL; keep > 0 {
+		header := url.Values{}
+		header.Set("fresh", strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10))
+		if entry.etag != "" {
+			header.Set("etag", entry.etag)
+		}
+		if entry.lastModified != "" {
+			header.Set("lm", entry.lastModified)
+		}
+		data = append([]byte(entryHeader+header.Encode()+"\n"), entry.body...)
+		ttl += keep
+	}
+
+	//a failing cache only costs a future request.
+	_ = c.cache.Set(ctx, key, data, ttl)
+}
+
+//revalidate refreshes the expired entry of key in the background, unless it
+//is already being refreshed. The refresh keeps the call options of ctx, like
+//the API key of WithCallAPIKey, but not its cancellation: the caller's
+//context may be done long before it completes.
+func (c *Client) revalidate(ctx context.Context, key string, params url.Values, entry *cacheEntry) {
+
+	if _, loaded := c.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	//the meta of the caller was already set for the stale entry.
+	o := callOptionsFrom(ctx)
+	o.meta = nil
+	ctx = context.WithValue(context.WithoutCancel(ctx), callOptionsKey{}, o)
+
+	//request adds the API key to params, which belong to the caller.
+	p := url.Values{}
+	for k, v := range params {
+		p[k] = append([]string(nil), v...)
+	}
+
+	go func() {
+		defer c.refreshing.Delete(key)
+		if c.keepTTL > 0 {
+			_, _ = c.fetch(ctx, key, p, entry)
+		} else {
+			_, _ = c.fetch(ctx, key, p, nil)
+		}
+	}()
+}
@@ -0,0 +1,83 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestLRUCache(t *testing.T) {
+
+	ctx := context.Background()
+	cache := omdb.NewLRUCache(2)
+	cache.Set(ctx, "a", []byte("1"), time.Hour)
+	cache.Set(ctx, "b", []byte("2"), time.Hour)
+
+	//reading a makes b the least recently used entry.
+	if data, err := cache.Get(ctx, "a"); err != nil || string(data) != "1" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	cache.Set(ctx, "c", []byte("3"), time.Hour)
+	if _, err := cache.Get(ctx, "b"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v for the evicted entry, want ErrCacheMiss", err)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("got %d entries, want 2", cache.Len())
+	}
+
+	cache.Set(ctx, "a", []byte("expired"), -time.Second)
+	if _, err := cache.Get(ctx, "a"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v for an expired entry, want ErrCacheMiss", err)
+	}
+	cache.Delete(ctx, "c")
+	if _, err := cache.Get(ctx, "c"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v after Delete, want ErrCacheMiss", err)
+	}
+
+	//a ttl of 0 never expires.
+	cache.Set(ctx, "d", []byte("4"), 0)
+	if data, err := cache.Get(ctx, "d"); err != nil || string(data) != "4" {
+		t.Errorf("got %q, %v for an entry without a ttl", data, err)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+
+	ctx := context.Background()
+	client := srv.Client(omdb.WithCache(omdb.NewLRUCache(10), time.Hour))
+	q := omdb.QueryData{ImdbID: "tt0133093"}
+	for i := 0; i < 3; i++ {
+		if _, err := client.SearchByImdbIDContext(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+
+	if _, err := client.SearchByImdbIDContext(ctx, q, omdb.WithNoCache()); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("got %d requests with WithNoCache, want 2", n)
+	}
+
+	//errors are not cached without negative caching.
+	missing := omdb.QueryData{ImdbID: "tt0000001"}
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbIDContext(ctx, missing); !errors.Is(err, omdb.ErrNotFound) {
+			t.Fatalf("got error %v, want ErrNotFound", err)
+		}
+	}
+	if n := srv.Requests(); n != 4 {
+		t.Errorf("got %d requests, want the not found lookup sent twice", n)
+	}
+}
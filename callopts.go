@@ -0,0 +1,92 @@
+package omdb
+
+import (
+	"context"
+	"time"
+)
+
+//CallOption overrides a default of the client for a single request. Unlike
+//an Option it never changes the Client, so calls with different CallOptions
+//can share a client concurrently.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	noCache bool
+	timeout time.Duration
+	plot    Plot
+	workers int
+	max     int
+	meta    *ResponseMeta
+	apiKey  string
+}
+
+type callOptionsKey struct{}
+
+//WithNoCache bypasses the cache of the client: the response is always
+//requested from the API. A successful response still replaces the cached
+//one.
+func WithNoCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+//WithCallTimeout replaces the timeout of the client (see WithTimeout) for a
+//single request.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+//WithCallAPIKey sends the request with key instead of the API key, or key
+//pool, of the client, e.g. the key of a tenant of a multi-tenant service.
+//The cache, rate limiters and quota of the client still apply; concurrent
+//identical calls only share a request when they use the same key.
+func WithCallAPIKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.apiKey = key
+	}
+}
+
+//WithFullPlot requests the full plot instead of the short one, overriding
+//QueryData.Plot.
+func WithFullPlot() CallOption {
+	return func(o *callOptions) {
+		o.plot = PlotFull
+	}
+}
+
+//WithWorkers sets the number of concurrent requests of a batch call like
+//GetByIDs.
+func WithWorkers(n int) CallOption {
+	return func(o *callOptions) {
+		o.workers = n
+	}
+}
+
+//WithMaxResults caps the number of results of SearchAll.
+func WithMaxResults(n int) CallOption {
+	return func(o *callOptions) {
+		o.max = n
+	}
+}
+
+//withCallOptions applies opts and binds them to ctx, so the request pipeline
+//can find them without threading them through every call.
+func withCallOptions(ctx context.Context, opts []CallOption) (context.Context, callOptions) {
+
+	o := callOptionsFrom(ctx)
+	if len(opts) == 0 {
+		return ctx, o
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o), o
+}
+
+func callOptionsFrom(ctx context.Context) callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return o
+}
@@ -0,0 +1,60 @@
+package omdb
+
+import "context"
+
+//MediaCard is a minimal, type independent summary of a movie, series or
+//episode, suitable for rendering without a type switch on the result.
+//Values OMDB reports as "N/A" are left empty (or zero for Rating).
+type MediaCard struct {
+	Title     string
+	Year      string
+	PosterURL string
+	Rating    float64
+	Type      string
+	ImdbID    string
+}
+
+//Card looks up a single movie, series or episode and returns its MediaCard.
+//The lookup is done by q.ImdbID when set, otherwise by q.Title. A result
+//which isn't a movie, series or episode fails with ErrTypeMismatch.
+func (c *Client) Card(ctx context.Context, q QueryData, opts ...CallOption) (*MediaCard, error) {
+
+	var (
+		res interface{}
+		err error
+	)
+	if q.ImdbID != "" {
+		res, err = c.SearchByImdbIDContext(ctx, q, opts...)
+	} else {
+		res, err = c.SearchByTitleContext(ctx, q, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(Result)
+	typed, typedOK := res.(typedResult)
+	if !ok || !typedOK || isNilResult(r) {
+		return nil, typeMismatch("movie, series or episode", res)
+	}
+
+	card := &MediaCard{
+		Title:  notAvailable(r.TitleName()),
+		Year:   notAvailable(r.YearOf()),
+		Rating: typed.Typed().ImdbRating,
+		Type:   TypeOf(res),
+		ImdbID: notAvailable(r.IMDBID()),
+	}
+	if poster, err := r.PosterURL(); err == nil {
+		card.PosterURL = poster.String()
+	}
+	return card, nil
+}
+
+//notAvailable maps OMDB's "N/A" placeholder to an empty string.
+func notAvailable(s string) string {
+	if s == "N/A" {
+		return ""
+	}
+	return s
+}
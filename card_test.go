@@ -0,0 +1,70 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+//cardServer serves the movie and series bodies by id or title.
+func cardServer(t *testing.T) *omdb.Client {
+
+	t.Helper()
+	bodies := map[string]string{
+		"tt0133093": `{"Title":"The Matrix","Year":"1999","imdbID":"tt0133093","imdbRating":"8.7","Poster":"https://example.com/matrix.jpg","Type":"movie","Response":"True"}`,
+		"Severance": `{"Title":"Severance","Year":"2022–","imdbID":"tt11280740","imdbRating":"N/A","Poster":"N/A","Type":"series","Response":"True"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, ok := bodies[r.URL.Query().Get("i")+r.URL.Query().Get("t")]
+		if !ok {
+			body = `{"Response":"False","Error":"Movie not found!"}`
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return omdb.NewClient("key", srv.Client(), omdb.WithBaseURL(srv.URL+"/"))
+}
+
+func TestCard(t *testing.T) {
+
+	client := cardServer(t)
+	ctx := context.Background()
+
+	card, err := client.Card(ctx, omdb.QueryData{ImdbID: "tt0133093"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := omdb.MediaCard{Title: "The Matrix", Year: "1999", PosterURL: "https://example.com/matrix.jpg", Rating: 8.7, Type: "movie", ImdbID: "tt0133093"}
+	if *card != want {
+		t.Errorf("got %+v, want %+v", *card, want)
+	}
+
+	card, err = client.Card(ctx, omdb.QueryData{Title: "Severance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = omdb.MediaCard{Title: "Severance", Year: "2022–", Type: "series", ImdbID: "tt11280740"}
+	if *card != want {
+		t.Errorf("got %+v, want %+v", *card, want)
+	}
+}
+
+func TestCardUnknownType(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Title":"Game","imdbID":"tt0000001","Type":"game","Response":"True"}`))
+	}))
+	defer srv.Close()
+	client := omdb.NewClient("key", srv.Client(), omdb.WithBaseURL(srv.URL+"/"))
+
+	card, err := client.Card(context.Background(), omdb.QueryData{ImdbID: "tt0000001"})
+	if card != nil || !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("got %+v, %v, want ErrTypeMismatch", card, err)
+	}
+}
@@ -1,223 +1,803 @@
 package omdb
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
 	// DefaultURL is the default request URL for API requests.
-	DefaultURL = "http://www.omdbapi.com/"
+	DefaultURL = "https://www.omdbapi.com/"
+
+	// DefaultPosterURL is the default request URL for the Poster API.
+	DefaultPosterURL = "https://img.omdbapi.com/"
+
+	// DefaultTimeout is the deadline applied to a request when neither the
+	// http.Client nor the request context sets one.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxResponseSize is the largest response body read from the API,
+	// far above the few kilobytes of an OMDB response.
+	DefaultMaxResponseSize = 1 << 20
 )
 
 //Client is a omdb client.
+//
+//A Client is safe for concurrent use by multiple goroutines and is meant to
+//be shared: its cache, rate limiters, quota, key pool, circuit breaker and
+//retry budget are synchronized internally, and concurrent identical lookups
+//share a single request. Its settings are fixed once NewClient or New
+//returns; use Clone or CallOptions for different settings. The Cache,
+//Metrics, Tracer, Middleware and Logger given to it are called from several
+//goroutines at once.
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey         string
+	httpClient     *http.Client
+	baseURL        string
+	posterURL      string
+	userAgent      string
+	timeout        time.Duration
+	defaultTimeout time.Duration
+	maxBodySize    int64
+	lenientDecode  bool
+	emptyNA        bool
+	keepRaw        bool
+	strictDecode   bool
+	debug          *debugLog
+	logger         *slog.Logger
+	middleware     []Middleware
+	metrics        Metrics
+	tracer         Tracer
+	httpTrace      bool
+	format         string
+
+	//cache settings, see WithCache, WithNegativeCache,
+	//WithStaleWhileRevalidate and WithConditionalRequests.
+	cache       Cache
+	cacheTTL    time.Duration
+	negativeTTL time.Duration
+	staleTTL    time.Duration
+	keepTTL     time.Duration
+
+	//refreshing holds the cache keys being revalidated in the background.
+	refreshing sync.Map
+
+	//flights coalesces identical concurrent requests.
+	flights flightGroup
+
+	//limiters each have to allow a request before it is sent, which is then
+	//counted by quota.
+	limiters []*Limiter
+	quota    *QuotaTracker
+
+	//keys is the key pool given to WithAPIKeys, nil when apiKey is the
+	//only key.
+	keys *keyPool
+
+	retry       RetryPolicy
+	retryBudget retryBudget
+	breaker     *breaker
+	hedgeDelay  time.Duration
+
+	//defaultSearchType is used by SearchByTitle and SearchByText when the
+	//query has no SearchType, defaultPlot by the lookups when it has no Plot.
+	defaultSearchType SearchType
+	defaultPlot       Plot
+
+	//err holds the first error returned by an Option, it is reported by every
+	//request made with the client.
+	err error
 }
 
-//NewClient creates a new omdb Client.
-func NewClient(key string, client *http.Client) *Client {
-	return &Client{
-		apiKey:     key,
-		httpClient: client,
+//NewClient creates a new omdb Client sending its requests with client, or
+//with a default http.Client when client is nil (see DefaultHTTPClient).
+func NewClient(key string, client *http.Client, opts ...Option) *Client {
+	c := newClient(key)
+	c.httpClient = client
+	if client == nil {
+		c.httpClient = defaultHTTPClient
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil && c.err == nil {
+			c.err = err
+		}
 	}
+	return c
 }
 
-//requestOmdbAPI will call the OMDB API
-func (c *Client) requestOmdbAPI(params url.Values) (*http.Response, error) {
+//New creates a new omdb Client configured by opts. Unlike NewClient it
+//checks the format of key and the options up front and returns their error.
+//DefaultHTTPClient is used unless WithHTTPClient is given.
+func New(key string, opts ...Option) (*Client, error) {
 
-	if c.httpClient == nil {
-		return nil, errors.New("http.Client is not provided")
+	if err := ValidateKeyFormat(key); err != nil {
+		return nil, err
 	}
-	if c.apiKey == "" {
-		return nil, errors.New("Missing OMDB API Key")
+
+	c := newClient(key)
+	c.httpClient = defaultHTTPClient
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+//Clone returns a copy of c with opts applied, e.g. WithAPIKey, WithTimeout or
+//WithDefaultPlot for another tenant or feature. The copy shares the
+//http.Client, cache, rate limiters, quota, key pool and circuit breaker of c,
+//so it is cheap to create per request. Like with NewClient, an error of opts
+//is reported by every request made with the copy.
+func (c *Client) Clone(opts ...Option) *Client {
+
+	//the state of in-flight requests, revalidations and the retry budget is
+	//not copied.
+	cp := &Client{
+		apiKey:            c.apiKey,
+		httpClient:        c.httpClient,
+		baseURL:           c.baseURL,
+		posterURL:         c.posterURL,
+		userAgent:         c.userAgent,
+		timeout:           c.timeout,
+		defaultTimeout:    c.defaultTimeout,
+		maxBodySize:       c.maxBodySize,
+		lenientDecode:     c.lenientDecode,
+		emptyNA:           c.emptyNA,
+		keepRaw:           c.keepRaw,
+		strictDecode:      c.strictDecode,
+		debug:             c.debug,
+		logger:            c.logger,
+		middleware:        append([]Middleware(nil), c.middleware...),
+		metrics:           c.metrics,
+		tracer:            c.tracer,
+		httpTrace:         c.httpTrace,
+		format:            c.format,
+		cache:             c.cache,
+		cacheTTL:          c.cacheTTL,
+		negativeTTL:       c.negativeTTL,
+		staleTTL:          c.staleTTL,
+		keepTTL:           c.keepTTL,
+		limiters:          append([]*Limiter(nil), c.limiters...),
+		quota:             c.quota,
+		keys:              c.keys,
+		retry:             c.retry,
+		breaker:           c.breaker,
+		hedgeDelay:        c.hedgeDelay,
+		defaultSearchType: c.defaultSearchType,
+		defaultPlot:       c.defaultPlot,
+		err:               c.err,
+	}
+	for _, opt := range opts {
+		if err := opt(cp); err != nil && cp.err == nil {
+			cp.err = err
+		}
+	}
+	return cp
+}
+
+func newClient(key string) *Client {
+	return &Client{
+		apiKey:         key,
+		baseURL:        DefaultURL,
+		posterURL:      DefaultPosterURL,
+		format:         FormatJSON,
+		defaultTimeout: DefaultTimeout,
+		maxBodySize:    DefaultMaxResponseSize,
+	}
+}
+
+//Quota returns the QuotaTracker given to WithDailyQuota, or nil.
+func (c *Client) Quota() *QuotaTracker {
+	return c.quota
+}
+
+//Do requests the API with arbitrary params, e.g. ones the typed methods
+//don't support yet, and unmarshals the response into into, using XML or JSON
+//depending on the response format of the client. The API key is added to
+//params, and the cache, rate limiters, quota and retries of the client apply
+//as with any other request. An error response of OMDB is returned as an
+//APIError. into may be nil to only check the response.
+func (c *Client) Do(ctx context.Context, params url.Values, into interface{}, opts ...CallOption) error {
+
+	//requestOmdbAPI modifies its params.
+	cp := url.Values{}
+	for k, v := range params {
+		if k != "apikey" {
+			cp[k] = append([]string(nil), v...)
+		}
 	}
-	params.Set("apikey", c.apiKey)
 
-	url, err := url.Parse(DefaultURL)
+	ctx, _ = withCallOptions(ctx, opts)
+	data, err := c.requestOmdbAPI(ctx, cp)
 	if err != nil {
+		return err
+	}
+	if into == nil {
+		return nil
+	}
+	if c.format == FormatXML {
+		return xml.Unmarshal(data, into)
+	}
+	return json.Unmarshal(data, into)
+}
+
+//requestOmdbAPI will call the OMDB API and return the response body. The
+//cache of the client is consulted first and filled with successful responses
+//and, with negative caching, not found responses; identical concurrent calls
+//missing the cache share a single request. An error response of OMDB is
+//returned as an APIError.
+func (c *Client) requestOmdbAPI(ctx context.Context, params url.Values) (data []byte, err error) {
+
+	if c.format != FormatJSON {
+		params.Set("r", c.format)
+	}
+
+	key := params.Encode()
+	ctx, span := c.startSpan(ctx, params)
+	defer func(start time.Time) {
+		c.observe(key, start, err)
+		span.End(err)
+	}(time.Now())
+	if c.cache == nil || callOptionsFrom(ctx).noCache {
+		return c.fetchShared(ctx, key, params, nil)
+	}
+
+	entry, err := c.cached(ctx, key)
+	switch {
+	case err != nil:
+		c.log(ctx, slog.LevelDebug, "omdb cache miss", slog.String("cache_key", key))
+		c.observeCache(key, false)
+		span.CacheHit(false)
+		return c.fetchShared(ctx, key, params, nil)
+	case !entry.expired():
+		c.log(ctx, slog.LevelDebug, "omdb cache hit", slog.String("cache_key", key))
+		c.observeCache(key, true)
+		span.CacheHit(true)
+		setMeta(ctx, ResponseMeta{FromCache: true})
+	case c.staleTTL > 0:
+		c.log(ctx, slog.LevelDebug, "omdb cache hit", slog.String("cache_key", key), slog.Bool("stale", true))
+		c.observeCache(key, true)
+		span.CacheHit(true)
+		setMeta(ctx, ResponseMeta{FromCache: true, Stale: true})
+		c.revalidate(ctx, key, params, entry)
+	default:
+		c.log(ctx, slog.LevelDebug, "omdb cache miss", slog.String("cache_key", key), slog.Bool("expired", true))
+		c.observeCache(key, false)
+		span.CacheHit(false)
+		return c.fetchShared(ctx, key, params, entry)
+	}
+
+	//negatively cached entries hold the error response.
+	if err := c.responseError(entry.body); err != nil {
 		return nil, err
 	}
+	return entry.body, nil
+}
 
-	url.RawQuery = params.Encode()
+//fetch requests params from the API and caches the response under key. When
+//prev is an expired cache entry with validators, the request is conditional
+//and prev is refreshed if the response is unchanged.
+func (c *Client) fetch(ctx context.Context, key string, params url.Values, prev *cacheEntry) ([]byte, error) {
+
+	var header http.Header
+	if prev != nil && (prev.etag != "" || prev.lastModified != "") {
+		header = http.Header{}
+		if prev.etag != "" {
+			header.Set("If-None-Match", prev.etag)
+		}
+		if prev.lastModified != "" {
+			header.Set("If-Modified-Since", prev.lastModified)
+		}
+	}
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	res, err := c.request(ctx, c.baseURL, params, header)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.httpClient.Do(req)
-	if err != nil {
+	defer res.Body.Close()
+	entry := &cacheEntry{
+		etag:         res.Header.Get("ETag"),
+		lastModified: res.Header.Get("Last-Modified"),
+	}
+	if res.StatusCode == http.StatusNotModified {
+		entry.body = prev.body
+		if entry.etag == "" && entry.lastModified == "" {
+			entry.etag, entry.lastModified = prev.etag, prev.lastModified
+		}
+	} else {
+		entry.body, err = c.readBody(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.responseError(entry.body); err != nil {
+		if c.cache != nil && c.negativeTTL > 0 && errors.Is(err, ErrNotFound) {
+			c.store(ctx, key, entry, c.negativeTTL)
+		}
 		return nil, err
 	}
-	if res.StatusCode != http.StatusOK {
-		defer res.Body.Close()
-		return nil, fmt.Errorf("http Status = %d", res.StatusCode)
+
+	if c.cache != nil {
+		c.store(ctx, key, entry, c.cacheTTL)
 	}
 
-	return res, nil
+	return entry.body, nil
 }
 
-//SearchByImdbID performs an API search for a specified movie or series or episode by
-//the specific imdb id. Although OMDB API allows passing other parameters like Year, SearchType etc
-//but they are ignored here as search is done on a unique id.
-func (c *Client) SearchByImdbID(q QueryData) (interface{}, error) {
+//readBody reads the body of res. A JSON body is decoded from the stream,
+//which validates it as it is read and stops at the end of the response; the
+//raw value is kept for the decoding of the result and the cache. A body
+//larger than the maximum response size of the client is an error matching
+//ErrResponseTooLarge, it is never read past that size.
+func (c *Client) readBody(res *http.Response) ([]byte, error) {
 
-	if q.ImdbID == "" {
-		return nil, errors.New("Missing ImdbID in query")
+	if res.ContentLength > c.maxBodySize {
+		return nil, responseTooLarge(c.maxBodySize)
 	}
+	body := &countingReader{r: io.LimitReader(res.Body, c.maxBodySize+1)}
 
-	params := url.Values{}
-	params.Add("i", q.ImdbID)
+	if c.format == FormatJSON {
+		var raw json.RawMessage
+		err := json.NewDecoder(body).Decode(&raw)
+		if body.n > c.maxBodySize {
+			return nil, responseTooLarge(c.maxBodySize)
+		}
+		var syntaxErr *json.SyntaxError
+		if err == io.EOF || err == io.ErrUnexpectedEOF || errors.As(err, &syntaxErr) {
+			return nil, errors.New("omdb: Invalid JSON response")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
 
-	res, err := c.requestOmdbAPI(params)
-	if err != nil {
+	var buf bytes.Buffer
+	if res.ContentLength > 0 {
+		buf.Grow(int(res.ContentLength))
+	}
+	if _, err := buf.ReadFrom(body); err != nil {
 		return nil, err
 	}
+	if body.n > c.maxBodySize {
+		return nil, responseTooLarge(c.maxBodySize)
+	}
+	return buf.Bytes(), nil
+}
+
+//countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func responseTooLarge(limit int64) error {
+	return &wrappedError{msg: "omdb: Response larger than " + strconv.FormatInt(limit, 10) + " bytes", err: ErrResponseTooLarge}
+}
+
+//responseError returns the error reported in the response envelope of data,
+//or nil when the response is successful.
+func (c *Client) responseError(data []byte) error {
+
+	if c.format == FormatXML {
+		_, err := decodeXML(data)
+		return err
+	}
 
-	defer res.Body.Close()
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	//only error responses hold "False", every other payload is just
+	//validated and left to the decoding of its result.
+	if !bytes.Contains(data, []byte(`"False"`)) {
+		if !json.Valid(data) {
+			return errors.New("omdb: Invalid JSON response")
+		}
+		return nil
 	}
 
-	envelope := &resultEnvelope{}
-	err = json.Unmarshal(data, envelope)
+	envelope := resultEnvelope{}
+	err := json.Unmarshal(data, &envelope)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if envelope.Response == "False" {
-		return nil, errors.New("Error from OMDB API: " + envelope.Error)
+		return &APIError{Message: envelope.Error}
 	}
+	return nil
+}
 
-	var val interface{}
+//request sends a GET request with params and the API key to baseURL, applying
+//the timeouts and retries of the client. header is added to the request, a
+//304 response is only accepted when it holds validators.
+func (c *Client) request(ctx context.Context, baseURL string, params url.Values, header http.Header) (*http.Response, error) {
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.httpClient == nil {
+		return nil, errors.New("http.Client is not provided")
+	}
+	if key := callOptionsFrom(ctx).apiKey; key != "" {
+		params.Set("apikey", key)
+		return c.send(ctx, baseURL, params, header)
+	}
+	if c.apiKey == "" {
+		return nil, &wrappedError{msg: "Missing OMDB API Key", err: ErrInvalidAPIKey}
+	}
 
-	switch envelope.Type {
+	if c.keys == nil {
+		params.Set("apikey", c.apiKey)
+		return c.send(ctx, baseURL, params, header)
+	}
 
-	case "movie":
-		movie := MovieResult{}
-		err = json.Unmarshal(data, &movie)
+	//rotate through the key pool until a key is accepted.
+	for {
+		i, key, err := c.keys.next()
 		if err != nil {
 			return nil, err
 		}
-		val = movie
+		params.Set("apikey", key)
+		res, err := c.send(ctx, baseURL, params, header)
+		c.keys.count(i)
+		if errors.Is(err, ErrDailyLimitExceeded) || errors.Is(err, ErrInvalidAPIKey) {
+			c.keys.exhaust(i)
+			continue
+		}
+		return res, err
+	}
+}
 
-	case "series":
-		series := SeriesResult{}
-		err = json.Unmarshal(data, &series)
-		if err != nil {
-			return nil, err
+//send sends the request of request with the API key already in params.
+func (c *Client) send(ctx context.Context, baseURL string, params url.Values, header http.Header) (res *http.Response, err error) {
+
+	url, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	url.RawQuery = params.Encode()
+
+	//the timeout starts once the rate limiters let the first attempt through.
+	waitCtx := ctx
+	cancel := context.CancelFunc(func() {})
+
+	//sent tells the circuit breaker whether OMDB was reached at all.
+	var sent bool
+
+	if c.breaker != nil {
+		if !c.breaker.allow() {
+			return nil, ErrCircuitOpen
 		}
-		val = series
+		defer func() {
+			if sent {
+				c.breaker.record(upstreamFailure(err))
+			} else {
+				c.breaker.cancel()
+			}
+		}()
+	}
 
-	case "episode":
-		episode := EpisodeResult{}
-		err = json.Unmarshal(data, &episode)
-		if err != nil {
+	if c.retry.Budget > 0 {
+		c.retryBudget.deposit(c.retry.Budget)
+	}
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		if err := waitAll(waitCtx, c.limiters); err != nil {
+			cancel()
 			return nil, err
 		}
-		val = episode
+		if wait := time.Since(start); len(c.limiters) > 0 && wait > time.Millisecond {
+			c.log(ctx, slog.LevelDebug, "omdb rate limit wait", slog.Duration("wait", wait))
+		}
+		if c.quota != nil {
+			if err := c.quota.take(waitCtx); err != nil {
+				for _, l := range c.limiters {
+					l.refund()
+				}
+				cancel()
+				return nil, err
+			}
+		}
+		if attempt == 0 {
+			ctx, cancel = c.withTimeout(ctx)
+			waitCtx = ctx
+		}
+		c.logStart(ctx, url.String(), attempt)
+		start = time.Now()
+		if c.hedgeDelay > 0 {
+			res, err = c.hedgedDo(ctx, url.String(), header)
+		} else {
+			res, err = c.do(ctx, url.String(), header)
+		}
+		sent = true
+		c.logResult(ctx, url.String(), attempt, start, res, err)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			break
+		}
+		if c.retry.Budget > 0 && !c.retryBudget.withdraw() {
+			break
+		}
+		backoff := c.retry.backoff(attempt)
+		c.log(ctx, slog.LevelInfo, "omdb request retry", slog.Int("attempt", attempt+1), slog.Duration("backoff", backoff), slog.String("error", err.Error()))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
 	}
 
-	return val, nil
+	//the deadline has to outlive this function as the caller reads the body.
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+
+	return res, nil
 }
 
-//SearchByTitle performs an API search for a specified movie or series or episode by
-//the specific title.
-//Currently OMDB API seems to be returning only movie information, on a title
-//based search. Year parameter has to be greater tha nor equal to 1888 (trivia: which
-//is the world's earliest surviving motion-picture film?)
-func (c *Client) SearchByTitle(q QueryData) (interface{}, error) {
+//withTimeout applies the timeout of the call or of the client, or the default
+//timeout when neither ctx nor the http.Client have one.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := callOptionsFrom(ctx).timeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	if _, ok := ctx.Deadline(); !ok && c.httpClient.Timeout == 0 && c.defaultTimeout > 0 {
+		return context.WithTimeout(ctx, c.defaultTimeout)
+	}
+	return ctx, func() {}
+}
 
-	params := url.Values{}
+//do sends a single GET request to url, any status but 200 (or 304 for a
+//conditional request) is an error.
+func (c *Client) do(ctx context.Context, url string, header http.Header) (*http.Response, error) {
 
-	if q.Title == "" {
-		return nil, errors.New("omdb: Title is missing")
+	var timer *requestTimer
+	if c.httpTrace {
+		ctx, timer = withTimer(ctx)
 	}
-	params.Add("t", q.Title)
 
-	if q.SearchType != "" && q.SearchType != "movie" && q.SearchType != "series" && q.SearchType != "episode" {
-		return nil, errors.New("omdb: Searchtype should be either blank or one of following: movie, series, episode")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
-	if q.SearchType != "" {
-		params.Add("type", q.SearchType)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	if q.Year != "" {
-		i, err := strconv.Atoi(q.Year)
-		if err != nil {
-			return nil, errors.New("omdb: Year should be either blank or a valid number")
-		}
-		if i < 1888 {
-			return nil, errors.New("omdb: Year should be either blank or greater than 1887")
+	start := time.Now()
+	res, err := c.roundTrip(req)
+	if c.debug != nil {
+		c.debug.dump(req, res, err, c.maxBodySize)
+	}
+	if err != nil {
+		return nil, err
+	}
+	recordResponse(ctx, res, start)
+	if timer != nil {
+		op := CacheKeyOp(req.URL.RawQuery)
+		res.Body = &timedBody{ReadCloser: res.Body, report: func() {
+			c.reportTimings(ctx, op, timer.done())
+		}}
+	}
+	spanFrom(ctx).UpstreamStatus(res.StatusCode)
+	if res.StatusCode == http.StatusUnauthorized {
+		//OMDB rejects invalid keys and exhausted ones with a 401 and an error
+		//envelope telling which one it is.
+		defer res.Body.Close()
+		envelope := resultEnvelope{}
+		if json.NewDecoder(io.LimitReader(res.Body, c.maxBodySize)).Decode(&envelope) == nil && envelope.Error != "" {
+			return nil, &APIError{Message: envelope.Error}
 		}
+		return nil, &wrappedError{msg: statusError(res.StatusCode).Error(), err: ErrInvalidAPIKey}
 	}
-	if q.Year != "" {
-		params.Add("y", q.Year)
+	if res.StatusCode == http.StatusNotModified && header != nil {
+		return res, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, statusError(res.StatusCode)
+	}
+
+	return res, nil
+}
+
+//statusError is returned for a response with a status other than 200.
+type statusError int
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("http Status = %d", int(e))
+}
+
+//retryable reports whether a request which failed with err is worth another
+//attempt: transient errors are, unless ctx is done.
+func retryable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && transient(err)
+}
+
+//transient reports whether err is a network error, a 429 or a 5xx response.
+func transient(err error) bool {
+
+	var status statusError
+	if errors.As(err, &status) {
+		return status == http.StatusTooManyRequests || status >= 500
+	}
+	var netErr *url.Error
+	return errors.As(err, &netErr)
+}
+
+//cancelBody releases the request context once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+//SearchByImdbID performs an API search for a specified movie or series or episode by
+//the specific imdb id. Although OMDB API allows passing other parameters like Year, SearchType etc
+//but they are ignored here as search is done on a unique id.
+func (c *Client) SearchByImdbID(q QueryData) (interface{}, error) {
+	return c.SearchByImdbIDContext(context.Background(), q)
+}
+
+//SearchByImdbIDContext is like SearchByImdbID but the request is bound to ctx,
+//which can cancel it or set its deadline, and opts override the defaults of
+//the client for this call only.
+func (c *Client) SearchByImdbIDContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error) {
+
+	var err error
+	if q.ImdbID, err = normalizeID(q.ImdbID); err != nil {
+		return nil, err
 	}
 
-	if q.Plot != "" && q.Plot != "short" && q.Plot != "full" {
-		return nil, errors.New("omdb: Plot should be either blank or one of following: short, full")
+	ctx, o := withCallOptions(ctx, opts)
+	if o.plot != "" {
+		q.Plot = o.plot
 	}
-	if q.Plot != "" {
-		params.Add("plot", q.Plot)
+	if q.Plot == "" {
+		q.Plot = c.defaultPlot
 	}
 
-	res, err := c.requestOmdbAPI(params)
-	if err != nil {
+	if err := q.validate(OpByID); err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
+	return c.lookup(ctx, queryParams(OpByID, q))
+}
+
+//SearchByTitle performs an API search for a specified movie or series or episode by
+//the specific title.
+//Currently OMDB API seems to be returning only movie information, on a title
+//based search. Year parameter has to be greater tha nor equal to 1888 (trivia: which
+//is the world's earliest surviving motion-picture film?)
+func (c *Client) SearchByTitle(q QueryData) (interface{}, error) {
+	return c.SearchByTitleContext(context.Background(), q)
+}
+
+//SearchByTitleContext is like SearchByTitle but the request is bound to ctx,
+//which can cancel it or set its deadline, and opts override the defaults of
+//the client for this call only.
+func (c *Client) SearchByTitleContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error) {
+
+	ctx, o := withCallOptions(ctx, opts)
+	if o.plot != "" {
+		q.Plot = o.plot
+	}
+	if q.Plot == "" {
+		q.Plot = c.defaultPlot
+	}
+
+	if q.SearchType == "" {
+		q.SearchType = c.defaultSearchType
+	}
+
+	if err := q.validate(OpByTitle); err != nil {
 		return nil, err
 	}
 
-	envelope := resultEnvelope{}
-	err = json.Unmarshal(data, &envelope)
+	return c.lookup(ctx, queryParams(OpByTitle, q))
+}
+
+//lookup requests a single movie, series or episode and decodes it into the
+//result struct matching the Type reported by the API.
+func (c *Client) lookup(ctx context.Context, params url.Values) (interface{}, error) {
+
+	data, err := c.requestOmdbAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	if envelope.Response == "False" {
-		return nil, errors.New("omdb: Error from OMDB API: " + envelope.Error)
+	res, err := c.decodeResult(data)
+	if err != nil {
+		return nil, err
 	}
 
-	var val interface{}
-
-	switch envelope.Type {
-
-	case "movie":
-		movie := MovieResult{}
-		err = json.Unmarshal(data, &movie)
-		if err != nil {
+	if c.strictDecode && c.format == FormatJSON {
+		if err := unknownFields(data, res); err != nil {
 			return nil, err
 		}
-		val = movie
+	}
+	if c.emptyNA {
+		res = clearNotAvailable(res)
+	}
+	if c.keepRaw {
+		res = withRaw(res, data)
+	}
+	return res, nil
+}
 
-	case "series":
-		series := SeriesResult{}
-		err = json.Unmarshal(data, &series)
+//decodeResult unmarshals data into MovieResult, SeriesResult or EpisodeResult
+//depending on the Type found in the response envelope. The payload is parsed
+//once, into a decodedResult holding the fields of every type.
+func (c *Client) decodeResult(data []byte) (interface{}, error) {
+
+	if c.format == FormatXML {
+		root, err := decodeXML(data)
 		if err != nil {
 			return nil, err
 		}
-		val = series
+		return xmlResult(root), nil
+	}
+	return decodeJSONResult(data, c.lenientDecode)
+}
+
+//decodeJSONResult is decodeResult for a JSON response, lenient decoding
+//guessing the type of a result whose Type looks wrong.
+func decodeJSONResult(data []byte, lenient bool) (interface{}, error) {
+
+	decoded := decodedResult{}
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded.Response == "False" {
+		return nil, &APIError{Message: decoded.Error}
+	}
 
+	var val interface{}
+
+	switch decoded.Type {
+	case "movie":
+		val = decoded.MovieResult
+	case "series":
+		val = decoded.series()
 	case "episode":
-		episode := EpisodeResult{}
-		err = json.Unmarshal(data, &episode)
-		if err != nil {
-			return nil, err
-		}
-		val = episode
+		val = decoded.episode()
+	}
+
+	if lenient {
+		return lenientResult(&decoded, val), nil
 	}
 
 	return val, nil
@@ -226,56 +806,43 @@ func (c *Client) SearchByTitle(q QueryData) (interface{}, error) {
 //SearchByText performs an API search based on given text and return a SearchResponse
 //struct.
 func (c *Client) SearchByText(q QueryData) (*SearchResponse, error) {
+	return c.SearchByTextContext(context.Background(), q)
+}
 
-	params := url.Values{}
-
-	if q.Title == "" {
-		return nil, errors.New("omdb: Text to search (Title) is missing")
-	}
-	params.Add("s", q.Title)
+//SearchByTextContext is like SearchByText but the request is bound to ctx,
+//which can cancel it or set its deadline, and opts override the defaults of
+//the client for this call only.
+func (c *Client) SearchByTextContext(ctx context.Context, q QueryData, opts ...CallOption) (*SearchResponse, error) {
 
-	if q.SearchType != "" && q.SearchType != "movie" && q.SearchType != "series" && q.SearchType != "episode" {
-		return nil, errors.New("omdb: Searchtype should be either blank or one of following: movie, series, episode")
-	}
-	if q.SearchType != "" {
-		params.Add("type", q.SearchType)
-	}
+	ctx, _ = withCallOptions(ctx, opts)
 
-	if q.Year != "" {
-		i, err := strconv.Atoi(q.Year)
-		if err != nil {
-			return nil, errors.New("omdb: Year omdb: is either blank or a valid number")
-		}
-		if i < 1888 {
-			return nil, errors.New("omdb: Year should be either blank or greater than 1887")
-		}
-	}
-	if q.Year != "" {
-		params.Add("y", q.Year)
+	if q.SearchType == "" {
+		q.SearchType = c.defaultSearchType
 	}
 
-	if q.Page != "" {
-		i, err := strconv.Atoi(q.Page)
-		if err != nil {
-			return nil, errors.New("omdb: Page should be either blank or a valid number")
-		}
-		if i < 1 || i > 100 {
-			return nil, errors.New("omdb: Page should be either blank or between 1 to 100 (inclusive of both)")
-		}
-	}
-	if q.Page != "" {
-		params.Add("page", q.Page)
+	if err := q.validate(OpSearch); err != nil {
+		return nil, err
 	}
 
-	res, err := c.requestOmdbAPI(params)
+	data, err := c.requestOmdbAPI(ctx, queryParams(OpSearch, q))
 	if err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	if c.format == FormatXML {
+		root, err := decodeXML(data)
+		if err != nil {
+			return nil, err
+		}
+		res := xmlSearch(root)
+		res.Total, _ = strconv.Atoi(res.TotalResults)
+		if c.emptyNA {
+			clearNotAvailable(res)
+		}
+		if c.keepRaw {
+			res.Raw = append([]byte(nil), data...)
+		}
+		return res, nil
 	}
 
 	searchresponse := SearchResponse{}
@@ -285,8 +852,20 @@ func (c *Client) SearchByText(q QueryData) (*SearchResponse, error) {
 	}
 
 	if searchresponse.Response == "False" {
-		return nil, errors.New("omdb: Error from OMDB API: " + searchresponse.Error)
+		return nil, &APIError{Message: searchresponse.Error}
 	}
 
+	if c.strictDecode {
+		if err := unknownFields(data, searchresponse); err != nil {
+			return nil, err
+		}
+	}
+	searchresponse.Total, _ = strconv.Atoi(searchresponse.TotalResults)
+	if c.emptyNA {
+		clearNotAvailable(&searchresponse)
+	}
+	if c.keepRaw {
+		searchresponse.Raw = append([]byte(nil), data...)
+	}
 	return &searchresponse, nil
 }
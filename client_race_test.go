@@ -0,0 +1,81 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//TestClientConcurrentUse drives a single Client with a cache, a rate limiter
+//and a daily quota from many goroutines, run it with -race.
+func TestClientConcurrentUse(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	const titles = 20
+	for i := 0; i < titles; i++ {
+		srv.AddMovie(omdb.MovieResult{
+			Title:  "Movie " + strconv.Itoa(i),
+			Year:   "1999",
+			ImdbID: "tt" + strconv.Itoa(1000000+i),
+		})
+	}
+
+	const limit = 15
+	quota := omdb.NewQuotaTracker(limit, nil)
+	client := srv.Client(
+		omdb.WithCache(omdb.NewLRUCache(100), time.Hour),
+		omdb.WithRateLimit(1000, 50),
+		omdb.WithDailyQuota(quota),
+	)
+
+	ctx := context.Background()
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		exhausted int
+	)
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 40; i++ {
+				id := "tt" + strconv.Itoa(1000000+(g+i)%titles)
+				var err error
+				switch i % 4 {
+				case 0, 1:
+					_, err = client.GetMovieByID(ctx, id)
+				case 2:
+					_, err = client.SearchByTextContext(ctx, omdb.QueryData{Title: "Movie"})
+				case 3:
+					_, err = client.Clone(omdb.WithDefaultPlot(omdb.PlotFull)).GetMovieByID(ctx, id)
+				}
+				switch {
+				case errors.Is(err, omdb.ErrQuotaExhausted):
+					mu.Lock()
+					exhausted++
+					mu.Unlock()
+				case err != nil:
+					t.Errorf("lookup of %s: %v", id, err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := srv.Requests(); n > limit {
+		t.Errorf("server got %d requests, want at most the quota of %d", n, limit)
+	}
+	if used, n := quota.Used(), srv.Requests(); used != n {
+		t.Errorf("quota counted %d requests, server got %d", used, n)
+	}
+	if exhausted == 0 {
+		t.Error("no lookup failed with ErrQuotaExhausted")
+	}
+}
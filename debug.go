@@ -0,0 +1,55 @@
+package omdb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+//debugLog writes the requests of a client and their responses, see
+//WithDebug.
+type debugLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+//dump writes req, with its API key masked, and its response or error. The
+//body of res is read, up to limit bytes, and replaced so the caller still
+//reads it.
+func (d *debugLog) dump(req *http.Request, res *http.Response, err error, limit int64) {
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "omdb: %s %s\n", req.Method, redactURL(req.URL.String()))
+	if err != nil {
+		fmt.Fprintf(&buf, "omdb: error: %v\n", err)
+	} else {
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, limit))
+		res.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+		fmt.Fprintf(&buf, "omdb: %s\n%s\n", res.Status, body)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.w.Write(buf.Bytes())
+}
+
+//redactURL masks the apikey parameter of rawURL like KeyUsage does.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if key := u.Query().Get("apikey"); key != "" {
+		//replaced in place, encoding the parameters would escape the mask.
+		u.RawQuery = strings.Replace(u.RawQuery, "apikey="+url.QueryEscape(key), "apikey="+maskKey(key), 1)
+	}
+	return u.String()
+}
@@ -0,0 +1,258 @@
+package omdb
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//decodedResult holds the envelope and the fields of every result type, so a
+//lookup response is unmarshaled once whatever its Type. MovieResult has every
+//field of SeriesResult and EpisodeResult except those of the envelope.
+type decodedResult struct {
+	resultEnvelope
+	MovieResult
+}
+
+func (d *decodedResult) series() SeriesResult {
+	m := d.MovieResult
+	return SeriesResult{
+		Title:        m.Title,
+		Year:         m.Year,
+		Rated:        m.Rated,
+		Released:     m.Released,
+		Runtime:      m.Runtime,
+		Genre:        m.Genre,
+		Director:     m.Director,
+		Writer:       m.Writer,
+		Actors:       m.Actors,
+		Plot:         m.Plot,
+		Language:     m.Language,
+		Country:      m.Country,
+		Awards:       m.Awards,
+		Poster:       m.Poster,
+		Ratings:      m.Ratings,
+		Metascore:    m.Metascore,
+		ImdbRating:   m.ImdbRating,
+		ImdbVotes:    m.ImdbVotes,
+		ImdbID:       m.ImdbID,
+		TotalSeasons: d.TotalSeasons,
+	}
+}
+
+func (d *decodedResult) episode() EpisodeResult {
+	m := d.MovieResult
+	return EpisodeResult{
+		Title:      m.Title,
+		Year:       m.Year,
+		Rated:      m.Rated,
+		Released:   m.Released,
+		Runtime:    m.Runtime,
+		Genre:      m.Genre,
+		Director:   m.Director,
+		Writer:     m.Writer,
+		Actors:     m.Actors,
+		Plot:       m.Plot,
+		Language:   m.Language,
+		Country:    m.Country,
+		Awards:     m.Awards,
+		Poster:     m.Poster,
+		Ratings:    m.Ratings,
+		Metascore:  m.Metascore,
+		ImdbRating: m.ImdbRating,
+		ImdbVotes:  m.ImdbVotes,
+		ImdbID:     m.ImdbID,
+		SeriesID:   d.SeriesID,
+		Season:     d.Season,
+		Episode:    d.Episode,
+	}
+}
+
+//lenientResult returns primary unless it looks like the envelope Type was
+//wrong, in which case every result type is built from d and the one with the
+//most populated fields wins. primary wins ties.
+func lenientResult(d *decodedResult, primary interface{}) interface{} {
+
+	suspicious := primary == nil ||
+		(d.Type != "series" && notAvailable(d.TotalSeasons) != "") ||
+		(d.Type != "episode" && notAvailable(d.SeriesID) != "")
+
+	if !suspicious {
+		v := reflect.ValueOf(primary)
+		suspicious = notAvailable(v.FieldByName("Title").String()) == "" ||
+			notAvailable(v.FieldByName("ImdbID").String()) == ""
+	}
+	if !suspicious {
+		return primary
+	}
+
+	best, bestScore := primary, populated(primary)
+	for _, candidate := range []interface{}{d.MovieResult, d.series(), d.episode()} {
+		if score := populated(candidate); score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	return best
+}
+
+//populated counts the string fields of v which hold a value other than "N/A"
+//and its non-empty slices.
+func populated(v interface{}) int {
+
+	if v == nil {
+		return 0
+	}
+
+	n := 0
+	rv := reflect.ValueOf(v)
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		switch f.Kind() {
+		case reflect.String:
+			if notAvailable(f.String()) != "" {
+				n++
+			}
+		case reflect.Slice:
+			if f.Len() > 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+//clearNotAvailable replaces "N/A" by "" in every string field of v, a result
+//struct or a pointer to one, descending into slices and nested structs. A
+//struct is returned as a cleared copy, a pointer is cleared in place.
+func clearNotAvailable(v interface{}) interface{} {
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		clearValue(rv.Elem())
+		return v
+	}
+
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	clearValue(cp)
+	return cp.Interface()
+}
+
+func clearValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "N/A" {
+			v.SetString("")
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				clearValue(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearValue(v.Index(i))
+		}
+	}
+}
+
+//withRaw returns res with its Raw field set to a copy of data, which may be
+//shared with the cache.
+func withRaw(res interface{}, data []byte) interface{} {
+
+	raw := append([]byte(nil), data...)
+	switch r := res.(type) {
+	case MovieResult:
+		r.Raw = raw
+		return r
+	case SeriesResult:
+		r.Raw = raw
+		return r
+	case EpisodeResult:
+		r.Raw = raw
+		return r
+	}
+	return res
+}
+
+//envelopeFields are the fields of every response which the result structs
+//don't carry.
+var envelopeFields = []string{"type", "response", "error"}
+
+//unknownFields returns an error matching ErrUnknownField naming the fields of
+//the JSON object data which have no counterpart in the struct res, including
+//the fields of nested objects like Ratings.
+func unknownFields(data []byte, res interface{}) error {
+
+	var unknown []string
+	err := collectUnknown(data, reflect.TypeOf(res), "", &unknown)
+	if err != nil || len(unknown) == 0 {
+		return err
+	}
+
+	sort.Strings(unknown)
+	return &wrappedError{msg: "omdb: Unknown fields in response: " + strings.Join(unknown, ", "), err: ErrUnknownField}
+}
+
+func collectUnknown(data []byte, t reflect.Type, prefix string, unknown *[]string) error {
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	//encoding/json matches names case-insensitively.
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Name
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			name = tag
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	if prefix == "" {
+		for _, name := range envelopeFields {
+			if _, ok := fields[name]; !ok {
+				fields[name] = nil
+			}
+		}
+	}
+
+	for key, value := range object {
+		ft, ok := fields[strings.ToLower(key)]
+		if !ok {
+			*unknown = appendOnce(*unknown, prefix+key)
+			continue
+		}
+		if ft == nil || ft.Kind() != reflect.Slice || ft.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			continue
+		}
+		for _, item := range items {
+			if err := collectUnknown(item, ft.Elem(), prefix+key+".", unknown); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func appendOnce(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,117 @@
+package omdb
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestEmptyNotAvailable(t *testing.T) {
+
+	srv := fixtureServer(t)
+	plain := NewClient("key", nil, WithBaseURL(srv.URL))
+	empty := NewClient("key", nil, WithBaseURL(srv.URL), WithEmptyNotAvailable())
+
+	res, err := plain.SearchByTitle(QueryData{Title: "series"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.(SeriesResult); s.Director != "N/A" || s.Metascore != "N/A" {
+		t.Errorf("got Director %q and Metascore %q, want N/A", s.Director, s.Metascore)
+	}
+
+	res, err = empty.SearchByTitle(QueryData{Title: "series"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := res.(SeriesResult)
+	if s.Director != "" || s.Metascore != "" {
+		t.Errorf("got Director %q and Metascore %q, want them empty", s.Director, s.Metascore)
+	}
+	if s.Writer != "Vince Gilligan" || s.TotalSeasons != "5" {
+		t.Errorf("got Writer %q and TotalSeasons %q, want them kept", s.Writer, s.TotalSeasons)
+	}
+
+	res, err = empty.SearchByTitle(QueryData{Title: "episode"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := res.(EpisodeResult); e.Awards != "" || e.Metascore != "" || e.ImdbRating != "8.6" {
+		t.Errorf("got Awards %q, Metascore %q and ImdbRating %q", e.Awards, e.Metascore, e.ImdbRating)
+	}
+}
+
+func TestRawResponse(t *testing.T) {
+
+	srv := fixtureServer(t)
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithRawResponse())
+
+	for _, name := range []string{"movie", "series", "episode"} {
+		data, err := os.ReadFile("testdata/" + name + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := client.SearchByTitle(QueryData{Title: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var raw []byte
+		switch r := res.(type) {
+		case MovieResult:
+			raw = r.Raw
+		case SeriesResult:
+			raw = r.Raw
+		case EpisodeResult:
+			raw = r.Raw
+		}
+		if !bytes.Equal(bytes.TrimSpace(raw), bytes.TrimSpace(data)) {
+			t.Errorf("%s: got Raw %q, want the payload", name, raw)
+		}
+	}
+
+	res, err := NewClient("key", nil, WithBaseURL(srv.URL)).SearchByTitle(QueryData{Title: "movie"})
+	if err != nil || res.(MovieResult).Raw != nil {
+		t.Errorf("got Raw %q, %v without WithRawResponse", res.(MovieResult).Raw, err)
+	}
+}
+
+func TestLenientDecode(t *testing.T) {
+
+	srv := fixtureServer(t)
+	strict := NewClient("key", nil, WithBaseURL(srv.URL))
+	lenient := NewClient("key", nil, WithBaseURL(srv.URL), WithLenientDecode())
+
+	//a series reported as a movie.
+	res, err := strict.SearchByTitle(QueryData{Title: "mistyped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.(MovieResult); !ok {
+		t.Errorf("got a %s, want the reported movie", TypeOf(res))
+	}
+	res, err = lenient.SearchByTitle(QueryData{Title: "mistyped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := res.(SeriesResult); !ok || s.TotalSeasons != "5" || s.ImdbID != "tt0903747" {
+		t.Errorf("got %+v, want the series", res)
+	}
+
+	//an episode without a Type.
+	res, err = lenient.SearchByTitle(QueryData{Title: "untyped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := res.(EpisodeResult); !ok || e.SeriesID != "tt0903747" || e.Season != "1" {
+		t.Errorf("got %+v, want the episode", res)
+	}
+
+	//a consistent payload is decoded as reported.
+	res, err = lenient.SearchByTitle(QueryData{Title: "movie"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := res.(MovieResult); !ok || m.ImdbID != "tt0133093" {
+		t.Errorf("got %+v, want the movie", res)
+	}
+}
@@ -0,0 +1,148 @@
+package omdb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//FieldChange is a field which differs between two results. Ratings are
+//compared by source, their Field being "Ratings." followed by the source,
+//e.g. "Ratings.Rotten Tomatoes".
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+//Added reports whether the field wasn't set in the old result, e.g. the
+//first ratings of a title after its release.
+func (c FieldChange) Added() bool {
+	return c.Old == ""
+}
+
+//Removed reports whether the field isn't set in the new result anymore.
+func (c FieldChange) Removed() bool {
+	return c.New == ""
+}
+
+//listedResult is a Result with the accessors of its comma separated fields.
+type listedResult interface {
+	Genres() []string
+	DirectorsList() []string
+	WritersList() []string
+	ActorsList() []string
+	Languages() []string
+	Countries() []string
+}
+
+//Diff returns the fields which differ from old to new: the Title, Year,
+//ImdbID and Poster of the results, their Runtime, Released, DVD, ImdbRating
+//and Metascore as Typed parses them, their Genre, Director, Writer, Actors,
+//Language and Country lists and lastly their ratings. ImdbVotes, which
+//changes on almost every refresh of a released title, is left out.
+//
+//The results are usually the same title before and after a refresh; fields
+//"N/A" are compared as empty and a nil result has no fields set.
+func Diff(old, new Result) []FieldChange {
+
+	o, n := diffFields(old), diffFields(new)
+	var changes []FieldChange
+	for _, field := range diffFieldNames {
+		if o[field] != n[field] {
+			changes = append(changes, FieldChange{Field: field, Old: o[field], New: n[field]})
+		}
+	}
+
+	var oldRatings, newRatings []Rating
+	if !isNilResult(old) {
+		oldRatings = old.RatingsList()
+	}
+	if !isNilResult(new) {
+		newRatings = new.RatingsList()
+	}
+	return append(changes, diffRatings(oldRatings, newRatings)...)
+}
+
+//diffFieldNames are the fields compared by Diff, in order.
+var diffFieldNames = []string{
+	"Title", "Year", "ImdbID", "Poster",
+	"Runtime", "Released", "DVD", "ImdbRating", "Metascore",
+	"Genre", "Director", "Writer", "Actors", "Language", "Country",
+}
+
+//diffFields returns the fields of r compared by Diff, keyed by name. Fields
+//which aren't set are left out.
+func diffFields(r Result) map[string]string {
+
+	fields := make(map[string]string, len(diffFieldNames))
+	if isNilResult(r) {
+		return fields
+	}
+
+	fields["Title"], fields["Year"], fields["ImdbID"] = notAvailable(r.TitleName()), notAvailable(r.YearOf()), r.IMDBID()
+	if poster, err := r.PosterURL(); err == nil {
+		fields["Poster"] = poster.String()
+	}
+	if t, ok := r.(typedResult); ok {
+		typed := t.Typed()
+		if typed.Runtime > 0 {
+			fields["Runtime"] = strconv.Itoa(int(typed.Runtime/time.Minute)) + " min"
+		}
+		fields["Released"], fields["DVD"] = formatDate(typed.Released), formatDate(typed.DVD)
+		if typed.ImdbRating > 0 {
+			fields["ImdbRating"] = strconv.FormatFloat(typed.ImdbRating, 'f', 1, 64)
+		}
+		if typed.Metascore > 0 {
+			fields["Metascore"] = strconv.Itoa(typed.Metascore)
+		}
+	}
+	if l, ok := r.(listedResult); ok {
+		fields["Genre"] = strings.Join(l.Genres(), ", ")
+		fields["Director"] = strings.Join(l.DirectorsList(), ", ")
+		fields["Writer"] = strings.Join(l.WritersList(), ", ")
+		fields["Actors"] = strings.Join(l.ActorsList(), ", ")
+		fields["Language"] = strings.Join(l.Languages(), ", ")
+		fields["Country"] = strings.Join(l.Countries(), ", ")
+	}
+	return fields
+}
+
+//diffRatings compares ratings by source, ordered by source.
+func diffRatings(old, new []Rating) []FieldChange {
+
+	values := make(map[string]*FieldChange)
+	var sources []string
+	get := func(source string) *FieldChange {
+		c, ok := values[source]
+		if !ok {
+			c = &FieldChange{Field: "Ratings." + source}
+			values[source] = c
+			sources = append(sources, source)
+		}
+		return c
+	}
+	for _, r := range old {
+		get(r.Source).Old = notAvailable(r.Value)
+	}
+	for _, r := range new {
+		get(r.Source).New = notAvailable(r.Value)
+	}
+	sort.Strings(sources)
+
+	var changes []FieldChange
+	for _, s := range sources {
+		if c := values[s]; c.Old != c.New {
+			changes = append(changes, *c)
+		}
+	}
+	return changes
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
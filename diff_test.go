@@ -0,0 +1,79 @@
+package omdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+
+	old := MovieResult{
+		Title:      "The Matrix",
+		Year:       "1999",
+		ImdbID:     "tt0133093",
+		Runtime:    "136 min",
+		Released:   "31 Mar 1999",
+		Genre:      "Action, Sci-Fi",
+		Poster:     "N/A",
+		ImdbRating: "8.7",
+		ImdbVotes:  "2,000,000",
+		Metascore:  "N/A",
+		Ratings:    []Rating{{Source: "Internet Movie Database", Value: "8.7/10"}},
+	}
+	new := old
+	new.Poster = "https://m.media-amazon.com/images/M/matrix.jpg"
+	new.ImdbRating = "8.8"
+	new.ImdbVotes = "2,000,100"
+	new.Metascore = "73"
+	new.Genre = "Action, Sci-Fi, Thriller"
+	new.Ratings = []Rating{
+		{Source: "Internet Movie Database", Value: "8.8/10"},
+		{Source: "Metacritic", Value: "73/100"},
+	}
+
+	want := []FieldChange{
+		{Field: "Poster", New: "https://m.media-amazon.com/images/M/matrix.jpg"},
+		{Field: "ImdbRating", Old: "8.7", New: "8.8"},
+		{Field: "Metascore", New: "73"},
+		{Field: "Genre", Old: "Action, Sci-Fi", New: "Action, Sci-Fi, Thriller"},
+		{Field: "Ratings.Internet Movie Database", Old: "8.7/10", New: "8.8/10"},
+		{Field: "Ratings.Metacritic", New: "73/100"},
+	}
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if got := Diff(&old, &new); !reflect.DeepEqual(got, want) {
+		t.Errorf("pointers: got %+v\nwant %+v", got, want)
+	}
+	if !want[0].Added() || want[1].Added() {
+		t.Error("Added doesn't tell new fields apart")
+	}
+}
+
+func TestDiffIgnoresVotesAndUnchanged(t *testing.T) {
+
+	old := SeriesResult{Title: "Severance", ImdbID: "tt11280740", ImdbVotes: "100", TotalSeasons: "1"}
+	new := old
+	new.ImdbVotes = "120"
+	if got := Diff(old, new); len(got) != 0 {
+		t.Errorf("got %+v, want no changes", got)
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+
+	e := EpisodeResult{Title: "Pilot", ImdbID: "tt0959621", Ratings: []Rating{{Source: "Internet Movie Database", Value: "9.0/10"}}}
+	got := Diff(nil, e)
+	if len(got) != 3 || got[0].Field != "Title" || got[1].Field != "ImdbID" || got[2].New != "9.0/10" {
+		t.Errorf("got %+v, want Title, ImdbID and the rating added", got)
+	}
+	if got := Diff((*EpisodeResult)(nil), &e); len(got) != 3 {
+		t.Errorf("nil pointer: got %+v", got)
+	}
+	if got := Diff(e, nil); len(got) != 3 || !got[0].Removed() {
+		t.Errorf("got %+v, want every field removed", got)
+	}
+	if got := Diff(nil, nil); got != nil {
+		t.Errorf("got %+v, want none", got)
+	}
+}
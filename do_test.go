@@ -0,0 +1,51 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestDo(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", ImdbRating: "8.7"})
+
+	ctx := context.Background()
+	client := srv.Client(omdb.WithCache(omdb.NewLRUCache(10), 0))
+
+	//the apikey of params is replaced by the one of the client.
+	params := url.Values{"i": {"tt0133093"}, "apikey": {"other"}}
+	var into struct {
+		Title      string
+		ImdbRating string `json:"imdbRating"`
+	}
+	if err := client.Do(ctx, params, &into); err != nil {
+		t.Fatal(err)
+	}
+	if into.Title != "The Matrix" || into.ImdbRating != "8.7" {
+		t.Errorf("got %+v", into)
+	}
+	if params.Get("apikey") != "other" || len(params) != 2 {
+		t.Errorf("params were modified: %v", params)
+	}
+
+	//the cache applies, into may be nil.
+	if err := client.Do(ctx, url.Values{"i": {"tt0133093"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Requests() != 1 {
+		t.Errorf("got %d requests, want the second call cached", srv.Requests())
+	}
+
+	var apiErr *omdb.APIError
+	err := client.Do(ctx, url.Values{"t": {"Unknown"}}, &into)
+	if !errors.As(err, &apiErr) || !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want an APIError matching ErrNotFound", err)
+	}
+}
@@ -0,0 +1,103 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//Enrich looks up the full details of every search result by its ImdbID, using
+//up to workers concurrent requests (1 when workers is less than 1). The
+//returned slice has the same order as results and holds a MovieResult,
+//SeriesResult or EpisodeResult, or nil for a failed lookup; the failures are
+//joined into the returned error.
+//
+//Every lookup goes through the same request path as the other methods of the
+//client, so whatever limits the client applies to its requests also apply to
+//the concurrent lookups as a whole.
+func (c *Client) Enrich(ctx context.Context, results []SearchResult, workers int) ([]interface{}, error) {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	out := make([]interface{}, len(results))
+	errs := make([]error, len(results))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				out[i], errs[i] = c.SearchByImdbIDContext(ctx, QueryData{ImdbID: results[i].ImdbID})
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return out, errors.Join(errs...)
+}
+
+//defaultWorkers is the number of concurrent requests of GetByIDs unless
+//WithWorkers is given.
+const defaultWorkers = 4
+
+//GetByIDs looks up every id in ids with up to defaultWorkers (or WithWorkers)
+//concurrent requests and returns the MovieResult, SeriesResult or
+//EpisodeResult of each successful lookup keyed by its id. Duplicate ids are
+//requested once. The failed lookups are left out of the map and joined into
+//the returned error, each prefixed by its id, so a partial failure still
+//returns the results which succeeded.
+//
+//Like Enrich, the lookups go through the limits of the client as a whole.
+func (c *Client) GetByIDs(ctx context.Context, ids []string, opts ...CallOption) (map[string]interface{}, error) {
+
+	ctx, o := withCallOptions(ctx, opts)
+	workers := o.workers
+	if workers < 1 {
+		workers = defaultWorkers
+	}
+
+	var (
+		mu   sync.Mutex
+		out  = make(map[string]interface{}, len(ids))
+		errs []error
+	)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				res, err := c.SearchByImdbIDContext(ctx, QueryData{ImdbID: id})
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, &wrappedError{msg: "omdb: " + id + ": " + err.Error(), err: err})
+				} else {
+					out[id] = res
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+
+	return out, errors.Join(errs...)
+}
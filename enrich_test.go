@@ -0,0 +1,101 @@
+package omdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+//timestampServer answers every lookup with a movie of the requested id and
+//records when each request arrived.
+type timestampServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func newTimestampServer() *timestampServer {
+	s := &timestampServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.times = append(s.times, time.Now())
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Title":"Movie","Year":"1999","imdbID":"` + r.URL.Query().Get("i") + `","Type":"movie","Response":"True"}`))
+	}))
+	return s
+}
+
+//checkRate fails when more requests than burst plus limit per second
+//arrived within any window of the recorded times.
+func (s *timestampServer) checkRate(t *testing.T, limit Limit, burst int) {
+
+	t.Helper()
+	s.mu.Lock()
+	times := append([]time.Time(nil), s.times...)
+	s.mu.Unlock()
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	//the server sees requests a little after the limiter lets them go.
+	const slack = 10 * time.Millisecond
+	for i := range times {
+		for j := i + 1; j < len(times); j++ {
+			window := times[j].Sub(times[i]) + slack
+			allowed := float64(burst) + float64(limit)*window.Seconds()
+			if n := j - i + 1; float64(n) > allowed {
+				t.Fatalf("%d requests within %v, the rate allows %.1f", n, window, allowed)
+			}
+		}
+	}
+}
+
+func TestEnrichRespectsRateLimit(t *testing.T) {
+
+	srv := newTimestampServer()
+	defer srv.Close()
+
+	const limit, burst = 40, 2
+	c := NewClient("key", srv.Client(), WithBaseURL(srv.URL+"/"), WithRateLimit(limit, burst))
+
+	results := make([]SearchResult, 12)
+	for i := range results {
+		results[i].ImdbID = fmt.Sprintf("tt%07d", i+1)
+	}
+	out, err := c.Enrich(context.Background(), results, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, res := range out {
+		if m, ok := res.(MovieResult); !ok || m.ImdbID != results[i].ImdbID {
+			t.Errorf("result %d: got %#v, want the movie %s", i, res, results[i].ImdbID)
+		}
+	}
+	if n := len(srv.times); n != len(results) {
+		t.Fatalf("server got %d requests, want %d", n, len(results))
+	}
+	srv.checkRate(t, limit, burst)
+}
+
+func TestGetByIDsRespectsRateLimit(t *testing.T) {
+
+	srv := newTimestampServer()
+	defer srv.Close()
+
+	const limit, burst = 40, 1
+	c := NewClient("key", srv.Client(), WithBaseURL(srv.URL+"/"), WithRateLimit(limit, burst))
+
+	ids := []string{"tt0000101", "tt0000102", "tt0000103", "tt0000104", "tt0000105", "tt0000106", "tt0000107", "tt0000108", "tt0000101"}
+	out, err := c.GetByIDs(context.Background(), ids, WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 8 || len(srv.times) != 8 {
+		t.Fatalf("got %d results from %d requests, want 8 of each", len(out), len(srv.times))
+	}
+	srv.checkRate(t, limit, burst)
+}
@@ -0,0 +1,49 @@
+package omdb
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+//Environment variables read by NewClientFromEnv.
+const (
+	EnvAPIKey  = "OMDB_API_KEY"
+	EnvBaseURL = "OMDB_BASE_URL"
+	EnvTimeout = "OMDB_TIMEOUT"
+)
+
+//NewClientFromEnv creates a Client like New, with the API key read from
+//OMDB_API_KEY. When set, OMDB_BASE_URL replaces DefaultURL and OMDB_TIMEOUT,
+//a duration like "10s" or a number of seconds, is applied with WithTimeout.
+//opts are applied after the settings of the environment and override them.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+
+	key := os.Getenv(EnvAPIKey)
+	if key == "" {
+		return nil, &wrappedError{msg: "omdb: " + EnvAPIKey + " is not set", err: ErrInvalidAPIKey}
+	}
+
+	var env []Option
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		env = append(env, WithBaseURL(baseURL))
+	}
+	if timeout := os.Getenv(EnvTimeout); timeout != "" {
+		d, err := parseTimeout(timeout)
+		if err != nil {
+			return nil, errors.New("omdb: " + EnvTimeout + " should be a duration like 10s or a number of seconds")
+		}
+		env = append(env, WithTimeout(d))
+	}
+
+	return New(key, append(env, opts...)...)
+}
+
+//parseTimeout parses a time.Duration, or a number of seconds without unit.
+func parseTimeout(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
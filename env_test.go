@@ -0,0 +1,61 @@
+package omdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+
+	t.Setenv(EnvAPIKey, "1a2b3c4d")
+	t.Setenv(EnvBaseURL, "https://omdb.example.com/")
+	t.Setenv(EnvTimeout, "2.5")
+	c, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.apiKey != "1a2b3c4d" || c.baseURL != "https://omdb.example.com/" || c.timeout != 2500*time.Millisecond {
+		t.Errorf("got key %q, base URL %q and timeout %v", c.apiKey, c.baseURL, c.timeout)
+	}
+
+	//options override the environment.
+	t.Setenv(EnvTimeout, "1m")
+	c, err = NewClientFromEnv(WithTimeout(time.Second))
+	if err != nil || c.timeout != time.Second {
+		t.Errorf("got timeout %v, %v, want the one of the option", c.timeout, err)
+	}
+
+	t.Setenv(EnvBaseURL, "")
+	t.Setenv(EnvTimeout, "")
+	if c, err := NewClientFromEnv(); err != nil || c.baseURL != DefaultURL {
+		t.Errorf("got base URL %q, %v, want DefaultURL", c.baseURL, err)
+	}
+}
+
+func TestNewClientFromEnvErrors(t *testing.T) {
+
+	for _, tt := range []struct {
+		name, key, baseURL, timeout string
+		want                        error
+	}{
+		{"key missing", "", "", "", ErrInvalidAPIKey},
+		{"bad key", "not a key", "", "", ErrInvalidAPIKey},
+		{"bad base URL", "1a2b3c4d", "ftp://omdb.example.com", "", nil},
+		{"bad timeout", "1a2b3c4d", "", "soon", nil},
+		{"negative timeout", "1a2b3c4d", "", "-1s", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvAPIKey, tt.key)
+			t.Setenv(EnvBaseURL, tt.baseURL)
+			t.Setenv(EnvTimeout, tt.timeout)
+			c, err := NewClientFromEnv()
+			if err == nil || c != nil {
+				t.Fatalf("got %v, %v, want an error", c, err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package omdb
+
+import (
+	"errors"
+	"strings"
+)
+
+//Errors which can be matched with errors.Is against the errors returned by
+//the client.
+var (
+	//ErrNotAvailable is returned by accessors when OMDB reports a value as "N/A".
+	ErrNotAvailable = errors.New("omdb: value not available")
+
+	//ErrNotFound is returned when OMDB has no result for a query.
+	ErrNotFound = errors.New("omdb: not found")
+
+	//ErrInvalidAPIKey is returned when the API key is missing or rejected.
+	ErrInvalidAPIKey = errors.New("omdb: invalid API key")
+
+	//ErrDailyLimitExceeded is returned when the API key used up its daily
+	//request limit.
+	ErrDailyLimitExceeded = errors.New("omdb: daily request limit exceeded")
+
+	//ErrQuotaExhausted is returned, without sending the request, once the
+	//daily quota of the client's QuotaTracker is used up.
+	ErrQuotaExhausted = errors.New("omdb: daily quota exhausted")
+
+	//ErrCircuitOpen is returned, without sending the request, while the
+	//circuit breaker of the client is open.
+	ErrCircuitOpen = errors.New("omdb: circuit breaker open")
+
+	//ErrInvalidRequest is returned for a query rejected by the client's
+	//validation or by OMDB.
+	ErrInvalidRequest = errors.New("omdb: invalid request")
+
+	//ErrTypeMismatch is returned by the typed lookups when the result is not
+	//of the requested type.
+	ErrTypeMismatch = errors.New("omdb: result type mismatch")
+
+	//ErrUnknownField is returned in strict decoding mode when a response has
+	//fields the result structs don't model.
+	ErrUnknownField = errors.New("omdb: unknown field in response")
+
+	//ErrResponseTooLarge is returned when a response body exceeds the
+	//maximum size set with WithMaxResponseSize.
+	ErrResponseTooLarge = errors.New("omdb: response too large")
+)
+
+//APIError is an error reported by OMDB in the Error field of a response.
+//It matches ErrNotFound, ErrInvalidAPIKey, ErrDailyLimitExceeded or
+//ErrInvalidRequest with errors.Is depending on Message.
+type APIError struct {
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return "omdb: Error from OMDB API: " + e.Message
+}
+
+//Unwrap returns the sentinel error matching the message of OMDB, or nil.
+func (e *APIError) Unwrap() error {
+	msg := strings.ToLower(e.Message)
+	switch {
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "incorrect imdb id"):
+		return ErrNotFound
+	case strings.Contains(msg, "api key"):
+		return ErrInvalidAPIKey
+	case strings.Contains(msg, "limit reached"):
+		return ErrDailyLimitExceeded
+	case strings.Contains(msg, "too many results"), strings.Contains(msg, "must provide"):
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+//wrappedError is an error with its own message which matches a sentinel
+//error with errors.Is.
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (e *wrappedError) Error() string { return e.msg }
+func (e *wrappedError) Unwrap() error { return e.err }
+
+//invalidRequest returns a validation error matching ErrInvalidRequest.
+func invalidRequest(msg string) error {
+	return &wrappedError{msg: msg, err: ErrInvalidRequest}
+}
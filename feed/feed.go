@@ -0,0 +1,288 @@
+//Package feed renders the tracked titles as an RSS or Atom feed: the
+//episodes of the series followed by a tracker.Tracker and the movies of a
+//watchlist.Watchlist which were released recently, for feed readers to
+//subscribe to. A feed can be served by an HTTP server or written to disk by
+//a cron job:
+//
+//	f := &feed.Feed{Title: "New releases", Link: "https://example.com/feed"}
+//	since := time.Now().AddDate(0, 0, -30)
+//	f.Add(feed.Episodes(shows.List(), since, time.Now())...)
+//	f.Add(feed.Movies(list.List(), since, time.Now())...)
+//	err := f.WriteFile("/var/www/feed.xml")
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/tracker"
+	"github.com/ahin/omdb/watchlist"
+)
+
+//releasedLayout is the format of the release dates reported by OMDB.
+const releasedLayout = "02 Jan 2006"
+
+//Feed is a feed of released titles.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Items       []Item
+}
+
+//Item is a released title of a feed.
+type Item struct {
+	//ID identifies the item across renderings, e.g. the IMDb id of the title.
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+}
+
+//Add adds items to the feed.
+func (f *Feed) Add(items ...Item) {
+	f.Items = append(f.Items, items...)
+}
+
+//Episodes returns the episodes of series aired from since until now.
+func Episodes(series []tracker.Series, since, now time.Time) []Item {
+
+	var items []Item
+	for _, s := range series {
+		for _, e := range s.Episodes {
+			aired, ok := e.Aired()
+			if !ok || !within(aired, since, now) {
+				continue
+			}
+			item := Item{
+				ID:        e.ImdbID,
+				Title:     s.Title + " " + e.Code(),
+				Link:      link(e.ImdbID),
+				Published: aired,
+			}
+			if item.ID == "" {
+				item.ID, item.Link = s.ImdbID+"-"+e.Code(), link(s.ImdbID)
+			}
+			if e.Title != "" {
+				item.Title += " - " + e.Title
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+//Movies returns the movies of entries released from since until now.
+func Movies(entries []watchlist.Entry, since, now time.Time) []Item {
+
+	var items []Item
+	for _, e := range entries {
+		if !omdb.TypeMovie.Is(e.Type) {
+			continue
+		}
+		released, err := time.Parse(releasedLayout, e.Released)
+		if err != nil || !within(released, since, now) {
+			continue
+		}
+		item := Item{ID: e.ImdbID, Title: e.Title, Link: link(e.ImdbID), Published: released}
+		if e.Year != "" {
+			item.Title += " (" + e.Year + ")"
+		}
+		if e.ImdbRating != "" {
+			item.Description = "IMDb rating: " + e.ImdbRating
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+//WriteRSS writes the feed as an RSS 2.0 document, latest items first.
+func (f *Feed) WriteRSS(w io.Writer) error {
+
+	type guid struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+	type item struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link,omitempty"`
+		Description string `xml:"description,omitempty"`
+		GUID        guid   `xml:"guid"`
+		PubDate     string `xml:"pubDate"`
+	}
+	doc := struct {
+		XMLName     xml.Name `xml:"rss"`
+		Version     string   `xml:"version,attr"`
+		Title       string   `xml:"channel>title"`
+		Link        string   `xml:"channel>link"`
+		Description string   `xml:"channel>description"`
+		Items       []item   `xml:"channel>item"`
+	}{Version: "2.0", Title: f.Title, Link: f.Link, Description: f.Description}
+
+	for _, it := range f.sorted() {
+		doc.Items = append(doc.Items, item{
+			Title:       it.Title,
+			Link:        it.Link,
+			Description: it.Description,
+			GUID:        guid{Value: it.ID},
+			PubDate:     it.Published.Format(time.RFC1123Z),
+		})
+	}
+	return write(w, doc)
+}
+
+//WriteAtom writes the feed as an Atom document, latest items first.
+func (f *Feed) WriteAtom(w io.Writer) error {
+
+	type link struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+	type entry struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Link    *link  `xml:"link"`
+		Updated string `xml:"updated"`
+		Summary string `xml:"summary,omitempty"`
+	}
+	doc := struct {
+		XMLName  xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID       string   `xml:"id"`
+		Title    string   `xml:"title"`
+		Subtitle string   `xml:"subtitle,omitempty"`
+		Link     *link    `xml:"link"`
+		Updated  string   `xml:"updated"`
+		Entries  []entry  `xml:"entry"`
+	}{ID: f.Link, Title: f.Title, Subtitle: f.Description}
+
+	if f.Link != "" {
+		doc.Link = &link{Href: f.Link, Rel: "self"}
+	}
+	items := f.sorted()
+	updated := time.Now()
+	if len(items) > 0 {
+		updated = items[0].Published
+	}
+	doc.Updated = updated.UTC().Format(time.RFC3339)
+	for _, it := range items {
+		e := entry{
+			ID:      "urn:omdb:" + it.ID,
+			Title:   it.Title,
+			Updated: it.Published.UTC().Format(time.RFC3339),
+			Summary: it.Description,
+		}
+		if it.Link != "" {
+			e.Link = &link{Href: it.Link}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+	return write(w, doc)
+}
+
+//WriteFile writes the feed to the file at path, as Atom when its extension
+//is ".atom", as RSS otherwise. The file is replaced at once, so a server
+//never serves it half written.
+func (f *Feed) WriteFile(path string) error {
+
+	buf := &bytes.Buffer{}
+	var err error
+	if filepath.Ext(path) == ".atom" {
+		err = f.WriteAtom(buf)
+	} else {
+		err = f.WriteRSS(buf)
+	}
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+//Handler serves the feed build returns for every request, as Atom when the
+//format query parameter is "atom", as RSS otherwise.
+func Handler(build func(r *http.Request) (*Feed, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		f, err := build(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		buf := &bytes.Buffer{}
+		contentType := "application/rss+xml; charset=utf-8"
+		if r.URL.Query().Get("format") == "atom" {
+			contentType = "application/atom+xml; charset=utf-8"
+			err = f.WriteAtom(buf)
+		} else {
+			err = f.WriteRSS(buf)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+//sorted returns the items, latest first.
+func (f *Feed) sorted() []Item {
+	items := append([]Item(nil), f.Items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Published.After(items[j].Published) })
+	return items
+}
+
+func write(w io.Writer, doc interface{}) error {
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+//within reports whether the day t is from since until now.
+func within(t, since, now time.Time) bool {
+	return !t.Before(day(since)) && !t.After(now)
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func link(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "https://www.imdb.com/title/" + id + "/"
+}
@@ -0,0 +1,125 @@
+package feed_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb/feed"
+	"github.com/ahin/omdb/tracker"
+	"github.com/ahin/omdb/watchlist"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func testFeed() *feed.Feed {
+
+	since := now.AddDate(0, 0, -30)
+	f := &feed.Feed{Title: "New releases", Link: "https://example.com/feed", Description: "Tracked titles"}
+	f.Add(feed.Episodes([]tracker.Series{{ImdbID: "tt11280740", Title: "Severance", Episodes: []tracker.Episode{
+		{Season: 2, Episode: 9, Title: "The After Hours", ImdbID: "tt14815464", Released: "14 Oct 2026"},
+		{Season: 2, Episode: 10, Released: "20 Oct 2026"},
+		{Season: 2, Episode: 8, Title: "Sweet Vitriol", Released: "01 Aug 2026"},
+		{Season: 2, Episode: 11},
+	}}}, since, now)...)
+	f.Add(feed.Movies([]watchlist.Entry{
+		{ImdbID: "tt0133093", Title: "The Matrix 5", Type: "movie", Year: "2026", Released: "02 Oct 2026", ImdbRating: "7.9"},
+		{ImdbID: "tt0000002", Title: "A Show", Type: "series", Released: "03 Oct 2026"},
+		{ImdbID: "tt0000003", Title: "Old", Type: "movie", Released: "03 Oct 2020"},
+	}, since, now)...)
+	return f
+}
+
+func TestItems(t *testing.T) {
+
+	f := testFeed()
+	if len(f.Items) != 2 {
+		t.Fatalf("got items %+v, want the aired episode and the released movie", f.Items)
+	}
+	if e := f.Items[0]; e.ID != "tt14815464" || e.Title != "Severance S02E09 - The After Hours" || e.Link != "https://www.imdb.com/title/tt14815464/" {
+		t.Errorf("got episode %+v", e)
+	}
+	if m := f.Items[1]; m.Title != "The Matrix 5 (2026)" || m.Description != "IMDb rating: 7.9" {
+		t.Errorf("got movie %+v", m)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	if err := testFeed().WriteRSS(buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Title   string `xml:"channel>title"`
+		Items   []struct {
+			Title   string `xml:"title"`
+			GUID    string `xml:"guid"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "2.0" || doc.Title != "New releases" || len(doc.Items) != 2 {
+		t.Fatalf("got %+v", doc)
+	}
+	//latest first.
+	if doc.Items[0].GUID != "tt14815464" || doc.Items[0].PubDate != "Wed, 14 Oct 2026 00:00:00 +0000" || doc.Items[1].GUID != "tt0133093" {
+		t.Errorf("got items %+v", doc.Items)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	if err := testFeed().WriteAtom(buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Updated != "2026-10-14T00:00:00Z" || len(doc.Entries) != 2 || doc.Entries[0].ID != "urn:omdb:tt14815464" {
+		t.Errorf("got %+v", doc)
+	}
+}
+
+func TestWriteFileAndHandler(t *testing.T) {
+
+	f := testFeed()
+	path := filepath.Join(t.TempDir(), "feed.atom")
+	if err := f.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(data, []byte("http://www.w3.org/2005/Atom")) {
+		t.Errorf("got %s, %v, want an Atom feed", data, err)
+	}
+
+	h := feed.Handler(func(r *http.Request) (*feed.Feed, error) { return f, nil })
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/feed?format=atom", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("got Content-Type %q", ct)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/feed", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") || !strings.Contains(rec.Body.String(), "<rss") {
+		t.Errorf("got Content-Type %q and\n%s", ct, rec.Body)
+	}
+}
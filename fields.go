@@ -0,0 +1,155 @@
+package omdb
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//dateLayout is the format of the dates reported by OMDB, e.g. "14 Oct 1994".
+const dateLayout = "02 Jan 2006"
+
+//TypedFields holds the numeric and date fields of a result parsed from their
+//string form. A field OMDB reports as "N/A", or which can't be parsed, is
+//left at its zero value.
+type TypedFields struct {
+	Runtime    time.Duration
+	Released   time.Time
+	DVD        time.Time //movies only
+	ImdbRating float64
+	ImdbVotes  int
+	Metascore  int
+}
+
+//typedResult is a Result with parsed numeric and date fields.
+type typedResult interface {
+	Typed() TypedFields
+}
+
+//Typed returns the parsed Runtime, Released, DVD, ImdbRating, ImdbVotes and
+//Metascore of the movie.
+func (m MovieResult) Typed() TypedFields {
+	t := typedFields(m.Runtime, m.Released, m.ImdbRating, m.ImdbVotes, m.Metascore)
+	t.DVD = parseDate(m.DVD)
+	return t
+}
+
+//Typed returns the parsed Runtime, Released, ImdbRating, ImdbVotes and
+//Metascore of the series.
+func (s SeriesResult) Typed() TypedFields {
+	return typedFields(s.Runtime, s.Released, s.ImdbRating, s.ImdbVotes, s.Metascore)
+}
+
+//Typed returns the parsed Runtime, Released, ImdbRating, ImdbVotes and
+//Metascore of the episode.
+func (e EpisodeResult) Typed() TypedFields {
+	return typedFields(e.Runtime, e.Released, e.ImdbRating, e.ImdbVotes, e.Metascore)
+}
+
+func typedFields(runtime, released, rating, votes, metascore string) TypedFields {
+	t := TypedFields{
+		Runtime:  parseRuntime(runtime),
+		Released: parseDate(released),
+	}
+	t.ImdbRating, _ = strconv.ParseFloat(notAvailable(rating), 64)
+	t.ImdbVotes, _ = strconv.Atoi(strings.ReplaceAll(notAvailable(votes), ",", ""))
+	t.Metascore, _ = strconv.Atoi(notAvailable(metascore))
+	return t
+}
+
+//parseRuntime parses runtimes like "142 min", "1 h 30 min" or "2 h".
+func parseRuntime(s string) time.Duration {
+
+	var d time.Duration
+	fields := strings.Fields(notAvailable(s))
+	if len(fields)%2 != 0 {
+		return 0
+	}
+	for i := 0; i < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0
+		}
+		switch fields[i+1] {
+		case "min":
+			d += time.Duration(n) * time.Minute
+		case "h":
+			d += time.Duration(n) * time.Hour
+		default:
+			return 0
+		}
+	}
+	return d
+}
+
+func parseDate(s string) time.Time {
+	t, _ := time.Parse(dateLayout, notAvailable(s))
+	return t
+}
+
+//splitList splits a comma separated field like Genre into its trimmed items,
+//nil when the field is "N/A" or empty.
+func splitList(s string) []string {
+
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" && item != "N/A" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+//Genres returns the genres of the movie split from Genre.
+func (m MovieResult) Genres() []string { return splitList(m.Genre) }
+
+//DirectorsList returns the directors of the movie split from Director.
+func (m MovieResult) DirectorsList() []string { return splitList(m.Director) }
+
+//WritersList returns the writers of the movie split from Writer.
+func (m MovieResult) WritersList() []string { return splitList(m.Writer) }
+
+//ActorsList returns the actors of the movie split from Actors.
+func (m MovieResult) ActorsList() []string { return splitList(m.Actors) }
+
+//Languages returns the languages of the movie split from Language.
+func (m MovieResult) Languages() []string { return splitList(m.Language) }
+
+//Countries returns the countries of the movie split from Country.
+func (m MovieResult) Countries() []string { return splitList(m.Country) }
+
+//Genres returns the genres of the series split from Genre.
+func (s SeriesResult) Genres() []string { return splitList(s.Genre) }
+
+//DirectorsList returns the directors of the series split from Director.
+func (s SeriesResult) DirectorsList() []string { return splitList(s.Director) }
+
+//WritersList returns the writers of the series split from Writer.
+func (s SeriesResult) WritersList() []string { return splitList(s.Writer) }
+
+//ActorsList returns the actors of the series split from Actors.
+func (s SeriesResult) ActorsList() []string { return splitList(s.Actors) }
+
+//Languages returns the languages of the series split from Language.
+func (s SeriesResult) Languages() []string { return splitList(s.Language) }
+
+//Countries returns the countries of the series split from Country.
+func (s SeriesResult) Countries() []string { return splitList(s.Country) }
+
+//Genres returns the genres of the episode split from Genre.
+func (e EpisodeResult) Genres() []string { return splitList(e.Genre) }
+
+//DirectorsList returns the directors of the episode split from Director.
+func (e EpisodeResult) DirectorsList() []string { return splitList(e.Director) }
+
+//WritersList returns the writers of the episode split from Writer.
+func (e EpisodeResult) WritersList() []string { return splitList(e.Writer) }
+
+//ActorsList returns the actors of the episode split from Actors.
+func (e EpisodeResult) ActorsList() []string { return splitList(e.Actors) }
+
+//Languages returns the languages of the episode split from Language.
+func (e EpisodeResult) Languages() []string { return splitList(e.Language) }
+
+//Countries returns the countries of the episode split from Country.
+func (e EpisodeResult) Countries() []string { return splitList(e.Country) }
@@ -0,0 +1,80 @@
+package omdb
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//Gzip is a Middleware asking OMDB for gzip compressed responses and
+//decompressing them. http.Transport already does so by default; Gzip is for
+//http.Clients with DisableCompression set or another RoundTripper. Requests
+//which already set Accept-Encoding are left alone. The maximum response size
+//of the client applies to the decompressed body.
+func Gzip() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Accept-Encoding") != "" {
+				return next(req)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("Accept-Encoding", "gzip")
+			res, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			return gunzip(res)
+		}
+	}
+}
+
+//GzipTransport wraps base, http.DefaultTransport when nil, to send requests
+//like Gzip does, e.g. for an http.Client shared with other code:
+//
+//	client := &http.Client{Transport: omdb.GzipTransport(transport)}
+func GzipTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return gzipTransport{base}
+}
+
+type gzipTransport struct {
+	base http.RoundTripper
+}
+
+func (t gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return Gzip()(t.base.RoundTrip)(req)
+}
+
+//gunzip replaces the body of a gzip encoded response with its decompressed
+//content.
+func gunzip(res *http.Response) (*http.Response, error) {
+
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return res, nil
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	res.Body = &gzipBody{Reader: zr, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return res, nil
+}
+
+//gzipBody closes the compressed body along with the gzip.Reader.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,102 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+//hedgedDo is do, except that when no response arrived after the hedging delay
+//of the client a second identical request is sent. The first successful
+//response wins and the other request is cancelled; when both fail the first
+//error is returned. The second request goes through the rate limiters and the
+//quota tracker like any other, but it is only sent when they allow it right
+//away: a hedge never waits for its turn.
+func (c *Client) hedgedDo(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+
+	type result struct {
+		i   int
+		res *http.Response
+		err error
+	}
+	results := make(chan result, 2)
+	var cancels []context.CancelFunc
+	start := func() {
+		ctx, cancel := context.WithCancel(ctx)
+		i := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			res, err := c.do(ctx, url, header)
+			results <- result{i, res, err}
+		}()
+	}
+
+	start()
+	pending := 1
+
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			if pending == 1 && len(cancels) == 1 && c.allowHedge(ctx) {
+				start()
+				pending++
+			}
+
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				cancels[r.i]()
+				if firstErr == nil {
+					firstErr = r.err
+				}
+				if pending == 0 {
+					return nil, firstErr
+				}
+				continue
+			}
+
+			//cancel the loser and close its response should it still
+			//arrive.
+			for i, cancel := range cancels {
+				if i != r.i {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go func() {
+					if l := <-results; l.res != nil {
+						l.res.Body.Close()
+					}
+				}()
+			}
+
+			r.res.Body = &cancelBody{ReadCloser: r.res.Body, cancel: cancels[r.i]}
+			return r.res, nil
+		}
+	}
+}
+
+//allowHedge takes a token of every rate limiter and of the quota for a hedged
+//request, when they all have one available right away.
+func (c *Client) allowHedge(ctx context.Context) bool {
+
+	for i, l := range c.limiters {
+		if !l.allow() {
+			for _, taken := range c.limiters[:i] {
+				taken.refund()
+			}
+			return false
+		}
+	}
+	if c.quota != nil && c.quota.take(ctx) != nil {
+		for _, l := range c.limiters {
+			l.refund()
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//newSlowFirstServer returns a server answering its first request after
+//delay and the others right away.
+func newSlowFirstServer(delay time.Duration, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Title":"Movie","Year":"1999","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+}
+
+func TestHedgingSendsSecondRequest(t *testing.T) {
+
+	var requests atomic.Int32
+	srv := newSlowFirstServer(time.Second, &requests)
+	defer srv.Close()
+	c := NewClient("key", srv.Client(), WithBaseURL(srv.URL+"/"), WithHedging(20*time.Millisecond))
+
+	start := time.Now()
+	if _, err := c.GetMovieByID(context.Background(), "tt0133093"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("lookup took %v, the hedged request should have answered", elapsed)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
+
+func TestHedgingRespectsRateLimit(t *testing.T) {
+
+	var requests atomic.Int32
+	srv := newSlowFirstServer(100*time.Millisecond, &requests)
+	defer srv.Close()
+	c := NewClient("key", srv.Client(), WithBaseURL(srv.URL+"/"),
+		WithHedging(20*time.Millisecond), WithRateLimit(Every(time.Minute), 1))
+
+	if _, err := c.GetMovieByID(context.Background(), "tt0133093"); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, the rate limit allows 1", n)
+	}
+}
+
+func TestHedgingRespectsQuota(t *testing.T) {
+
+	var requests atomic.Int32
+	srv := newSlowFirstServer(100*time.Millisecond, &requests)
+	defer srv.Close()
+	quota := NewQuotaTracker(1, nil)
+	c := NewClient("key", srv.Client(), WithBaseURL(srv.URL+"/"),
+		WithHedging(20*time.Millisecond), WithDailyQuota(quota))
+
+	if _, err := c.GetMovieByID(context.Background(), "tt0133093"); err != nil {
+		t.Fatal(err)
+	}
+	if n, used := requests.Load(), quota.Used(); n != 1 || used != 1 {
+		t.Errorf("server got %d requests, quota used %d, want 1 of each", n, used)
+	}
+}
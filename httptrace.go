@@ -0,0 +1,140 @@
+package omdb
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+//Timings is the latency breakdown of a single HTTP request, see
+//WithHTTPTrace. A phase which didn't happen, like DNS on a reused
+//connection, is zero.
+type Timings struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	TTFB     time.Duration //from sending the request to the first response byte
+	BodyRead time.Duration //from the first response byte to closing the body
+	Total    time.Duration
+	Reused   bool //the connection was reused
+}
+
+//TimingsMetrics is implemented by a Metrics which also wants the Timings of
+//the requests traced with WithHTTPTrace.
+type TimingsMetrics interface {
+	Metrics
+	Timings(op string, t Timings)
+}
+
+//WithHTTPTrace measures the phases of every HTTP request with
+//net/http/httptrace and reports their Timings to the logger of the client,
+//as an "omdb request timings" event, and to its Metrics when it implements
+//TimingsMetrics.
+func WithHTTPTrace() Option {
+	return func(c *Client) error {
+		c.httpTrace = true
+		return nil
+	}
+}
+
+//requestTimer records the Timings of a request.
+type requestTimer struct {
+	mu                     sync.Mutex
+	start, dnsStart        time.Time
+	connectStart, tlsStart time.Time
+	wrote, firstByte       time.Time
+	t                      Timings
+}
+
+//withTimer returns ctx tracing the request made with it into a new timer.
+func withTimer(ctx context.Context) (context.Context, *requestTimer) {
+
+	r := &requestTimer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { r.mark(&r.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { r.since(&r.t.DNS, r.dnsStart) },
+		ConnectStart: func(string, string) {
+			r.mark(&r.connectStart)
+		},
+		ConnectDone: func(string, string, error) {
+			r.since(&r.t.Connect, r.connectStart)
+		},
+		TLSHandshakeStart: func() { r.mark(&r.tlsStart) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			r.since(&r.t.TLS, r.tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mu.Lock()
+			r.t.Reused = info.Reused
+			r.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) { r.mark(&r.wrote) },
+		GotFirstResponseByte: func() {
+			r.mark(&r.firstByte)
+			r.since(&r.t.TTFB, r.wrote)
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), r
+}
+
+func (r *requestTimer) mark(t *time.Time) {
+	r.mu.Lock()
+	*t = time.Now()
+	r.mu.Unlock()
+}
+
+//since sets d to the time elapsed since from, unless from wasn't marked.
+func (r *requestTimer) since(d *time.Duration, from time.Time) {
+	r.mu.Lock()
+	if !from.IsZero() {
+		*d = time.Since(from)
+	}
+	r.mu.Unlock()
+}
+
+//done completes the Timings once the body is closed.
+func (r *requestTimer) done() Timings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.firstByte.IsZero() {
+		r.t.BodyRead = time.Since(r.firstByte)
+	}
+	r.t.Total = time.Since(r.start)
+	return r.t
+}
+
+//reportTimings sends t to the logger and metrics of the client.
+func (c *Client) reportTimings(ctx context.Context, op string, t Timings) {
+	if m, ok := c.metrics.(TimingsMetrics); ok {
+		m.Timings(op, t)
+	}
+	if c.logger == nil {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, "omdb request timings",
+		slog.String("op", op),
+		slog.Duration("dns", t.DNS),
+		slog.Duration("connect", t.Connect),
+		slog.Duration("tls", t.TLS),
+		slog.Duration("ttfb", t.TTFB),
+		slog.Duration("body_read", t.BodyRead),
+		slog.Duration("duration", t.Total),
+		slog.Bool("reused", t.Reused))
+}
+
+//timedBody reports the Timings of its request once closed.
+type timedBody struct {
+	io.ReadCloser
+	once   sync.Once
+	report func()
+}
+
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.report)
+	return err
+}
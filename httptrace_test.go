@@ -0,0 +1,65 @@
+package omdb_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//timingsMetrics records the Timings reported to a TimingsMetrics.
+type timingsMetrics struct {
+	mu      sync.Mutex
+	ops     []string
+	timings []omdb.Timings
+}
+
+func (m *timingsMetrics) Request(op string, latency time.Duration, errClass string) {}
+func (m *timingsMetrics) Cache(op string, hit bool)                                 {}
+func (m *timingsMetrics) QuotaRemaining(remaining int)                              {}
+
+func (m *timingsMetrics) Timings(op string, t omdb.Timings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops = append(m.ops, op)
+	m.timings = append(m.timings, t)
+}
+
+func TestHTTPTrace(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"})
+
+	metrics := &timingsMetrics{}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := srv.Client(omdb.WithHTTPTrace(), omdb.WithMetrics(metrics), omdb.WithLogger(logger))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbID(omdb.QueryData{ImdbID: "tt0133093"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.timings) != 2 || metrics.ops[0] != omdb.OpByID {
+		t.Fatalf("got timings %v for ops %v, want 2 lookups by id", metrics.timings, metrics.ops)
+	}
+	first, second := metrics.timings[0], metrics.timings[1]
+	if first.Connect <= 0 || first.Reused || first.TTFB <= 0 || first.Total < first.TTFB {
+		t.Errorf("got first timings %+v, want a new connection", first)
+	}
+	if !second.Reused || second.Connect != 0 || second.TTFB <= 0 {
+		t.Errorf("got second timings %+v, want the connection reused", second)
+	}
+	if n := strings.Count(logs.String(), "omdb request timings"); n != 2 {
+		t.Errorf("got %d timings logged, want 2:\n%s", n, logs.String())
+	}
+}
@@ -0,0 +1,145 @@
+//Package imdbcsv reads the CSV files IMDb exports for the lists of a user,
+//like the watchlist or the ratings, and hydrates every title they name with
+//its full OMDB result, e.g. to feed an omdb.JSONLWriter:
+//
+//	entries, err := imdbcsv.Read(f)
+//	...
+//	w := omdb.NewJSONLWriter(os.Stdout)
+//	for _, t := range imdbcsv.Hydrate(ctx, client, entries) {
+//		if t.Err == nil {
+//			err = w.Write(t.Result)
+//		}
+//	}
+//
+//The lookups go through the client one after the other, so its cache and
+//rate limiters apply and hydrating an export again is served from the cache.
+package imdbcsv
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//Columns of the export files read by Read.
+const (
+	ColumnConst      = "Const"
+	ColumnTitle      = "Title"
+	ColumnTitleType  = "Title Type"
+	ColumnYourRating = "Your Rating"
+	ColumnDateRated  = "Date Rated"
+	ColumnPosition   = "Position"
+	ColumnCreated    = "Created"
+)
+
+//Entry is a title of an export file.
+type Entry struct {
+	ImdbID string
+	Title  string
+	//TitleType is the kind of title as IMDb names it, e.g. "Movie",
+	//"TV Series" or "Short".
+	TitleType string
+	//YourRating is the rating of the user out of 10, 0 when the title isn't
+	//rated, and DateRated when it was rated, as "2006-01-02".
+	YourRating int
+	DateRated  string
+	//Position is the position of the title in a list like the watchlist,
+	//0 in the ratings, and Created when it was added.
+	Position int
+	Created  string
+
+	//Record holds the cells of the entry as read.
+	Record []string
+}
+
+//Title is an Entry with its OMDB result.
+type Title struct {
+	Entry
+	//Result is the omdb.MovieResult, omdb.SeriesResult or omdb.EpisodeResult
+	//of the entry, nil when the lookup failed with Err.
+	Result interface{}
+	Err    error
+}
+
+//Movie returns the result of a movie, nil for any other title.
+func (t Title) Movie() *omdb.MovieResult {
+	if m, ok := t.Result.(omdb.MovieResult); ok {
+		return &m
+	}
+	return nil
+}
+
+//Read reads an export file, which needs the Const column holding the IMDb
+//ids. Rows without a valid id are skipped.
+func Read(r io.Reader) ([]Entry, error) {
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	col := make(map[string]int)
+	for i, name := range header {
+		col[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := col[ColumnConst]; !ok {
+		return nil, errors.New("imdbcsv: Missing column " + ColumnConst)
+	}
+	cell := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []Entry
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := omdb.NormalizeImdbID(cell(record, ColumnConst))
+		if err != nil {
+			continue
+		}
+		entry := Entry{
+			ImdbID:    id,
+			Title:     cell(record, ColumnTitle),
+			TitleType: cell(record, ColumnTitleType),
+			DateRated: cell(record, ColumnDateRated),
+			Created:   cell(record, ColumnCreated),
+			Record:    record,
+		}
+		entry.YourRating, _ = strconv.Atoi(cell(record, ColumnYourRating))
+		entry.Position, _ = strconv.Atoi(cell(record, ColumnPosition))
+		entries = append(entries, entry)
+	}
+}
+
+//Hydrate looks up every entry by its ImdbID with c, one after the other. A
+//failed lookup is reported in the Err of its Title rather than stopping the
+//others; when ctx is done, the remaining titles fail with its error.
+func Hydrate(ctx context.Context, c omdb.API, entries []Entry, opts ...omdb.CallOption) []Title {
+
+	titles := make([]Title, len(entries))
+	for i, e := range entries {
+		titles[i].Entry = e
+		if err := ctx.Err(); err != nil {
+			titles[i].Err = err
+			continue
+		}
+		titles[i].Result, titles[i].Err = c.SearchByImdbIDContext(ctx, omdb.QueryData{ImdbID: e.ImdbID}, opts...)
+	}
+	return titles
+}
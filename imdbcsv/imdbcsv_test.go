@@ -0,0 +1,89 @@
+package imdbcsv_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/imdbcsv"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//watchlist is a WATCHLIST.csv as exported by IMDb.
+const watchlist = "\ufeffPosition,Const,Created,Modified,Description,Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated\n" +
+	"1,tt0133093,2023-05-01,2023-05-01,,The Matrix,https://www.imdb.com/title/tt0133093/,Movie,8.7,136,1999,\"Action, Sci-Fi\",2000000,1999-03-31,\"Lana Wachowski, Lilly Wachowski\",9,2023-06-01\n" +
+	"2,tt0903747,2023-05-02,2023-05-02,,Breaking Bad,https://www.imdb.com/title/tt0903747/,TV Series,9.5,49,2008,\"Crime, Drama, Thriller\",2100000,2008-01-20,,,\n" +
+	"3,,2023-05-03,2023-05-03,,No Id,,Movie,,,,,,,,,\n" +
+	"4,tt0000001,2023-05-04,2023-05-04,,Carmencita,https://www.imdb.com/title/tt0000001/,Short,5.7,1,1894,\"Documentary, Short\",2100,1894-03-10,William K.L. Dickson,,\n"
+
+func TestRead(t *testing.T) {
+
+	entries, err := imdbcsv.Read(strings.NewReader(watchlist))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want the 3 with an id", len(entries))
+	}
+	e := entries[0]
+	if e.ImdbID != "tt0133093" || e.Title != "The Matrix" || e.TitleType != "Movie" || e.YourRating != 9 ||
+		e.DateRated != "2023-06-01" || e.Position != 1 || e.Created != "2023-05-01" || len(e.Record) != 17 {
+		t.Errorf("got %+v", e)
+	}
+	if e := entries[1]; e.ImdbID != "tt0903747" || e.TitleType != "TV Series" || e.YourRating != 0 || e.Position != 2 {
+		t.Errorf("got %+v", e)
+	}
+
+	if _, err := imdbcsv.Read(strings.NewReader("Title,Year\nThe Matrix,1999\n")); err == nil {
+		t.Error("got no error for an export without Const")
+	}
+}
+
+func TestHydrate(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747", TotalSeasons: "5"})
+
+	ctx := context.Background()
+	entries, err := imdbcsv.Read(strings.NewReader(watchlist))
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := imdbcsv.Hydrate(ctx, srv.Client(), entries)
+
+	if m := titles[0].Movie(); titles[0].Err != nil || m == nil || m.Title != "The Matrix" {
+		t.Errorf("got %+v", titles[0])
+	}
+	if s, ok := titles[1].Result.(omdb.SeriesResult); !ok || s.TotalSeasons != "5" || titles[1].Movie() != nil {
+		t.Errorf("got %+v", titles[1])
+	}
+	//a failed lookup doesn't stop the others.
+	if titles[2].Result != nil || !errors.Is(titles[2].Err, omdb.ErrNotFound) || titles[2].Title != "Carmencita" {
+		t.Errorf("got %+v, want ErrNotFound", titles[2])
+	}
+
+	//hydrating again is served from the cache.
+	client := srv.Client(omdb.WithCache(omdb.NewLRUCache(10), 0))
+	imdbcsv.Hydrate(ctx, client, entries[:2])
+	requests := srv.Requests()
+	for _, title := range imdbcsv.Hydrate(ctx, client, entries[:2]) {
+		if title.Err != nil {
+			t.Error(title.Err)
+		}
+	}
+	if srv.Requests() != requests {
+		t.Errorf("got %d requests hydrating again", srv.Requests()-requests)
+	}
+
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, title := range imdbcsv.Hydrate(done, srv.Client(), entries) {
+		if !errors.Is(title.Err, context.Canceled) {
+			t.Errorf("%s: got error %v, want context.Canceled", title.ImdbID, title.Err)
+		}
+	}
+}
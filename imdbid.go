@@ -0,0 +1,99 @@
+package omdb
+
+import (
+	"net/url"
+	"strings"
+)
+
+//InvalidIDError is returned, before any request is sent, for an ImdbID which
+//isn't "tt" followed by 7 or 8 digits. It matches ErrInvalidRequest.
+type InvalidIDError struct {
+	ID string
+}
+
+func (e *InvalidIDError) Error() string {
+	return "omdb: Invalid ImdbID " + e.ID + ", it should look like tt0133093"
+}
+
+func (e *InvalidIDError) Unwrap() error { return ErrInvalidRequest }
+
+//NormalizeImdbID returns id as OMDB expects it: surrounding whitespace is
+//trimmed, the "tt" prefix is lowercased, and a number without prefix, e.g.
+//"0133093" or "133093", gets the prefix and is padded to 7 digits. The URL of
+//a title on IMDb is accepted too, see ImdbIDFromURL. An id which doesn't come
+//down to "tt" followed by 7 or 8 digits is an InvalidIDError.
+func NormalizeImdbID(id string) (string, error) {
+
+	s := strings.TrimSpace(id)
+	if strings.Contains(s, "/") {
+		return ImdbIDFromURL(s)
+	}
+	if len(s) >= 2 && strings.EqualFold(s[:2], "tt") {
+		s = "tt" + s[2:]
+	} else if digits(s) && len(s) > 0 && len(s) <= 8 {
+		for len(s) < 7 {
+			s = "0" + s
+		}
+		s = "tt" + s
+	}
+
+	if !ValidImdbID(s) {
+		return "", &InvalidIDError{ID: id}
+	}
+	return s, nil
+}
+
+//ImdbIDFromURL extracts the title id from the URL of a title on IMDb, like
+//"https://www.imdb.com/title/tt0133093/" or
+//"m.imdb.com/title/tt0133093/reviews?ref_=tt_ov", with or without scheme.
+//A URL which isn't one of a title is an InvalidIDError.
+func ImdbIDFromURL(rawURL string) (string, error) {
+
+	s := strings.TrimSpace(rawURL)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", &InvalidIDError{ID: rawURL}
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "imdb.com" && !strings.HasSuffix(host, ".imdb.com") {
+		return "", &InvalidIDError{ID: rawURL}
+	}
+
+	//the path is /title/tt0133093/..., optionally after a language like
+	//"de".
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "title" && ValidImdbID(strings.ToLower(parts[i+1])) {
+			return strings.ToLower(parts[i+1]), nil
+		}
+	}
+	return "", &InvalidIDError{ID: rawURL}
+}
+
+//normalizeID is NormalizeImdbID, except that a missing id is left for the
+//validation of the query to report.
+func normalizeID(id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+	return NormalizeImdbID(id)
+}
+
+//ValidImdbID reports whether id is "tt" followed by 7 or 8 digits, the
+//format of the IMDb ids of titles.
+func ValidImdbID(id string) bool {
+	return strings.HasPrefix(id, "tt") && len(id) >= 9 && len(id) <= 10 && digits(id[2:])
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
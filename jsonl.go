@@ -0,0 +1,217 @@
+package omdb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+//JSONLWriter writes results as JSON Lines, one JSON object per line, for
+//tools like jq or BigQuery. A MovieResult, SeriesResult or EpisodeResult is
+//written like the OMDB response it came from, with its Type and Response,
+//so the lines can be read back by a JSONLReader or into a cache by
+//ImportJSONL. A result kept with WithRawResponse is written as received.
+type JSONLWriter struct {
+	w io.Writer
+}
+
+//NewJSONLWriter returns a JSONLWriter writing to w. Every line is a single
+//write to w, which should be buffered for large outputs.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{w: w}
+}
+
+//Write writes v, a MovieResult, SeriesResult, EpisodeResult or SearchResult,
+//or a pointer to one, as a line. A SearchResponse is written as a line per
+//SearchResult, and a nil value not at all.
+func (w *JSONLWriter) Write(v interface{}) error {
+
+	var (
+		typ string
+		raw []byte
+	)
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		v = rv.Elem().Interface()
+	}
+
+	switch r := v.(type) {
+	case nil:
+		return nil
+	case SearchResponse:
+		for _, s := range r.Search {
+			if err := w.Write(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case SearchResult:
+		return w.line(r)
+	case MovieResult:
+		typ, raw = string(TypeMovie), r.Raw
+	case SeriesResult:
+		typ, raw = string(TypeSeries), r.Raw
+	case EpisodeResult:
+		typ, raw = string(TypeEpisode), r.Raw
+	default:
+		return errors.New("omdb: Can't write a " + reflect.TypeOf(v).String() + " as JSON Lines")
+	}
+
+	if len(raw) > 0 && raw[0] == '{' {
+		buf := &bytes.Buffer{}
+		if err := json.Compact(buf, raw); err == nil {
+			buf.WriteByte('\n')
+			_, err = w.w.Write(buf.Bytes())
+			return err
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	//the envelope fields go first, like in the responses of OMDB.
+	head := `{"Type":` + strconv.Quote(typ) + `,"Response":"True"`
+	if len(data) > 2 {
+		head += ","
+	}
+	line := append([]byte(head), data[1:]...)
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
+
+func (w *JSONLWriter) line(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
+
+//JSONLReader reads the lines written by a JSONLWriter, or saved from OMDB
+//responses one per line.
+type JSONLReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+//NewJSONLReader returns a JSONLReader reading from r.
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{r: bufio.NewReader(r)}
+}
+
+//Read returns the result of the next line: a MovieResult, SeriesResult or
+//EpisodeResult for a lookup response, told apart by its Type, or a
+//SearchResult for a line without Response. Blank lines are skipped. io.EOF
+//is returned after the last line.
+func (r *JSONLReader) Read() (interface{}, error) {
+
+	data, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	res, err := decodeJSONLine(data)
+	if err != nil {
+		return nil, r.lineError(err)
+	}
+	return res, nil
+}
+
+func (r *JSONLReader) lineError(err error) error {
+	return &wrappedError{msg: "omdb: JSON Lines line " + strconv.Itoa(r.line) + ": " + err.Error(), err: err}
+}
+
+//next returns the next non blank line.
+func (r *JSONLReader) next() ([]byte, error) {
+
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) > 0 {
+			r.line++
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func decodeJSONLine(data []byte) (interface{}, error) {
+
+	var envelope resultEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Response == "" {
+		var s SearchResult
+		err := json.Unmarshal(data, &s)
+		return s, err
+	}
+
+	res, err := decodeJSONResult(data, false)
+	if err == nil && res == nil {
+		err = errors.New("unknown Type " + strconv.Quote(envelope.Type))
+	}
+	return res, err
+}
+
+//ImportJSONL stores the lookup responses of the JSON Lines read from r in the
+//cache of the client, as if they had been looked up by ImdbID with the
+//defaults of the client, so a previous export is served without requests.
+//SearchResult lines are skipped, the number of imported responses is
+//returned. It requires WithCache and the JSON format.
+func (c *Client) ImportJSONL(ctx context.Context, r io.Reader) (int, error) {
+
+	if c.cache == nil {
+		return 0, errors.New("omdb: ImportJSONL requires a cache, see WithCache")
+	}
+	if c.format != FormatJSON {
+		return 0, errors.New("omdb: ImportJSONL requires the JSON format")
+	}
+
+	lines := NewJSONLReader(r)
+	n := 0
+	for {
+		data, err := lines.next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		res, err := decodeJSONLine(data)
+		if err != nil {
+			return n, lines.lineError(err)
+		}
+		id := ""
+		switch r := res.(type) {
+		case MovieResult:
+			id = r.ImdbID
+		case SeriesResult:
+			id = r.ImdbID
+		case EpisodeResult:
+			id = r.ImdbID
+		}
+		if id, err = normalizeID(id); err != nil || id == "" {
+			continue
+		}
+
+		key := queryParams(OpByID, QueryData{ImdbID: id, Plot: c.defaultPlot}).Encode()
+		c.store(ctx, key, &cacheEntry{body: data}, c.cacheTTL)
+		n++
+	}
+}
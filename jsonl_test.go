@@ -0,0 +1,97 @@
+package omdb_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//ttlCache records the ttl of every Set.
+type ttlCache struct {
+	omdb.Cache
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func (c *ttlCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	c.ttls[key] = ttl
+	c.mu.Unlock()
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", ImdbRating: "8.7"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747", TotalSeasons: "5"})
+	srv.AddEpisode(omdb.EpisodeResult{Title: "Pilot", ImdbID: "tt0959621", SeriesID: "tt0903747", Season: "1", Episode: "1"})
+
+	ctx := context.Background()
+	var buf bytes.Buffer
+	w := omdb.NewJSONLWriter(&buf)
+	for _, id := range []string{"tt0133093", "tt0903747", "tt0959621"} {
+		res, err := srv.Client().SearchByImdbID(omdb.QueryData{ImdbID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//search results and blank lines are skipped on import.
+	if err := w.Write(&omdb.SearchResponse{Search: []omdb.SearchResult{{Title: "The Matrix", ImdbID: "tt0133093"}}}); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("\n")
+
+	cache := &ttlCache{Cache: omdb.NewLRUCache(10), ttls: map[string]time.Duration{}}
+	client := srv.Client(omdb.WithCache(cache, 6*time.Hour))
+	n, err := client.ImportJSONL(ctx, strings.NewReader(buf.String()))
+	if err != nil || n != 3 {
+		t.Fatalf("got %d, %v, want 3 imported", n, err)
+	}
+	for key, ttl := range cache.ttls {
+		if ttl != 6*time.Hour {
+			t.Errorf("%s imported with ttl %v, want the ttl of the client", key, ttl)
+		}
+	}
+
+	requests := srv.Requests()
+	movie, err := client.GetMovieByID(ctx, "tt0133093")
+	if err != nil || movie.Title != "The Matrix" || movie.ImdbRating != "8.7" {
+		t.Errorf("got %+v, %v", movie, err)
+	}
+	series, err := client.GetSeriesByID(ctx, "tt0903747")
+	if err != nil || series.TotalSeasons != "5" {
+		t.Errorf("got %+v, %v", series, err)
+	}
+	episode, err := client.GetEpisodeByID(ctx, "tt0959621")
+	if err != nil || episode.SeriesID != "tt0903747" || episode.Season != "1" {
+		t.Errorf("got %+v, %v", episode, err)
+	}
+	if srv.Requests() != requests {
+		t.Errorf("got %d requests, want the imports served from the cache", srv.Requests()-requests)
+	}
+
+	//the lines read back as the results written.
+	r := omdb.NewJSONLReader(strings.NewReader(buf.String()))
+	var types []string
+	for {
+		res, err := r.Read()
+		if err != nil {
+			break
+		}
+		types = append(types, omdb.TypeOf(res))
+	}
+	if got := strings.Join(types, ","); got != "movie,series,episode,unknown" {
+		t.Errorf("got types %q", got)
+	}
+}
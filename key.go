@@ -0,0 +1,25 @@
+package omdb
+
+import "strings"
+
+//ValidateKeyFormat does a local sanity check of an OMDB API key, without
+//calling the API. Keys issued by OMDB are 8 hexadecimal characters, but as
+//that isn't documented any alphanumeric key of 8 to 64 characters is
+//accepted; empty keys, keys containing whitespace or punctuation are not.
+//The error matches ErrInvalidAPIKey.
+func ValidateKeyFormat(key string) error {
+
+	if strings.TrimSpace(key) == "" {
+		return &wrappedError{msg: "omdb: API key is empty", err: ErrInvalidAPIKey}
+	}
+	if len(key) < 8 || len(key) > 64 {
+		return &wrappedError{msg: "omdb: API key should be between 8 and 64 characters long", err: ErrInvalidAPIKey}
+	}
+	for _, r := range key {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return &wrappedError{msg: "omdb: API key should only contain letters and digits", err: ErrInvalidAPIKey}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package omdb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestValidateKeyFormat(t *testing.T) {
+
+	for _, key := range []string{"1a2b3c4d", "ABCDEF0123456789", strings.Repeat("a", 64)} {
+		if err := omdb.ValidateKeyFormat(key); err != nil {
+			t.Errorf("%q: %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "   ", "1a2b3c", strings.Repeat("a", 65), "1a2b 3c4d", "1a2b-3c4d", "1a2b3c4d\n"} {
+		err := omdb.ValidateKeyFormat(key)
+		if !errors.Is(err, omdb.ErrInvalidAPIKey) {
+			t.Errorf("%q: got error %v, want ErrInvalidAPIKey", key, err)
+		}
+		if _, err := omdb.New(key); !errors.Is(err, omdb.ErrInvalidAPIKey) {
+			t.Errorf("New(%q): got error %v, want ErrInvalidAPIKey", key, err)
+		}
+	}
+}
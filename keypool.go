@@ -0,0 +1,115 @@
+package omdb
+
+import (
+	"sync"
+	"time"
+)
+
+//KeyUsage is the usage of a key of the pool given to WithAPIKeys.
+type KeyUsage struct {
+	//Key is the API key with all but its last 4 characters masked.
+	Key string
+	//Requests is the number of requests sent with the key today, UTC.
+	Requests int
+	//Exhausted reports whether OMDB rejected the key today, for reaching
+	//its limit or being invalid.
+	Exhausted bool
+}
+
+//keyPool rotates through API keys, moving to the next one when a key is
+//rejected. Rejected keys are skipped until the next day, UTC.
+type keyPool struct {
+	mu        sync.Mutex
+	keys      []string
+	current   int
+	day       string
+	requests  []int
+	exhausted []bool
+}
+
+func newKeyPool(keys []string) *keyPool {
+	return &keyPool{
+		keys:      keys,
+		requests:  make([]int, len(keys)),
+		exhausted: make([]bool, len(keys)),
+	}
+}
+
+//next returns the key to use and its index, or ErrDailyLimitExceeded when
+//every key was rejected today.
+func (p *keyPool) next() (int, string, error) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rollover()
+	for n := 0; n < len(p.keys); n++ {
+		i := (p.current + n) % len(p.keys)
+		if !p.exhausted[i] {
+			p.current = i
+			return i, p.keys[i], nil
+		}
+	}
+	return 0, "", &wrappedError{msg: "omdb: Every API key of the pool reached its limit or was rejected", err: ErrDailyLimitExceeded}
+}
+
+//count records a request sent with key i.
+func (p *keyPool) count(i int) {
+	p.mu.Lock()
+	p.rollover()
+	p.requests[i]++
+	p.mu.Unlock()
+}
+
+//exhaust marks key i as rejected for the rest of the day.
+func (p *keyPool) exhaust(i int) {
+	p.mu.Lock()
+	p.rollover()
+	p.exhausted[i] = true
+	p.mu.Unlock()
+}
+
+func (p *keyPool) usage() []KeyUsage {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rollover()
+	usage := make([]KeyUsage, len(p.keys))
+	for i, key := range p.keys {
+		usage[i] = KeyUsage{Key: maskKey(key), Requests: p.requests[i], Exhausted: p.exhausted[i]}
+	}
+	return usage
+}
+
+//rollover resets the counters when the day changed. p.mu must be held.
+func (p *keyPool) rollover() {
+	day := time.Now().UTC().Format("2006-01-02")
+	if day == p.day {
+		return
+	}
+	p.day = day
+	for i := range p.keys {
+		p.requests[i], p.exhausted[i] = 0, false
+	}
+}
+
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+	masked := []byte(key)
+	for i := 0; i < len(masked)-4; i++ {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
+
+//KeyUsage returns the usage of each key of the pool given to WithAPIKeys, in
+//order, or nil without a pool.
+func (c *Client) KeyUsage() []KeyUsage {
+	if c.keys == nil {
+		return nil
+	}
+	return c.keys.usage()
+}
@@ -0,0 +1,33 @@
+package omdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeysSkipsEmptyKeys(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != "second" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"Response":"False","Error":"Request limit reached!"}`))
+			return
+		}
+		w.Write([]byte(`{"Title":"The Matrix","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("", nil, WithBaseURL(srv.URL), WithAPIKeys("first", "", "second"))
+	if _, err := client.SearchByImdbID(QueryData{ImdbID: "tt0133093"}); err != nil {
+		t.Fatal(err)
+	}
+	usage := client.KeyUsage()
+	if len(usage) != 2 || !usage[0].Exhausted || usage[1].Exhausted || usage[1].Requests != 1 {
+		t.Errorf("got usage %+v, want first exhausted and second used", usage)
+	}
+
+	if err := NewClient("", nil, WithAPIKeys("", "")).err; err == nil {
+		t.Error("got no error without any key")
+	}
+}
@@ -0,0 +1,298 @@
+//Package letterboxd enriches the CSV files of a Letterboxd data export
+//(watched.csv, ratings.csv, diary.csv, watchlist.csv...) with OMDB: every
+//film is matched by its name and year, and the export is written back with
+//its IMDb id, runtime, director and ratings, as CSV or JSON.
+//
+//	export, err := letterboxd.Read(f)
+//	...
+//	films := letterboxd.Enrich(ctx, client, export)
+//	err = letterboxd.WriteCSV(os.Stdout, export.Header, films)
+//
+//Letterboxd doesn't export IMDb ids, so a film OMDB doesn't know under the
+//same name and year is looked for in the neighbouring years, then searched
+//by name; a name which fits several films is reported as Ambiguous.
+package letterboxd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//Columns of the export files read by Read.
+const (
+	ColumnName        = "Name"
+	ColumnYear        = "Year"
+	ColumnURI         = "Letterboxd URI"
+	ColumnRating      = "Rating"
+	ColumnWatchedDate = "Watched Date"
+)
+
+//EnrichedColumns are the columns WriteCSV appends to the export.
+var EnrichedColumns = []string{"Match", "imdbID", "Runtime", "Director", "imdbRating", "Metascore", "Rotten Tomatoes"}
+
+//Export is an export file of Letterboxd.
+type Export struct {
+	Header []string
+	Rows   []Row
+}
+
+//Row is a film of an export.
+type Row struct {
+	Name string
+	Year int
+	URI  string `json:"LetterboxdURI,omitempty"`
+	//Rating is the rating of the user out of 5, 0 when the film isn't
+	//rated.
+	Rating      float64 `json:",omitempty"`
+	WatchedDate string  `json:",omitempty"`
+
+	//Record holds the cells of the row as read.
+	Record []string `json:"-"`
+}
+
+//Match tells how a film was matched on OMDB.
+type Match int
+
+const (
+	//Exact films were found under their name and year.
+	Exact Match = iota
+	//NearYear films were found under their name a year apart, release years
+	//differing between countries.
+	NearYear
+	//Searched films were found by searching their name.
+	Searched
+	//Ambiguous films fit several search results, see Film.Candidates.
+	Ambiguous
+	//Missed films were not found.
+	Missed
+	//Failed films could not be looked up, see Film.Err.
+	Failed
+)
+
+func (m Match) String() string {
+	switch m {
+	case Exact:
+		return "exact"
+	case NearYear:
+		return "near-year"
+	case Searched:
+		return "searched"
+	case Ambiguous:
+		return "ambiguous"
+	case Missed:
+		return "missed"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+//MarshalText encodes the match as its String.
+func (m Match) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+//Film is a Row with the movie OMDB matched it to, nil when it is not
+//matched.
+type Film struct {
+	Row
+	Match      Match
+	Movie      *omdb.MovieResult   `json:",omitempty"`
+	Candidates []omdb.SearchResult `json:",omitempty"`
+	Err        error               `json:"-"`
+}
+
+//Read reads an export file, which needs the Name and Year columns. Other
+//columns are kept in the Record of every row.
+func Read(r io.Reader) (*Export, error) {
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	//the export is written with a byte order mark by some spreadsheets.
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	col := make(map[string]int)
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{ColumnName, ColumnYear} {
+		if _, ok := col[name]; !ok {
+			return nil, errors.New("letterboxd: Missing column " + name)
+		}
+	}
+	cell := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	export := &Export{Header: header}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return export, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := Row{
+			Name:        cell(record, ColumnName),
+			URI:         cell(record, ColumnURI),
+			WatchedDate: cell(record, ColumnWatchedDate),
+			Record:      record,
+		}
+		row.Year, _ = strconv.Atoi(cell(record, ColumnYear))
+		row.Rating, _ = strconv.ParseFloat(cell(record, ColumnRating), 64)
+		export.Rows = append(export.Rows, row)
+	}
+}
+
+//Enrich matches every row of e with c, one lookup after the other so the
+//cache and rate limiters of an omdb.Client apply. A failed lookup is
+//reported in its Film rather than stopping the others; Enrich stops when
+//ctx is done, the remaining films being Failed.
+func Enrich(ctx context.Context, c omdb.API, e *Export, opts ...omdb.CallOption) []Film {
+
+	films := make([]Film, len(e.Rows))
+	for i, row := range e.Rows {
+		films[i] = match(ctx, c, row, opts)
+	}
+	return films
+}
+
+func match(ctx context.Context, c omdb.API, row Row, opts []omdb.CallOption) Film {
+
+	film := Film{Row: row}
+	if err := ctx.Err(); err != nil {
+		film.Match, film.Err = Failed, err
+		return film
+	}
+
+	years := []int{row.Year}
+	if row.Year > 0 {
+		years = append(years, row.Year-1, row.Year+1)
+	}
+	for i, year := range years {
+		q := omdb.QueryData{Title: row.Name}
+		if year > 0 {
+			q.Year = strconv.Itoa(year)
+		}
+		movie, err := c.GetMovieByTitle(ctx, q, opts...)
+		switch {
+		case err == nil:
+			film.Movie, film.Match = movie, Exact
+			if i > 0 {
+				film.Match = NearYear
+			}
+			return film
+		case !errors.Is(err, omdb.ErrNotFound) && !errors.Is(err, omdb.ErrTypeMismatch):
+			film.Match, film.Err = Failed, err
+			return film
+		}
+	}
+
+	//the name OMDB knows may differ, e.g. by punctuation.
+	found, err := c.SearchByTextContext(ctx, omdb.QueryData{Title: row.Name, SearchType: omdb.TypeMovie}, opts...)
+	switch {
+	case errors.Is(err, omdb.ErrNotFound):
+		film.Match = Missed
+		return film
+	case err != nil:
+		film.Match, film.Err = Failed, err
+		return film
+	}
+	for _, r := range found.Search {
+		if y, _ := strconv.Atoi(firstYear(r.Year)); row.Year == 0 || (y >= row.Year-1 && y <= row.Year+1) {
+			film.Candidates = append(film.Candidates, r)
+		}
+	}
+	if len(film.Candidates) != 1 {
+		film.Match = Missed
+		if len(film.Candidates) > 1 {
+			film.Match = Ambiguous
+		}
+		return film
+	}
+
+	movie, err := c.GetMovieByID(ctx, film.Candidates[0].ImdbID, opts...)
+	if err != nil {
+		film.Match, film.Err = Failed, err
+		return film
+	}
+	film.Movie, film.Match, film.Candidates = movie, Searched, nil
+	return film
+}
+
+//WriteCSV writes films as the export they were read from, header being its
+//Header, with the EnrichedColumns appended.
+func WriteCSV(w io.Writer, header []string, films []Film) error {
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(append([]string(nil), header...), EnrichedColumns...)); err != nil {
+		return err
+	}
+	for _, f := range films {
+		record := make([]string, len(header), len(header)+len(EnrichedColumns))
+		copy(record, f.Record)
+		record = append(record, f.Match.String())
+		if m := f.Movie; m != nil {
+			record = append(record, m.ImdbID, na(m.Runtime), na(m.Director), na(m.ImdbRating), na(m.Metascore), rottenTomatoes(m.Ratings))
+		}
+		record = record[:cap(record)]
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+//WriteJSON writes films as an indented JSON array.
+func WriteJSON(w io.Writer, films []Film) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if films == nil {
+		films = []Film{}
+	}
+	return enc.Encode(films)
+}
+
+func rottenTomatoes(ratings []omdb.Rating) string {
+	for _, r := range ratings {
+		if r.Kind() == omdb.SourceRottenTomatoes {
+			return r.Value
+		}
+	}
+	return ""
+}
+
+//firstYear returns the first year of a Year field like "1999" or
+//"2008–2013".
+func firstYear(s string) string {
+	if len(s) > 4 {
+		return s[:4]
+	}
+	return s
+}
+
+//na maps OMDB's "N/A" placeholder to an empty string.
+func na(s string) string {
+	if s == "N/A" {
+		return ""
+	}
+	return s
+}
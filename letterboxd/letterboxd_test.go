@@ -0,0 +1,113 @@
+package letterboxd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/letterboxd"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//ratings is a ratings.csv of an export, as written by Letterboxd with a byte
+//order mark.
+const ratings = "\ufeffDate,Name,Year,Letterboxd URI,Rating\n" +
+	"2024-01-02,The Matrix,1999,https://boxd.it/2a1m,4.5\n" +
+	"2024-01-03,Amélie,2002,https://boxd.it/2aHi,5\n" +
+	"2024-01-04,Blade Runner,1982,https://boxd.it/2bcA,4\n" +
+	"2024-01-05,Hamlet,1996,https://boxd.it/1S8W,3.5\n" +
+	"2024-01-06,\"Nothing, Really\",2000,https://boxd.it/0000,\n"
+
+func TestRead(t *testing.T) {
+
+	export, err := letterboxd.Read(strings.NewReader(ratings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(export.Header, ",") != "Date,Name,Year,Letterboxd URI,Rating" {
+		t.Errorf("got header %q", export.Header)
+	}
+	if len(export.Rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(export.Rows))
+	}
+	row := export.Rows[0]
+	if row.Name != "The Matrix" || row.Year != 1999 || row.URI != "https://boxd.it/2a1m" || row.Rating != 4.5 || row.Record[0] != "2024-01-02" {
+		t.Errorf("got %+v", row)
+	}
+	if row := export.Rows[4]; row.Name != "Nothing, Really" || row.Rating != 0 {
+		t.Errorf("got %+v", row)
+	}
+
+	if _, err := letterboxd.Read(strings.NewReader("Name,Rating\nThe Matrix,4\n")); err == nil {
+		t.Error("got no error for an export without Year")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", Runtime: "136 min", Director: "Lana Wachowski, Lilly Wachowski", ImdbRating: "8.7",
+		Ratings: []omdb.Rating{{Source: "Rotten Tomatoes", Value: "83%"}}})
+	srv.AddMovie(omdb.MovieResult{Title: "Amélie", Year: "2001", ImdbID: "tt0211915"})
+	srv.AddMovie(omdb.MovieResult{Title: "Blade Runner: The Final Cut", Year: "1982", ImdbID: "tt0083658"})
+	srv.AddMovie(omdb.MovieResult{Title: "Blade Runner 2049", Year: "2017", ImdbID: "tt1856101"})
+	srv.AddMovie(omdb.MovieResult{Title: "Hamlet: Kenneth Branagh", Year: "1996", ImdbID: "tt0116477"})
+	srv.AddMovie(omdb.MovieResult{Title: "Hamlet: Director's Cut", Year: "1997", ImdbID: "tt0116478"})
+
+	ctx := context.Background()
+	export, err := letterboxd.Read(strings.NewReader(ratings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	films := letterboxd.Enrich(ctx, srv.Client(), export)
+
+	for i, want := range []struct {
+		match  letterboxd.Match
+		imdbID string
+	}{
+		{letterboxd.Exact, "tt0133093"},
+		{letterboxd.NearYear, "tt0211915"},
+		{letterboxd.Searched, "tt0083658"},
+		{letterboxd.Ambiguous, ""},
+		{letterboxd.Missed, ""},
+	} {
+		f := films[i]
+		if f.Match != want.match || f.Err != nil {
+			t.Errorf("%s: got match %v, %v, want %v", f.Name, f.Match, f.Err, want.match)
+		}
+		imdbID := ""
+		if f.Movie != nil {
+			imdbID = f.Movie.ImdbID
+		}
+		if imdbID != want.imdbID {
+			t.Errorf("%s: got movie %q, want %q", f.Name, imdbID, want.imdbID)
+		}
+	}
+	if candidates := films[3].Candidates; len(candidates) != 2 {
+		t.Errorf("got candidates %+v for Hamlet, want 2", candidates)
+	}
+
+	var buf bytes.Buffer
+	if err := letterboxd.WriteCSV(&buf, export.Header, films[:2]); err != nil {
+		t.Fatal(err)
+	}
+	want := "Date,Name,Year,Letterboxd URI,Rating,Match,imdbID,Runtime,Director,imdbRating,Metascore,Rotten Tomatoes\n" +
+		"2024-01-02,The Matrix,1999,https://boxd.it/2a1m,4.5,exact,tt0133093,136 min,\"Lana Wachowski, Lilly Wachowski\",8.7,,83%\n" +
+		"2024-01-03,Amélie,2002,https://boxd.it/2aHi,5,near-year,tt0211915,,,,,\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	//the films left when ctx is done fail.
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, f := range letterboxd.Enrich(done, srv.Client(), export) {
+		if f.Match != letterboxd.Failed || !errors.Is(f.Err, context.Canceled) {
+			t.Errorf("%s: got match %v, %v, want failed", f.Name, f.Match, f.Err)
+		}
+	}
+}
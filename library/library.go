@@ -0,0 +1,412 @@
+//Package library keeps a personal catalog of titles in SQLite: every movie,
+//series or episode fetched from OMDB is stored with its main fields in
+//indexed columns, so the catalog can be browsed offline by year, type or
+//genre:
+//
+//	lib, err := library.New(ctx, db)
+//	...
+//	movie, err := client.GetMovieByID(ctx, "tt0133093")
+//	...
+//	err = lib.Upsert(ctx, movie)
+//	items, err := lib.Find(ctx, library.Filter{Genre: "Sci-Fi", YearFrom: 1990})
+//
+//Like sqlitecache, the package only uses database/sql, the caller opens the
+//*sql.DB with the SQLite driver of its choice.
+package library
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+//Tables holding the titles and their genres.
+const (
+	TitlesTable = "omdb_titles"
+	GenresTable = "omdb_title_genres"
+)
+
+//Library is a catalog of titles stored in a SQLite database. It is safe for
+//concurrent use as far as the *sql.DB is.
+type Library struct {
+	db *sql.DB
+}
+
+//Item is a title of the library: its indexed fields, and the result it was
+//stored from.
+type Item struct {
+	ImdbID     string
+	Type       string
+	Title      string
+	Year       int //first year of a series, 0 when unknown
+	ImdbRating float64
+	Genres     []string
+	SeriesID   string //episodes only
+	Updated    time.Time
+
+	//Result is the omdb.MovieResult, omdb.SeriesResult or
+	//omdb.EpisodeResult of the title.
+	Result interface{}
+}
+
+//Filter selects the titles returned by Find, its zero value selects all of
+//them ordered by title.
+type Filter struct {
+	//Type is omdb.TypeMovie, omdb.TypeSeries or omdb.TypeEpisode.
+	Type  string
+	Genre string
+	//Title matches titles containing it, ignoring ASCII case.
+	Title     string
+	YearFrom  int
+	YearTo    int
+	MinRating float64
+	SeriesID  string
+
+	//OrderBy is "title", "year" or "rating", Desc reverses it.
+	OrderBy string
+	Desc    bool
+	//Limit and Offset page through the titles when Limit is positive.
+	Limit  int
+	Offset int
+}
+
+//orderColumns maps Filter.OrderBy to columns.
+var orderColumns = map[string]string{
+	"":       "title",
+	"title":  "title",
+	"year":   "year",
+	"rating": "imdb_rating",
+}
+
+//New opens the library stored in db, its tables and indexes are created when
+//they don't exist.
+func New(ctx context.Context, db *sql.DB) (*Library, error) {
+
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS ` + TitlesTable + ` (
+			imdb_id     TEXT PRIMARY KEY,
+			type        TEXT NOT NULL,
+			title       TEXT NOT NULL,
+			year        INTEGER,
+			imdb_rating REAL,
+			series_id   TEXT,
+			body        BLOB NOT NULL,
+			updated_at  INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + TitlesTable + `_year ON ` + TitlesTable + ` (year)`,
+		`CREATE INDEX IF NOT EXISTS ` + TitlesTable + `_type ON ` + TitlesTable + ` (type)`,
+		`CREATE INDEX IF NOT EXISTS ` + TitlesTable + `_series_id ON ` + TitlesTable + ` (series_id)`,
+		`CREATE TABLE IF NOT EXISTS ` + GenresTable + ` (
+			imdb_id TEXT NOT NULL REFERENCES ` + TitlesTable + ` (imdb_id) ON DELETE CASCADE,
+			genre   TEXT NOT NULL,
+			PRIMARY KEY (imdb_id, genre)
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + GenresTable + `_genre ON ` + GenresTable + ` (genre)`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, err
+		}
+	}
+	return &Library{db: db}, nil
+}
+
+//Upsert stores results, each an omdb.MovieResult, omdb.SeriesResult or
+//omdb.EpisodeResult or a pointer to one, replacing the titles already stored
+//with the same ImdbID. nil results are skipped, like the failed lookups of
+//Client.Enrich. The results are stored in a single transaction.
+func (l *Library) Upsert(ctx context.Context, results ...interface{}) error {
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, res := range results {
+		if err := upsert(ctx, tx, res); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func upsert(ctx context.Context, tx *sql.Tx, res interface{}) error {
+
+	item, ok := newItem(res)
+	if !ok {
+		return nil
+	}
+	if item.ImdbID == "" {
+		return errors.New("library: Can't store " + item.Type + " " + item.Title + " without ImdbID")
+	}
+
+	body := &bytes.Buffer{}
+	if err := omdb.NewJSONLWriter(body).Write(item.Result); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO `+TitlesTable+` (imdb_id, type, title, year, imdb_rating, series_id, body, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (imdb_id) DO UPDATE SET
+			type = excluded.type, title = excluded.title, year = excluded.year,
+			imdb_rating = excluded.imdb_rating, series_id = excluded.series_id,
+			body = excluded.body, updated_at = excluded.updated_at`,
+		item.ImdbID, item.Type, item.Title, nullInt(item.Year), nullFloat(item.ImdbRating),
+		nullString(item.SeriesID), bytes.TrimSpace(body.Bytes()), time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+GenresTable+` WHERE imdb_id = ?`, item.ImdbID); err != nil {
+		return err
+	}
+	for _, genre := range item.Genres {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO `+GenresTable+` (imdb_id, genre) VALUES (?, ?)`,
+			item.ImdbID, genre); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Get returns the title stored with imdbID, an error matching omdb.ErrNotFound
+//when there is none.
+func (l *Library) Get(ctx context.Context, imdbID string) (*Item, error) {
+
+	items, err := l.query(ctx, `WHERE t.imdb_id = ?`, []interface{}{imdbID})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, &notFoundError{id: imdbID}
+	}
+	return &items[0], nil
+}
+
+//Delete removes the title stored with imdbID, if any.
+func (l *Library) Delete(ctx context.Context, imdbID string) error {
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{GenresTable, TitlesTable} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE imdb_id = ?`, imdbID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+//Find returns the titles selected by f.
+func (l *Library) Find(ctx context.Context, f Filter) ([]Item, error) {
+
+	order, ok := orderColumns[f.OrderBy]
+	if !ok {
+		return nil, errors.New("library: Can't order by " + f.OrderBy)
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	add := func(cond string, arg interface{}) {
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+	if f.Type != "" {
+		add(`t.type = ?`, f.Type)
+	}
+	if f.Genre != "" {
+		add(`EXISTS (SELECT 1 FROM `+GenresTable+` g WHERE g.imdb_id = t.imdb_id AND g.genre = ? COLLATE NOCASE)`, f.Genre)
+	}
+	if f.Title != "" {
+		add(`t.title LIKE ? ESCAPE '\'`, "%"+escapeLike(f.Title)+"%")
+	}
+	if f.YearFrom > 0 {
+		add(`t.year >= ?`, f.YearFrom)
+	}
+	if f.YearTo > 0 {
+		add(`t.year <= ?`, f.YearTo)
+	}
+	if f.MinRating > 0 {
+		add(`t.imdb_rating >= ?`, f.MinRating)
+	}
+	if f.SeriesID != "" {
+		add(`t.series_id = ?`, f.SeriesID)
+	}
+
+	clause := ""
+	if len(where) > 0 {
+		clause = `WHERE ` + strings.Join(where, ` AND `)
+	}
+	clause += ` ORDER BY t.` + order
+	if f.Desc {
+		clause += ` DESC`
+	}
+	clause += `, t.imdb_id`
+	if f.Limit > 0 {
+		clause += ` LIMIT ? OFFSET ?`
+		args = append(args, f.Limit, f.Offset)
+	}
+	return l.query(ctx, clause, args)
+}
+
+//Count returns the number of titles stored.
+func (l *Library) Count(ctx context.Context) (int, error) {
+	var n int
+	err := l.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+TitlesTable).Scan(&n)
+	return n, err
+}
+
+//Genres returns the number of titles stored of every genre.
+func (l *Library) Genres(ctx context.Context) (map[string]int, error) {
+
+	rows, err := l.db.QueryContext(ctx, `SELECT genre, COUNT(*) FROM `+GenresTable+` GROUP BY genre`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := make(map[string]int)
+	for rows.Next() {
+		var (
+			genre string
+			n     int
+		)
+		if err := rows.Scan(&genre, &n); err != nil {
+			return nil, err
+		}
+		genres[genre] = n
+	}
+	return genres, rows.Err()
+}
+
+//query returns the titles selected by clause.
+func (l *Library) query(ctx context.Context, clause string, args []interface{}) ([]Item, error) {
+
+	rows, err := l.db.QueryContext(ctx, `
+		SELECT t.imdb_id, t.type, t.title, t.year, t.imdb_rating, t.series_id, t.body, t.updated_at
+		FROM `+TitlesTable+` t `+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var (
+			item     Item
+			year     sql.NullInt64
+			rating   sql.NullFloat64
+			seriesID sql.NullString
+			body     []byte
+			updated  int64
+		)
+		if err := rows.Scan(&item.ImdbID, &item.Type, &item.Title, &year, &rating, &seriesID, &body, &updated); err != nil {
+			return nil, err
+		}
+		item.Year, item.ImdbRating, item.SeriesID = int(year.Int64), rating.Float64, seriesID.String
+		item.Updated = time.Unix(updated, 0)
+		if item.Result, err = omdb.NewJSONLReader(bytes.NewReader(body)).Read(); err != nil {
+			return nil, err
+		}
+		if stored, ok := newItem(item.Result); ok {
+			item.Genres = stored.Genres
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+//newItem returns the Item of res, ok is false when res is nil or not a
+//result.
+func newItem(res interface{}) (item Item, ok bool) {
+
+	switch r := res.(type) {
+	case *omdb.MovieResult:
+		if r == nil {
+			return item, false
+		}
+		return newItem(*r)
+	case *omdb.SeriesResult:
+		if r == nil {
+			return item, false
+		}
+		return newItem(*r)
+	case *omdb.EpisodeResult:
+		if r == nil {
+			return item, false
+		}
+		return newItem(*r)
+	case omdb.MovieResult:
+		item = Item{ImdbID: r.ImdbID, Type: string(omdb.TypeMovie), Title: r.Title, Year: firstYear(r.Year), Genres: r.Genres()}
+		item.ImdbRating = r.Typed().ImdbRating
+	case omdb.SeriesResult:
+		item = Item{ImdbID: r.ImdbID, Type: string(omdb.TypeSeries), Title: r.Title, Year: firstYear(r.Year), Genres: r.Genres()}
+		item.ImdbRating = r.Typed().ImdbRating
+	case omdb.EpisodeResult:
+		item = Item{ImdbID: r.ImdbID, Type: string(omdb.TypeEpisode), Title: r.Title, Year: firstYear(r.Year), Genres: r.Genres()}
+		item.ImdbRating = r.Typed().ImdbRating
+		item.SeriesID = r.SeriesID
+	default:
+		return item, false
+	}
+	item.Result = res
+	return item, true
+}
+
+//firstYear returns the first year of a Year field like "1999" or
+//"2008–2013", 0 when there is none.
+func firstYear(s string) int {
+	if len(s) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(s[:4])
+	return year
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func nullInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func nullFloat(f float64) interface{} {
+	if f == 0 {
+		return nil
+	}
+	return f
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+//notFoundError is returned by Get for a title which isn't stored, it matches
+//omdb.ErrNotFound.
+type notFoundError struct {
+	id string
+}
+
+func (e *notFoundError) Error() string {
+	return "library: " + e.id + " not found"
+}
+
+func (e *notFoundError) Unwrap() error { return omdb.ErrNotFound }
@@ -0,0 +1,178 @@
+package library_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/library"
+
+	_ "modernc.org/sqlite"
+)
+
+func openLibrary(t *testing.T) *library.Library {
+
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//every connection to :memory: is a database of its own.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	lib, err := library.New(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lib
+}
+
+//ids returns the ImdbIDs of items, joined by commas.
+func ids(items []library.Item) string {
+	var s []string
+	for _, item := range items {
+		s = append(s, item.ImdbID)
+	}
+	return strings.Join(s, ",")
+}
+
+func fill(t *testing.T, lib *library.Library) {
+
+	t.Helper()
+	err := lib.Upsert(context.Background(),
+		omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", Genre: "Action, Sci-Fi", ImdbRating: "8.7"},
+		&omdb.MovieResult{Title: "Dune", Year: "2021", ImdbID: "tt1160419", Genre: "Action, Adventure, Drama", ImdbRating: "8.0"},
+		omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747", Genre: "Crime, Drama, Thriller", ImdbRating: "9.5"},
+		omdb.EpisodeResult{Title: "Pilot", Year: "2008", ImdbID: "tt0959621", SeriesID: "tt0903747", Genre: "Crime, Drama", ImdbRating: "N/A"},
+		omdb.MovieResult{Title: "100% Wolf", Year: "2020", ImdbID: "tt4943998", Genre: "Animation", ImdbRating: "5.5"},
+		(*omdb.MovieResult)(nil),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpsertGet(t *testing.T) {
+
+	ctx := context.Background()
+	lib := openLibrary(t)
+	fill(t, lib)
+
+	item, err := lib.Get(ctx, "tt0903747")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Type != "series" || item.Title != "Breaking Bad" || item.Year != 2008 || item.ImdbRating != 9.5 || item.Updated.IsZero() {
+		t.Errorf("got %+v", item)
+	}
+	if !reflect.DeepEqual(item.Genres, []string{"Crime", "Drama", "Thriller"}) {
+		t.Errorf("got genres %q", item.Genres)
+	}
+	if series, ok := item.Result.(omdb.SeriesResult); !ok || series.Year != "2008–2013" {
+		t.Errorf("got result %#v", item.Result)
+	}
+
+	episode, err := lib.Get(ctx, "tt0959621")
+	if err != nil || episode.SeriesID != "tt0903747" || episode.ImdbRating != 0 {
+		t.Errorf("got %+v, %v", episode, err)
+	}
+
+	//a title stored again replaces the first one.
+	if err := lib.Upsert(ctx, omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", Genre: "Sci-Fi", ImdbRating: "8.8"}); err != nil {
+		t.Fatal(err)
+	}
+	item, err = lib.Get(ctx, "tt0133093")
+	if err != nil || item.ImdbRating != 8.8 || !reflect.DeepEqual(item.Genres, []string{"Sci-Fi"}) {
+		t.Errorf("got %+v, %v", item, err)
+	}
+	if n, err := lib.Count(ctx); err != nil || n != 5 {
+		t.Errorf("got %d titles, %v, want 5", n, err)
+	}
+
+	if err := lib.Upsert(ctx, omdb.MovieResult{Title: "No ID"}); err == nil {
+		t.Error("got no error for a title without ImdbID")
+	}
+	if _, err := lib.Get(ctx, "tt0000001"); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+
+	ctx := context.Background()
+	lib := openLibrary(t)
+	fill(t, lib)
+
+	if err := lib.Delete(ctx, "tt0133093"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lib.Get(ctx, "tt0133093"); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for a deleted title, want ErrNotFound", err)
+	}
+	if items, err := lib.Find(ctx, library.Filter{Genre: "Sci-Fi"}); err != nil || len(items) != 0 {
+		t.Errorf("got %s, %v, want the genres deleted", ids(items), err)
+	}
+	if err := lib.Delete(ctx, "tt0133093"); err != nil {
+		t.Errorf("got error %v deleting a title twice", err)
+	}
+}
+
+func TestFind(t *testing.T) {
+
+	ctx := context.Background()
+	lib := openLibrary(t)
+	fill(t, lib)
+
+	for _, tt := range []struct {
+		name string
+		f    library.Filter
+		want string
+	}{
+		{"all", library.Filter{}, "tt4943998,tt0903747,tt1160419,tt0959621,tt0133093"},
+		{"type", library.Filter{Type: string(omdb.TypeMovie)}, "tt4943998,tt1160419,tt0133093"},
+		{"genre ignoring case", library.Filter{Genre: "drama"}, "tt0903747,tt1160419,tt0959621"},
+		{"title ignoring case", library.Filter{Title: "MATRIX"}, "tt0133093"},
+		{"title with %", library.Filter{Title: "0%"}, "tt4943998"},
+		{"title with _", library.Filter{Title: "Th_"}, ""},
+		{"years", library.Filter{YearFrom: 2000, YearTo: 2020}, "tt4943998,tt0903747,tt0959621"},
+		{"rating", library.Filter{MinRating: 8}, "tt0903747,tt1160419,tt0133093"},
+		{"series", library.Filter{SeriesID: "tt0903747"}, "tt0959621"},
+		{"year", library.Filter{OrderBy: "year", Type: string(omdb.TypeMovie)}, "tt0133093,tt4943998,tt1160419"},
+		{"rating desc", library.Filter{OrderBy: "rating", Desc: true, MinRating: 1}, "tt0903747,tt0133093,tt1160419,tt4943998"},
+		{"page", library.Filter{Limit: 2, Offset: 1}, "tt0903747,tt1160419"},
+	} {
+		items, err := lib.Find(ctx, tt.f)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := ids(items); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := lib.Find(ctx, library.Filter{OrderBy: "votes"}); err == nil {
+		t.Error("got no error ordering by votes")
+	}
+}
+
+func TestGenres(t *testing.T) {
+
+	lib := openLibrary(t)
+	fill(t, lib)
+
+	genres, err := lib.Genres(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"Action": 2, "Adventure": 1, "Animation": 1, "Crime": 2, "Drama": 3, "Sci-Fi": 1, "Thriller": 1}
+	if !reflect.DeepEqual(genres, want) {
+		t.Errorf("got %v, want %v", genres, want)
+	}
+}
@@ -0,0 +1,41 @@
+package omdb
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+//log emits an event to the logger of the client, if any.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+//logStart emits the start of an attempt.
+func (c *Client) logStart(ctx context.Context, rawURL string, attempt int) {
+	if c.logger == nil {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, "omdb request started", slog.String("url", redactURL(rawURL)), slog.Int("attempt", attempt))
+}
+
+//logResult emits the outcome of an attempt started at start.
+func (c *Client) logResult(ctx context.Context, rawURL string, attempt int, start time.Time, res *http.Response, err error) {
+	if c.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("url", redactURL(rawURL)),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		c.log(ctx, slog.LevelWarn, "omdb request failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	c.log(ctx, slog.LevelDebug, "omdb request finished", append(attrs, slog.Int("status", res.StatusCode))...)
+}
@@ -0,0 +1,77 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//ResponseMeta tells where the response of a call came from, see
+//WithResponseMeta.
+type ResponseMeta struct {
+	//Status is the HTTP status OMDB responded with, 0 when the response
+	//came from the cache or no response was received.
+	Status int
+	//FromCache is set when the response came from the cache of the client.
+	FromCache bool
+	//Stale is set along with FromCache when the cached response expired
+	//and is revalidated in the background, see WithStaleWhileRevalidate.
+	Stale bool
+	//Latency is the time OMDB took to send the response headers.
+	Latency time.Duration
+	//CacheStatus is the CF-Cache-Status header of the response, telling
+	//whether the Cloudflare CDN in front of OMDB answered the request.
+	CacheStatus string
+}
+
+//WithResponseMeta fills meta with the ResponseMeta of the call once it
+//returns, also when it fails. With retries it describes the last attempt.
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.meta = meta
+	}
+}
+
+//metaRecorder collects the ResponseMeta of a request sent by fetch. The
+//responses of hedged requests may arrive concurrently.
+type metaRecorder struct {
+	mu   sync.Mutex
+	meta ResponseMeta
+}
+
+type metaRecorderKey struct{}
+
+func withMetaRecorder(ctx context.Context, r *metaRecorder) context.Context {
+	return context.WithValue(ctx, metaRecorderKey{}, r)
+}
+
+//recordResponse records the response of an attempt started at start.
+func recordResponse(ctx context.Context, res *http.Response, start time.Time) {
+
+	r, _ := ctx.Value(metaRecorderKey{}).(*metaRecorder)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.meta = ResponseMeta{
+		Status:      res.StatusCode,
+		Latency:     time.Since(start),
+		CacheStatus: res.Header.Get("CF-Cache-Status"),
+	}
+	r.mu.Unlock()
+}
+
+//get returns the recorded ResponseMeta.
+func (r *metaRecorder) get() ResponseMeta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.meta
+}
+
+//setMeta hands meta to the caller which asked for it with WithResponseMeta.
+func setMeta(ctx context.Context, meta ResponseMeta) {
+	if m := callOptionsFrom(ctx).meta; m != nil {
+		*m = meta
+	}
+}
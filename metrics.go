@@ -0,0 +1,100 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+//Metrics receives the measurements of a client, see WithMetrics. op is the
+//kind of request (OpByID, OpByTitle, OpSearch, OpSeason or OpEpisode).
+//Implementations have to be safe for concurrent use.
+type Metrics interface {
+	//Request is called once per request made by the client, served from
+	//the cache or not, with its latency and its error class (see
+	//ErrorClass), "" on success.
+	Request(op string, latency time.Duration, errClass string)
+
+	//Cache is called for each request looked up in the cache.
+	Cache(op string, hit bool)
+
+	//QuotaRemaining is called after each request with the requests left
+	//in the daily quota, when the client has one (see WithDailyQuota).
+	QuotaRemaining(remaining int)
+}
+
+//Error classes returned by ErrorClass.
+const (
+	ClassNotFound       = "not_found"
+	ClassInvalidRequest = "invalid_request"
+	ClassInvalidAPIKey  = "invalid_api_key"
+	ClassLimit          = "limit"
+	ClassCircuitOpen    = "circuit_open"
+	ClassTimeout        = "timeout"
+	ClassCanceled       = "canceled"
+	ClassUpstream       = "upstream"
+	ClassNetwork        = "network"
+	ClassOther          = "other"
+)
+
+//ErrorClass returns a low cardinality class of err suitable as a metric
+//label, "" for a nil error.
+func ErrorClass(err error) string {
+
+	var status statusError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return ClassNotFound
+	case errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrTypeMismatch):
+		return ClassInvalidRequest
+	case errors.Is(err, ErrInvalidAPIKey):
+		return ClassInvalidAPIKey
+	case errors.Is(err, ErrDailyLimitExceeded), errors.Is(err, ErrQuotaExhausted):
+		return ClassLimit
+	case errors.Is(err, ErrCircuitOpen):
+		return ClassCircuitOpen
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.As(err, &status):
+		if status == http.StatusTooManyRequests {
+			return ClassLimit
+		}
+		return ClassUpstream
+	case transient(err):
+		return ClassNetwork
+	}
+	return ClassOther
+}
+
+//WithMetrics reports the requests of the client, its cache lookups and its
+//remaining quota to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) error {
+		c.metrics = m
+		return nil
+	}
+}
+
+//observe reports a request for key which started at start and ended with
+//err.
+func (c *Client) observe(key string, start time.Time, err error) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.Request(CacheKeyOp(key), time.Since(start), ErrorClass(err))
+	if c.quota != nil {
+		c.metrics.QuotaRemaining(c.quota.Remaining())
+	}
+}
+
+//observeCache reports a cache lookup for key.
+func (c *Client) observeCache(key string, hit bool) {
+	if c.metrics != nil {
+		c.metrics.Cache(CacheKeyOp(key), hit)
+	}
+}
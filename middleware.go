@@ -0,0 +1,33 @@
+package omdb
+
+import "net/http"
+
+//RoundTripFunc sends a request and returns its response, like
+//http.RoundTripper.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+//Middleware wraps the sending of every request of a client, e.g. to add
+//headers, record metrics or inject failures. It calls next to go on with the
+//request, or returns a response or error of its own. The RoundTripFunc it
+//returns is called concurrently.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+//WithMiddleware adds mw to the middlewares of the client. The first
+//middleware given is the outermost one: it sees the request first and the
+//response last. Each attempt and hedged request goes through the middlewares,
+//which run inside the retries, rate limiting and caching of the client.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) error {
+		c.middleware = append(c.middleware, mw...)
+		return nil
+	}
+}
+
+//roundTrip sends req with the http.Client through the middlewares.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(c.httpClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(req)
+}
@@ -0,0 +1,187 @@
+package omdb
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+//MockClient is an API returning canned results, for testing code which uses
+//the client without sending requests. Results are registered per operation
+//and query with Handle, or computed by Func; a query without either fails
+//like an unknown id with an error matching ErrNotFound. Every call is
+//recorded and can be inspected with Calls.
+//
+//A MockClient is safe for concurrent use, its zero value has no results.
+type MockClient struct {
+	//Func, when set, answers the queries which have no result registered
+	//with Handle.
+	Func func(ctx context.Context, op string, q QueryData) (interface{}, error)
+
+	mu        sync.Mutex
+	responses map[string][]mockResponse
+	calls     []MockCall
+}
+
+//MockCall is a call recorded by MockClient.
+type MockCall struct {
+	Op    string
+	Query QueryData
+}
+
+type mockResponse struct {
+	res interface{}
+	err error
+}
+
+//Handle registers the result, or the error, returned for the query q of op
+//(OpByID, OpByTitle, OpSearch, OpSeason or OpEpisode). res has the type the
+//lookup returns: a MovieResult, SeriesResult or EpisodeResult for OpByID,
+//OpByTitle and OpEpisode, a *SearchResponse for OpSearch and a *SeasonResult
+//for OpSeason. Queries are matched like CacheKey does, the typed lookups
+//by title set SearchType on the query.
+//
+//Registering several responses for the same query scripts them: each call
+//consumes one and the last one is kept for the following calls.
+func (m *MockClient) Handle(op string, q QueryData, res interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.responses == nil {
+		m.responses = map[string][]mockResponse{}
+	}
+	key := op + "?" + CacheKey(q, op)
+	m.responses[key] = append(m.responses[key], mockResponse{res: res, err: err})
+}
+
+//Calls returns the calls made so far, in order.
+func (m *MockClient) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+//Reset forgets the registered responses and the recorded calls.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = nil
+	m.calls = nil
+}
+
+func (m *MockClient) call(ctx context.Context, op string, q QueryData) (interface{}, error) {
+
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Op: op, Query: q})
+	key := op + "?" + CacheKey(q, op)
+	responses, ok := m.responses[key]
+	if ok && len(responses) > 1 {
+		m.responses[key] = responses[1:]
+	}
+	m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ok {
+		return responses[0].res, responses[0].err
+	}
+	if m.Func != nil {
+		return m.Func(ctx, op, q)
+	}
+	return nil, &APIError{Message: "Movie not found!"}
+}
+
+//SearchByImdbID returns the result registered for q with OpByID.
+func (m *MockClient) SearchByImdbID(q QueryData) (interface{}, error) {
+	return m.SearchByImdbIDContext(context.Background(), q)
+}
+
+//SearchByImdbIDContext returns the result registered for q with OpByID.
+func (m *MockClient) SearchByImdbIDContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error) {
+	return m.call(ctx, OpByID, q)
+}
+
+//SearchByTitle returns the result registered for q with OpByTitle.
+func (m *MockClient) SearchByTitle(q QueryData) (interface{}, error) {
+	return m.SearchByTitleContext(context.Background(), q)
+}
+
+//SearchByTitleContext returns the result registered for q with OpByTitle.
+func (m *MockClient) SearchByTitleContext(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, error) {
+	return m.call(ctx, OpByTitle, q)
+}
+
+//SearchByText returns the result registered for q with OpSearch.
+func (m *MockClient) SearchByText(q QueryData) (*SearchResponse, error) {
+	return m.SearchByTextContext(context.Background(), q)
+}
+
+//SearchByTextContext returns the result registered for q with OpSearch.
+func (m *MockClient) SearchByTextContext(ctx context.Context, q QueryData, opts ...CallOption) (*SearchResponse, error) {
+	res, err := m.call(ctx, OpSearch, q)
+	if err != nil {
+		return nil, err
+	}
+	search, ok := res.(*SearchResponse)
+	if !ok {
+		return nil, typeMismatch("search response", res)
+	}
+	return search, nil
+}
+
+//GetSeason returns the result registered with OpSeason for the query with
+//ImdbID and Season set.
+func (m *MockClient) GetSeason(ctx context.Context, seriesID string, season int, opts ...CallOption) (*SeasonResult, error) {
+	res, err := m.call(ctx, OpSeason, QueryData{ImdbID: seriesID, Season: strconv.Itoa(season)})
+	if err != nil {
+		return nil, err
+	}
+	result, ok := res.(*SeasonResult)
+	if !ok {
+		return nil, typeMismatch("season", res)
+	}
+	return result, nil
+}
+
+//GetEpisode returns the result registered with OpEpisode for the query with
+//ImdbID, Season and Episode set.
+func (m *MockClient) GetEpisode(ctx context.Context, seriesID string, season, episode int, opts ...CallOption) (*EpisodeResult, error) {
+	q := QueryData{ImdbID: seriesID, Season: strconv.Itoa(season), Episode: strconv.Itoa(episode)}
+	return asEpisode(m.call(ctx, OpEpisode, q))
+}
+
+//GetMovieByID returns the MovieResult registered for the id with OpByID.
+func (m *MockClient) GetMovieByID(ctx context.Context, id string, opts ...CallOption) (*MovieResult, error) {
+	return asMovie(m.call(ctx, OpByID, QueryData{ImdbID: id}))
+}
+
+//GetMovieByTitle returns the MovieResult registered for q, with SearchType
+//set to TypeMovie, with OpByTitle.
+func (m *MockClient) GetMovieByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*MovieResult, error) {
+	q.SearchType = TypeMovie
+	return asMovie(m.call(ctx, OpByTitle, q))
+}
+
+//GetSeriesByID returns the SeriesResult registered for the id with OpByID.
+func (m *MockClient) GetSeriesByID(ctx context.Context, id string, opts ...CallOption) (*SeriesResult, error) {
+	return asSeries(m.call(ctx, OpByID, QueryData{ImdbID: id}))
+}
+
+//GetSeriesByTitle returns the SeriesResult registered for q, with SearchType
+//set to TypeSeries, with OpByTitle.
+func (m *MockClient) GetSeriesByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*SeriesResult, error) {
+	q.SearchType = TypeSeries
+	return asSeries(m.call(ctx, OpByTitle, q))
+}
+
+//GetEpisodeByID returns the EpisodeResult registered for the id with OpByID.
+func (m *MockClient) GetEpisodeByID(ctx context.Context, id string, opts ...CallOption) (*EpisodeResult, error) {
+	return asEpisode(m.call(ctx, OpByID, QueryData{ImdbID: id}))
+}
+
+//GetEpisodeByTitle returns the EpisodeResult registered for q, with
+//SearchType set to TypeEpisode, with OpByTitle.
+func (m *MockClient) GetEpisodeByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*EpisodeResult, error) {
+	q.SearchType = TypeEpisode
+	return asEpisode(m.call(ctx, OpByTitle, q))
+}
@@ -0,0 +1,95 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestMockClient(t *testing.T) {
+
+	var api omdb.API = &omdb.MockClient{}
+	m := api.(*omdb.MockClient)
+	ctx := context.Background()
+
+	matrix := omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"}
+	m.Handle(omdb.OpByID, omdb.QueryData{ImdbID: "tt0133093"}, matrix, nil)
+	m.Handle(omdb.OpSearch, omdb.QueryData{Title: "matrix"}, &omdb.SearchResponse{TotalResults: "1"}, nil)
+	m.Handle(omdb.OpSeason, omdb.QueryData{ImdbID: "tt0903747", Season: "1"}, &omdb.SeasonResult{Season: "1"}, nil)
+
+	//queries are matched like CacheKey does.
+	res, err := api.SearchByImdbID(omdb.QueryData{ImdbID: "0133093"})
+	if err != nil || res.(omdb.MovieResult).Title != "The Matrix" {
+		t.Errorf("got %v, %v", res, err)
+	}
+	if movie, err := api.GetMovieByID(ctx, "tt0133093"); err != nil || movie.ImdbID != "tt0133093" {
+		t.Errorf("got %v, %v", movie, err)
+	}
+	if search, err := api.SearchByText(omdb.QueryData{Title: "matrix"}); err != nil || search.TotalResults != "1" {
+		t.Errorf("got %v, %v", search, err)
+	}
+	if season, err := api.GetSeason(ctx, "tt0903747", 1); err != nil || season.Season != "1" {
+		t.Errorf("got %v, %v", season, err)
+	}
+
+	//a result of another type is a mismatch, an unstubbed query isn't found.
+	if _, err := api.GetSeriesByID(ctx, "tt0133093"); !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("got error %v, want ErrTypeMismatch", err)
+	}
+	if _, err := api.SearchByTitle(omdb.QueryData{Title: "Dune"}); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for an unstubbed query, want ErrNotFound", err)
+	}
+	if _, err := api.GetEpisode(ctx, "tt0903747", 1, 2); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for an unstubbed episode, want ErrNotFound", err)
+	}
+
+	calls := m.Calls()
+	if len(calls) != 7 || calls[0].Op != omdb.OpByID || calls[0].Query.ImdbID != "0133093" || calls[6].Op != omdb.OpEpisode {
+		t.Errorf("got calls %+v", calls)
+	}
+
+	m.Reset()
+	if _, err := api.GetMovieByID(ctx, "tt0133093"); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v after Reset, want ErrNotFound", err)
+	}
+	if len(m.Calls()) != 1 {
+		t.Errorf("got %d calls after Reset, want 1", len(m.Calls()))
+	}
+}
+
+func TestMockClientScript(t *testing.T) {
+
+	m := &omdb.MockClient{}
+	ctx := context.Background()
+	q := omdb.QueryData{Title: "Dune"}
+	unavailable := errors.New("unavailable")
+	m.Handle(omdb.OpByTitle, q, nil, unavailable)
+	m.Handle(omdb.OpByTitle, q, omdb.MovieResult{Title: "Dune"}, nil)
+
+	if _, err := m.SearchByTitle(q); err != unavailable {
+		t.Errorf("got error %v, want the first response", err)
+	}
+	for i := 0; i < 2; i++ {
+		if res, err := m.SearchByTitle(q); err != nil || res.(omdb.MovieResult).Title != "Dune" {
+			t.Errorf("call %d: got %v, %v, want the last response", i+2, res, err)
+		}
+	}
+
+	//Func answers the unstubbed queries, a done context fails every call.
+	m.Func = func(ctx context.Context, op string, q omdb.QueryData) (interface{}, error) {
+		return omdb.SeriesResult{Title: q.Title}, nil
+	}
+	if series, err := m.GetSeriesByTitle(ctx, omdb.QueryData{Title: "Severance"}); err != nil || series.Title != "Severance" {
+		t.Errorf("got %v, %v from Func", series, err)
+	}
+	if calls := m.Calls(); calls[len(calls)-1].Query.SearchType != omdb.TypeSeries {
+		t.Errorf("got query %+v, want SearchType series", calls[len(calls)-1].Query)
+	}
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := m.SearchByTitleContext(done, q); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
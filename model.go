@@ -1,13 +1,20 @@
 package omdb
 
+import "net/url"
+
 // QueryData is the type to create a search query.
 type QueryData struct {
 	Title      string
 	Year       string
 	ImdbID     string
-	SearchType string
-	Plot       string
+	SearchType SearchType
+	Plot       Plot
 	Page       string
+	Season     string
+	Episode    string
+
+	//Tomatoes requests the extended Rotten Tomatoes fields of MovieResult.
+	Tomatoes bool
 }
 
 //resultEnvelope will be used to unmarshall API response for checking Type.
@@ -17,6 +24,14 @@ type resultEnvelope struct {
 	Type     string
 	Response string
 	Error    string
+
+	//TotalSeasons and SeriesID are only inspected in lenient decoding to
+	//detect a Type which doesn't match the payload. Season and Episode are
+	//only reported for episodes.
+	TotalSeasons string
+	SeriesID     string `json:"seriesID"`
+	Season       string
+	Episode      string
 }
 
 //MovieResult will hold information of a single movie.
@@ -44,6 +59,22 @@ type MovieResult struct {
 	BoxOffice  string
 	Production string
 	Website    string
+
+	//Rotten Tomatoes fields, only returned when QueryData.Tomatoes is set.
+	TomatoMeter       string
+	TomatoImage       string
+	TomatoRating      string
+	TomatoReviews     string
+	TomatoFresh       string
+	TomatoRotten      string
+	TomatoConsensus   string
+	TomatoUserMeter   string
+	TomatoUserRating  string
+	TomatoUserReviews string
+	TomatoURL         string
+
+	//Raw is the response payload, see WithRawResponse.
+	Raw []byte `json:"-"`
 }
 
 //SeriesResult will hold information of a single series.
@@ -68,6 +99,9 @@ type SeriesResult struct {
 	ImdbVotes    string
 	ImdbID       string
 	TotalSeasons string
+
+	//Raw is the response payload, see WithRawResponse.
+	Raw []byte `json:"-"`
 }
 
 //EpisodeResult will hold information of a single episode.
@@ -92,6 +126,12 @@ type EpisodeResult struct {
 	ImdbVotes  string
 	ImdbID     string
 	SeriesID   string
+	//Season and Episode are the numbers of the episode in its series.
+	Season  string
+	Episode string
+
+	//Raw is the response payload, see WithRawResponse.
+	Raw []byte `json:"-"`
 }
 
 //Rating will hold rating information from a single source.
@@ -100,12 +140,54 @@ type Rating struct {
 	Value  string
 }
 
+//SeasonResult will hold the episode list of a single season of a series.
+type SeasonResult struct {
+	Title        string
+	Season       string
+	TotalSeasons string
+	Episodes     []SeasonEpisode
+
+	//Raw is the response payload, see WithRawResponse.
+	Raw []byte `json:"-"`
+}
+
+//SeasonEpisode represents a single episode of a SeasonResult.
+//Season is not part of the API response, it is filled by GetAllEpisodes.
+type SeasonEpisode struct {
+	Title      string
+	Released   string
+	Season     string
+	Episode    string
+	ImdbRating string
+	ImdbID     string
+}
+
 //SearchResponse is a container holding one or more SearchResults.
+//Total is TotalResults as a number, 0 when OMDB doesn't report it.
 type SearchResponse struct {
 	Search       []SearchResult
 	TotalResults string
+	Total        int `json:"-"`
 	Response     string
 	Error        string
+
+	//Raw is the response payload, see WithRawResponse.
+	Raw []byte `json:"-"`
+}
+
+//TotalPages returns the number of pages of the search, limited to the pages
+//OMDB serves.
+func (s *SearchResponse) TotalPages() int {
+	pages := (s.Total + pageSize - 1) / pageSize
+	if pages > maxPage {
+		pages = maxPage
+	}
+	return pages
+}
+
+//HasMore reports whether there is a page after page.
+func (s *SearchResponse) HasMore(page int) bool {
+	return page < s.TotalPages()
 }
 
 //SearchResult represents a single result from API search by text.
@@ -116,3 +198,48 @@ type SearchResult struct {
 	Type   string
 	Poster string
 }
+
+//PosterURL parses the Poster of the movie. ErrNotAvailable is returned when
+//the movie has no poster.
+func (m MovieResult) PosterURL() (*url.URL, error) {
+	return parsePoster(m.Poster)
+}
+
+//PosterURL parses the Poster of the series. ErrNotAvailable is returned when
+//the series has no poster.
+func (s SeriesResult) PosterURL() (*url.URL, error) {
+	return parsePoster(s.Poster)
+}
+
+//PosterURL parses the Poster of the episode. ErrNotAvailable is returned when
+//the episode has no poster.
+func (e EpisodeResult) PosterURL() (*url.URL, error) {
+	return parsePoster(e.Poster)
+}
+
+//PosterURL parses the Poster of the search result. ErrNotAvailable is returned
+//when the result has no poster.
+func (s SearchResult) PosterURL() (*url.URL, error) {
+	return parsePoster(s.Poster)
+}
+
+func parsePoster(poster string) (*url.URL, error) {
+	if poster == "" || poster == "N/A" {
+		return nil, ErrNotAvailable
+	}
+	return url.ParseRequestURI(poster)
+}
+
+//TypeOf returns "movie", "series" or "episode" for a MovieResult, SeriesResult
+//or EpisodeResult (or a pointer to one) and "unknown" for anything else.
+func TypeOf(r interface{}) string {
+	switch r.(type) {
+	case MovieResult, *MovieResult:
+		return "movie"
+	case SeriesResult, *SeriesResult:
+		return "series"
+	case EpisodeResult, *EpisodeResult:
+		return "episode"
+	}
+	return "unknown"
+}
@@ -0,0 +1,334 @@
+//Package nfo exports OMDB results as the .nfo XML files Kodi reads local
+//metadata from: movie.nfo next to a movie, tvshow.nfo in the folder of a
+//series and an nfo named like the video file of each episode.
+//
+//	movie, err := client.GetMovieByID(ctx, "tt0133093")
+//	...
+//	err = nfo.WriteFile(filepath.Join(dir, nfo.MovieFile), nfo.Movie(*movie))
+//
+//Fields OMDB reports as "N/A" are left out.
+package nfo
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+//MovieFile and TVShowFile are the names Kodi looks for in the folder of a
+//movie and of a series.
+const (
+	MovieFile  = "movie.nfo"
+	TVShowFile = "tvshow.nfo"
+)
+
+//MovieNFO is the <movie> document of a movie.
+type MovieNFO struct {
+	XMLName   xml.Name   `xml:"movie"`
+	Title     string     `xml:"title"`
+	Ratings   Ratings    `xml:"ratings"`
+	Plot      string     `xml:"plot,omitempty"`
+	Runtime   int        `xml:"runtime,omitempty"`
+	MPAA      string     `xml:"mpaa,omitempty"`
+	UniqueIDs []UniqueID `xml:"uniqueid"`
+	Genres    []string   `xml:"genre"`
+	Countries []string   `xml:"country"`
+	Credits   []string   `xml:"credits"`
+	Directors []string   `xml:"director"`
+	Premiered string     `xml:"premiered,omitempty"`
+	Year      string     `xml:"year,omitempty"`
+	Studio    string     `xml:"studio,omitempty"`
+	Actors    []Actor    `xml:"actor"`
+	Thumbs    []Thumb    `xml:"thumb"`
+}
+
+//TVShowNFO is the <tvshow> document of a series.
+type TVShowNFO struct {
+	XMLName   xml.Name   `xml:"tvshow"`
+	Title     string     `xml:"title"`
+	Ratings   Ratings    `xml:"ratings"`
+	Plot      string     `xml:"plot,omitempty"`
+	MPAA      string     `xml:"mpaa,omitempty"`
+	UniqueIDs []UniqueID `xml:"uniqueid"`
+	Genres    []string   `xml:"genre"`
+	Premiered string     `xml:"premiered,omitempty"`
+	Year      string     `xml:"year,omitempty"`
+	Seasons   int        `xml:"season,omitempty"`
+	Actors    []Actor    `xml:"actor"`
+	Thumbs    []Thumb    `xml:"thumb"`
+}
+
+//EpisodeNFO is the <episodedetails> document of an episode.
+type EpisodeNFO struct {
+	XMLName   xml.Name   `xml:"episodedetails"`
+	Title     string     `xml:"title"`
+	ShowTitle string     `xml:"showtitle,omitempty"`
+	Season    int        `xml:"season"`
+	Episode   int        `xml:"episode"`
+	Ratings   Ratings    `xml:"ratings"`
+	Plot      string     `xml:"plot,omitempty"`
+	Runtime   int        `xml:"runtime,omitempty"`
+	MPAA      string     `xml:"mpaa,omitempty"`
+	UniqueIDs []UniqueID `xml:"uniqueid"`
+	Credits   []string   `xml:"credits"`
+	Directors []string   `xml:"director"`
+	Aired     string     `xml:"aired,omitempty"`
+	Actors    []Actor    `xml:"actor"`
+	Thumbs    []Thumb    `xml:"thumb"`
+}
+
+//Ratings are the ratings of a title, left out of the document when empty.
+type Ratings []Rating
+
+//MarshalXML encodes the ratings as <rating> elements of start.
+func (r Ratings) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(r) == 0 {
+		return nil
+	}
+	return e.EncodeElement(struct {
+		Rating []Rating `xml:"rating"`
+	}{r}, start)
+}
+
+//Rating is a rating of a title on the scale of its source, e.g. 8.7 of 10
+//for "imdb".
+type Rating struct {
+	Name    string  `xml:"name,attr"`
+	Max     int     `xml:"max,attr"`
+	Default bool    `xml:"default,attr,omitempty"`
+	Value   float64 `xml:"value"`
+	Votes   int     `xml:"votes,omitempty"`
+}
+
+//UniqueID is the id of a title on a site, e.g. the imdb id.
+type UniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr,omitempty"`
+	ID      string `xml:",chardata"`
+}
+
+//Actor is an actor of a title, in billing order.
+type Actor struct {
+	Name  string `xml:"name"`
+	Order int    `xml:"order"`
+}
+
+//Thumb is the URL of an image of a title, e.g. of the poster.
+type Thumb struct {
+	Aspect string `xml:"aspect,attr,omitempty"`
+	URL    string `xml:",chardata"`
+}
+
+//ratingNames maps the Source names used by OMDB to the names Kodi uses.
+var ratingNames = map[omdb.RatingSource]string{
+	omdb.SourceIMDb:           "imdb",
+	omdb.SourceRottenTomatoes: "tomatometerallcritics",
+	omdb.SourceMetacritic:     "metacritic",
+}
+
+//Movie returns the MovieNFO of m.
+func Movie(m omdb.MovieResult) *MovieNFO {
+
+	t := m.Typed()
+	return &MovieNFO{
+		Title:     m.Title,
+		Ratings:   ratings(m.Ratings, t.ImdbVotes),
+		Plot:      na(m.Plot),
+		Runtime:   minutes(t.Runtime),
+		MPAA:      na(m.Rated),
+		UniqueIDs: uniqueIDs(m.ImdbID),
+		Genres:    m.Genres(),
+		Countries: m.Countries(),
+		Credits:   m.WritersList(),
+		Directors: m.DirectorsList(),
+		Premiered: date(t.Released),
+		Year:      year(m.Year),
+		Studio:    na(m.Production),
+		Actors:    actors(m.ActorsList()),
+		Thumbs:    poster(m.Poster),
+	}
+}
+
+//TVShow returns the TVShowNFO of s.
+func TVShow(s omdb.SeriesResult) *TVShowNFO {
+
+	t := s.Typed()
+	seasons, _ := strconv.Atoi(s.TotalSeasons)
+	return &TVShowNFO{
+		Title:     s.Title,
+		Ratings:   ratings(s.Ratings, t.ImdbVotes),
+		Plot:      na(s.Plot),
+		MPAA:      na(s.Rated),
+		UniqueIDs: uniqueIDs(s.ImdbID),
+		Genres:    s.Genres(),
+		Premiered: date(t.Released),
+		Year:      year(s.Year),
+		Seasons:   seasons,
+		Actors:    actors(s.ActorsList()),
+		Thumbs:    poster(s.Poster),
+	}
+}
+
+//Episode returns the EpisodeNFO of e, episode number episode of season
+//season of the series titled showTitle. OMDB doesn't report the numbers with
+//the episode, they are those it was looked up by.
+func Episode(e omdb.EpisodeResult, showTitle string, season, episode int) *EpisodeNFO {
+
+	t := e.Typed()
+	return &EpisodeNFO{
+		Title:     e.Title,
+		ShowTitle: showTitle,
+		Season:    season,
+		Episode:   episode,
+		Ratings:   ratings(e.Ratings, t.ImdbVotes),
+		Plot:      na(e.Plot),
+		Runtime:   minutes(t.Runtime),
+		MPAA:      na(e.Rated),
+		UniqueIDs: uniqueIDs(e.ImdbID),
+		Credits:   e.WritersList(),
+		Directors: e.DirectorsList(),
+		Aired:     date(t.Released),
+		Actors:    actors(e.ActorsList()),
+		Thumbs:    poster(e.Poster),
+	}
+}
+
+//Write writes v, a MovieNFO, TVShowNFO or EpisodeNFO, to w as an indented
+//XML document.
+func Write(w io.Writer, v interface{}) error {
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+//WriteFile writes v like Write to the file at path, replacing it.
+func WriteFile(path string, v interface{}) error {
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, v); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+//EpisodeFile returns the path of the nfo of the episode in the video file at
+//path: the path with its extension replaced by ".nfo".
+func EpisodeFile(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".nfo"
+}
+
+//ratings converts the ratings OMDB reports into Kodi's, the IMDb one, with
+//votes, being the default.
+func ratings(list []omdb.Rating, votes int) Ratings {
+
+	var out Ratings
+	for _, r := range list {
+		name, ok := ratingNames[r.Kind()]
+		if !ok {
+			continue
+		}
+		value, max, ok := parseRating(r.Value)
+		if !ok {
+			continue
+		}
+		rating := Rating{Name: name, Max: max, Value: value}
+		if r.Kind() == omdb.SourceIMDb {
+			rating.Default, rating.Votes = true, votes
+		}
+		out = append(out, rating)
+	}
+	return out
+}
+
+//parseRating parses a rating value like "8.7/10", "88%" or "73/100".
+func parseRating(s string) (value float64, max int, ok bool) {
+
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		value, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		return value, 100, err == nil
+	}
+	i := strings.Index(s, "/")
+	if i < 0 {
+		return 0, 0, false
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	max, err = strconv.Atoi(s[i+1:])
+	if err != nil || max <= 0 {
+		return 0, 0, false
+	}
+	return value, max, true
+}
+
+func uniqueIDs(imdbID string) []UniqueID {
+	if na(imdbID) == "" {
+		return nil
+	}
+	return []UniqueID{{Type: "imdb", Default: true, ID: imdbID}}
+}
+
+func actors(names []string) []Actor {
+	var out []Actor
+	for i, name := range names {
+		out = append(out, Actor{Name: name, Order: i})
+	}
+	return out
+}
+
+func poster(url string) []Thumb {
+	if na(url) == "" {
+		return nil
+	}
+	return []Thumb{{Aspect: "poster", URL: url}}
+}
+
+func minutes(d time.Duration) int {
+	return int(d / time.Minute)
+}
+
+//date formats t the way Kodi expects it, "" for the zero time.
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+//year returns the first year of a Year field like "1999" or "2008–2013".
+func year(s string) string {
+	if len(s) >= 4 {
+		if _, err := strconv.Atoi(s[:4]); err == nil {
+			return s[:4]
+		}
+	}
+	return ""
+}
+
+//na maps OMDB's "N/A" placeholder to an empty string.
+func na(s string) string {
+	if s == "N/A" {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package nfo_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/nfo"
+)
+
+var matrix = omdb.MovieResult{
+	Title:      "The Matrix",
+	Year:       "1999",
+	Rated:      "R",
+	Released:   "31 Mar 1999",
+	Runtime:    "136 min",
+	Genre:      "Action, Sci-Fi",
+	Director:   "Lana Wachowski, Lilly Wachowski",
+	Writer:     "Lilly Wachowski, Lana Wachowski",
+	Actors:     "Keanu Reeves, Laurence Fishburne",
+	Plot:       "N/A",
+	Country:    "United States, Australia",
+	Poster:     "https://m.media-amazon.com/images/M/matrix.jpg",
+	ImdbRating: "8.7",
+	ImdbVotes:  "2,000,000",
+	ImdbID:     "tt0133093",
+	Production: "N/A",
+	Ratings: []omdb.Rating{
+		{Source: "Internet Movie Database", Value: "8.7/10"},
+		{Source: "Rotten Tomatoes", Value: "83%"},
+		{Source: "Metacritic", Value: "73/100"},
+	},
+}
+
+func TestMovie(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	if err := nfo.Write(buf, nfo.Movie(matrix)); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		"<movie>",
+		"<title>The Matrix</title>",
+		`<rating name="imdb" max="10" default="true">` + "\n      <value>8.7</value>\n      <votes>2000000</votes>",
+		`<rating name="tomatometerallcritics" max="100">` + "\n      <value>83</value>",
+		`<rating name="metacritic" max="100">`,
+		"<runtime>136</runtime>",
+		"<mpaa>R</mpaa>",
+		`<uniqueid type="imdb" default="true">tt0133093</uniqueid>`,
+		"<genre>Action</genre>\n  <genre>Sci-Fi</genre>",
+		"<director>Lana Wachowski</director>",
+		"<premiered>1999-03-31</premiered>",
+		"<year>1999</year>",
+		"<actor>\n    <name>Laurence Fishburne</name>\n    <order>1</order>",
+		`<thumb aspect="poster">https://m.media-amazon.com/images/M/matrix.jpg</thumb>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("missing %q in\n%s", want, doc)
+		}
+	}
+	for _, field := range []string{"<plot>", "<studio>", "N/A"} {
+		if strings.Contains(doc, field) {
+			t.Errorf("got %q for a field not available:\n%s", field, doc)
+		}
+	}
+}
+
+func TestTVShowAndEpisode(t *testing.T) {
+
+	show := nfo.TVShow(omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", TotalSeasons: "5", ImdbID: "tt0903747", Poster: "N/A"})
+	if show.Year != "2008" || show.Seasons != 5 || show.Thumbs != nil || len(show.Ratings) != 0 {
+		t.Errorf("got %+v", show)
+	}
+	buf := &bytes.Buffer{}
+	if err := nfo.Write(buf, show); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<ratings>") {
+		t.Errorf("got ratings without any:\n%s", buf)
+	}
+
+	dir := t.TempDir()
+	path := nfo.EpisodeFile(filepath.Join(dir, "Breaking Bad - S01E02.mkv"))
+	if filepath.Base(path) != "Breaking Bad - S01E02.nfo" {
+		t.Errorf("got episode file %s", path)
+	}
+	episode := nfo.Episode(omdb.EpisodeResult{Title: "Cat's in the Bag...", Released: "27 Jan 2008", ImdbID: "tt1054724"}, "Breaking Bad", 1, 2)
+	if err := nfo.WriteFile(path, episode); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<episodedetails>", "<showtitle>Breaking Bad</showtitle>", "<season>1</season>", "<episode>2</episode>", "<aired>2008-01-27</aired>"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("missing %q in\n%s", want, data)
+		}
+	}
+}
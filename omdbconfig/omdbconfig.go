@@ -0,0 +1,275 @@
+//Package omdbconfig builds an omdb.Client from a YAML or TOML file, so
+//deployments can tune the key, cache, rate limits and retries without
+//recompiling:
+//
+//	api_key: 1a2b3c4d
+//	timeout: 10s
+//	cache:
+//	  backend: redis
+//	  addr: localhost:6379
+//	  ttl: 24h
+//	rate_limit:
+//	  per_day: 1000
+//	  burst: 10
+//	retry:
+//	  attempts: 3
+//	  base: 200ms
+//
+//Load the file and create the client with:
+//
+//	cfg, err := omdbconfig.Load("omdb.yaml")
+//	...
+//	client, err := cfg.NewClient()
+//	...
+//	defer cfg.Close()
+package omdbconfig
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/boltcache"
+	"github.com/ahin/omdb/rediscache"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/yaml.v3"
+)
+
+//Formats accepted by Parse.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+//Cache backends accepted by CacheConfig.Backend.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendBolt   = "bolt"
+)
+
+//DefaultCacheSize is the number of entries of the memory cache unless
+//CacheConfig.Size is set.
+const DefaultCacheSize = 1000
+
+//Config holds the settings of a client. Durations are written like "10s" or
+//"24h". Unset settings keep the defaults of the omdb package.
+type Config struct {
+	//APIKey falls back to the OMDB_API_KEY environment variable, so the
+	//file doesn't have to hold the secret.
+	APIKey    string        `yaml:"api_key" toml:"api_key"`
+	BaseURL   string        `yaml:"base_url" toml:"base_url"`
+	Timeout   time.Duration `yaml:"timeout" toml:"timeout"`
+	UserAgent string        `yaml:"user_agent" toml:"user_agent"`
+
+	Cache     *CacheConfig     `yaml:"cache" toml:"cache"`
+	RateLimit *RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Retry     *RetryConfig     `yaml:"retry" toml:"retry"`
+
+	closers []io.Closer
+}
+
+//CacheConfig selects the cache backend of the client.
+type CacheConfig struct {
+	//Backend is BackendMemory, BackendRedis or BackendBolt.
+	Backend string        `yaml:"backend" toml:"backend"`
+	TTL     time.Duration `yaml:"ttl" toml:"ttl"`
+
+	//Size is the number of entries of the memory backend.
+	Size int `yaml:"size" toml:"size"`
+	//Addr is the host:port of the Redis server.
+	Addr string `yaml:"addr" toml:"addr"`
+	//Prefix replaces rediscache.DefaultPrefix.
+	Prefix string `yaml:"prefix" toml:"prefix"`
+	//Path is the database file of the bolt backend.
+	Path string `yaml:"path" toml:"path"`
+}
+
+//RateLimitConfig limits the rate of requests, either PerSecond or PerDay.
+type RateLimitConfig struct {
+	PerSecond float64 `yaml:"per_second" toml:"per_second"`
+	PerDay    int     `yaml:"per_day" toml:"per_day"`
+	Burst     int     `yaml:"burst" toml:"burst"`
+}
+
+//RetryConfig is the omdb.RetryPolicy of the client.
+type RetryConfig struct {
+	Attempts int           `yaml:"attempts" toml:"attempts"`
+	Base     time.Duration `yaml:"base" toml:"base"`
+	Max      time.Duration `yaml:"max" toml:"max"`
+	Jitter   float64       `yaml:"jitter" toml:"jitter"`
+	Budget   float64       `yaml:"budget" toml:"budget"`
+}
+
+//Load reads the config file at path, its format is told by the extension:
+//.yaml, .yml or .toml.
+func Load(path string) (*Config, error) {
+
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".toml":
+		format = FormatTOML
+	default:
+		return nil, errors.New("omdbconfig: Unknown config file extension " + filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, format)
+}
+
+//Parse decodes a config in format, FormatYAML or FormatTOML. Unknown keys are
+//an error, so typos don't go unnoticed.
+func Parse(data []byte, format string) (*Config, error) {
+
+	cfg := &Config{}
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && err != io.EOF {
+			return nil, err
+		}
+	case FormatTOML:
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, errors.New("omdbconfig: Unknown key " + undecoded[0].String())
+		}
+	default:
+		return nil, errors.New("omdbconfig: Unknown config format " + format)
+	}
+	return cfg, nil
+}
+
+//Options returns the omdb.Options configured by cfg. A Redis or bolt cache is
+//opened by the call, Close releases it; it is released already when Options
+//returns an error.
+func (cfg *Config) Options() ([]omdb.Option, error) {
+
+	var opts []omdb.Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, omdb.WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, omdb.WithTimeout(cfg.Timeout))
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, omdb.WithUserAgent(cfg.UserAgent))
+	}
+
+	if cfg.Cache != nil {
+		cache, err := cfg.openCache()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, omdb.WithCache(cache, cfg.Cache.TTL))
+	}
+
+	if r := cfg.RateLimit; r != nil {
+		switch {
+		case r.PerSecond > 0 && r.PerDay > 0:
+			cfg.Close()
+			return nil, errors.New("omdbconfig: Rate limit should be either per_second or per_day")
+		case r.PerSecond > 0:
+			opts = append(opts, omdb.WithRateLimit(omdb.Limit(r.PerSecond), r.Burst))
+		case r.PerDay > 0:
+			opts = append(opts, omdb.WithRateLimit(omdb.PerDay(r.PerDay), r.Burst))
+		default:
+			cfg.Close()
+			return nil, errors.New("omdbconfig: Rate limit needs per_second or per_day")
+		}
+	}
+
+	if r := cfg.Retry; r != nil {
+		opts = append(opts, omdb.WithRetryPolicy(omdb.RetryPolicy{
+			MaxAttempts: r.Attempts,
+			Base:        r.Base,
+			Max:         r.Max,
+			Jitter:      r.Jitter,
+			Budget:      r.Budget,
+		}))
+	}
+
+	return opts, nil
+}
+
+//NewClient creates the omdb.Client configured by cfg with omdb.New. opts are
+//applied after the configured options and override them.
+func (cfg *Config) NewClient(opts ...omdb.Option) (*omdb.Client, error) {
+
+	key := cfg.APIKey
+	if key == "" {
+		key = os.Getenv(omdb.EnvAPIKey)
+	}
+
+	configured, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	client, err := omdb.New(key, append(configured, opts...)...)
+	if err != nil {
+		cfg.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+//Close releases the caches opened by Options or NewClient.
+func (cfg *Config) Close() error {
+	var first error
+	for _, c := range cfg.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	cfg.closers = nil
+	return first
+}
+
+func (cfg *Config) openCache() (omdb.Cache, error) {
+
+	c := cfg.Cache
+	switch c.Backend {
+	case "", BackendMemory:
+		size := c.Size
+		if size <= 0 {
+			size = DefaultCacheSize
+		}
+		return omdb.NewLRUCache(size), nil
+	case BackendRedis:
+		if c.Addr == "" {
+			return nil, errors.New("omdbconfig: Redis cache needs addr")
+		}
+		client := redis.NewClient(&redis.Options{Addr: c.Addr})
+		cfg.closers = append(cfg.closers, client)
+		var opts []rediscache.Option
+		if c.Prefix != "" {
+			opts = append(opts, rediscache.WithPrefix(c.Prefix))
+		}
+		return rediscache.New(client, opts...), nil
+	case BackendBolt:
+		if c.Path == "" {
+			return nil, errors.New("omdbconfig: Bolt cache needs path")
+		}
+		cache, err := boltcache.Open(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.closers = append(cfg.closers, cache)
+		return cache, nil
+	}
+	return nil, errors.New("omdbconfig: Unknown cache backend " + c.Backend)
+}
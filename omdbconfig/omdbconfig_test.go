@@ -0,0 +1,29 @@
+package omdbconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ahin/omdb/boltcache"
+	"github.com/ahin/omdb/omdbconfig"
+)
+
+func TestOptionsClosesCacheOnError(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "cache.db")
+	yaml := "api_key: 1a2b3c4d\ncache:\n  backend: bolt\n  path: " + path + "\nrate_limit:\n  burst: 10\n"
+	cfg, err := omdbconfig.Parse([]byte(yaml), omdbconfig.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Options(); err == nil {
+		t.Fatal("got no error for a rate limit without per_second or per_day")
+	}
+
+	//the file is released, it can be opened again.
+	cache, err := boltcache.Open(path)
+	if err != nil {
+		t.Fatalf("the cache is still open: %v", err)
+	}
+	cache.Close()
+}
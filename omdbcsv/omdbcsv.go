@@ -0,0 +1,160 @@
+//Package omdbcsv writes OMDB results and search results as CSV, one row per
+//title, to triage large lookups like those of Client.Enrich in a
+//spreadsheet:
+//
+//	enc := omdbcsv.NewEncoder(os.Stdout, "ImdbID", "Title", "Year", "ImdbRating")
+//	for _, res := range results {
+//		if err := enc.Encode(res); err != nil {
+//			...
+//		}
+//	}
+//	err := enc.Flush()
+package omdbcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//DefaultFields are the columns of an Encoder created without fields.
+var DefaultFields = []string{"ImdbID", "Title", "Year", "Type", "Genre", "Director", "Runtime", "ImdbRating", "ImdbVotes", "Metascore"}
+
+//SearchFields are the fields of a SearchResult.
+var SearchFields = []string{"ImdbID", "Title", "Year", "Type", "Poster"}
+
+//Encoder writes results as CSV rows, the columns being the fields it was
+//created with in their order. A header row naming the fields is written
+//before the first row.
+type Encoder struct {
+	w           *csv.Writer
+	fields      []string
+	wroteHeader bool
+}
+
+//NewEncoder returns an Encoder writing to w the columns fields, names of the
+//fields of MovieResult, SeriesResult, EpisodeResult or SearchResult, or
+//DefaultFields when there are none. "Type" is the type of the title for
+//every result, and "Ratings" the ratings joined like
+//"Internet Movie Database: 8.7/10; Metacritic: 73/100". A row has an empty
+//cell for a field its result doesn't have, e.g. "Genre" of a SearchResult.
+func NewEncoder(w io.Writer, fields ...string) *Encoder {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	return &Encoder{w: csv.NewWriter(w), fields: fields}
+}
+
+//Encode writes the rows of v: a MovieResult, SeriesResult, EpisodeResult or
+//SearchResult, a pointer to one, a SearchResponse, or a slice of any of
+//them, like the results of Client.Enrich. nil elements are skipped. A field
+//which none of the results has is an error.
+func (e *Encoder) Encode(v interface{}) error {
+
+	if !e.wroteHeader {
+		for _, f := range e.fields {
+			if !knownField(f) {
+				return errors.New("omdbcsv: Unknown field " + f)
+			}
+		}
+		if err := e.w.Write(e.fields); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	return e.encode(reflect.ValueOf(v))
+}
+
+func (e *Encoder) encode(v reflect.Value) error {
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch r := v.Interface().(type) {
+	case omdb.SearchResponse:
+		return e.encode(reflect.ValueOf(r.Search))
+	case omdb.MovieResult:
+		return e.w.Write(e.row(v, string(omdb.TypeMovie)))
+	case omdb.SeriesResult:
+		return e.w.Write(e.row(v, string(omdb.TypeSeries)))
+	case omdb.EpisodeResult:
+		return e.w.Write(e.row(v, string(omdb.TypeEpisode)))
+	case omdb.SearchResult:
+		return e.w.Write(e.row(v, r.Type))
+	}
+	return errors.New("omdbcsv: Can't encode a " + v.Type().String())
+}
+
+//Flush writes the buffered rows to the underlying writer.
+func (e *Encoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *Encoder) row(v reflect.Value, typ string) []string {
+
+	row := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		switch f {
+		case "Type":
+			row[i] = typ
+		case "Ratings":
+			if field := v.FieldByName(f); field.IsValid() {
+				row[i] = joinRatings(field.Interface().([]omdb.Rating))
+			}
+		default:
+			if field := v.FieldByName(f); field.Kind() == reflect.String {
+				row[i] = field.String()
+			}
+		}
+	}
+	return row
+}
+
+func joinRatings(ratings []omdb.Rating) string {
+	parts := make([]string, len(ratings))
+	for i, r := range ratings {
+		parts[i] = r.Source + ": " + r.Value
+	}
+	return strings.Join(parts, "; ")
+}
+
+var resultTypes = []reflect.Type{
+	reflect.TypeOf(omdb.MovieResult{}),
+	reflect.TypeOf(omdb.SeriesResult{}),
+	reflect.TypeOf(omdb.EpisodeResult{}),
+	reflect.TypeOf(omdb.SearchResult{}),
+}
+
+//knownField reports whether name is a column the Encoder can fill.
+func knownField(name string) bool {
+	if name == "Type" || name == "Ratings" {
+		return true
+	}
+	for _, t := range resultTypes {
+		if f, ok := t.FieldByName(name); ok && f.Type.Kind() == reflect.String {
+			return true
+		}
+	}
+	return false
+}
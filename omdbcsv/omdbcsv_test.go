@@ -0,0 +1,79 @@
+package omdbcsv_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbcsv"
+)
+
+func TestEncode(t *testing.T) {
+
+	var buf bytes.Buffer
+	enc := omdbcsv.NewEncoder(&buf, "ImdbID", "Title", "Type", "Genre", "Ratings")
+	matrix := omdb.MovieResult{
+		Title:  "The Matrix",
+		ImdbID: "tt0133093",
+		Genre:  "Action, Sci-Fi",
+		Ratings: []omdb.Rating{
+			{Source: "Internet Movie Database", Value: "8.7/10"},
+			{Source: "Metacritic", Value: "73/100"},
+		},
+	}
+	results := []interface{}{
+		matrix,
+		&omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747"},
+		nil,
+		(*omdb.EpisodeResult)(nil),
+		omdb.EpisodeResult{Title: "Pilot", ImdbID: "tt0959621"},
+		&omdb.SearchResponse{Search: []omdb.SearchResult{{Title: "Dune", ImdbID: "tt1160419", Type: "movie"}}},
+	}
+	if err := enc.Encode(results); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode([]omdb.SearchResult{{Title: "Sherlock", ImdbID: "tt1475582", Type: "series"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "ImdbID,Title,Type,Genre,Ratings\n" +
+		"tt0133093,The Matrix,movie,\"Action, Sci-Fi\",Internet Movie Database: 8.7/10; Metacritic: 73/100\n" +
+		"tt0903747,Breaking Bad,series,,\n" +
+		"tt0959621,Pilot,episode,,\n" +
+		"tt1160419,Dune,movie,,\n" +
+		"tt1475582,Sherlock,series,,\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDefaultFields(t *testing.T) {
+
+	var buf bytes.Buffer
+	enc := omdbcsv.NewEncoder(&buf)
+	if err := enc.Encode(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093", Year: "1999", ImdbRating: "8.7"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := "ImdbID,Title,Year,Type,Genre,Director,Runtime,ImdbRating,ImdbVotes,Metascore\n" +
+		"tt0133093,The Matrix,1999,movie,,,,8.7,,\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+
+	var buf bytes.Buffer
+	if err := omdbcsv.NewEncoder(&buf, "Title", "Budget").Encode(omdb.MovieResult{}); err == nil {
+		t.Error("got no error for an unknown field")
+	}
+	if err := omdbcsv.NewEncoder(&buf, "Title").Encode("The Matrix"); err == nil {
+		t.Error("got no error for a string")
+	}
+}
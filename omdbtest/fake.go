@@ -0,0 +1,228 @@
+package omdbtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+var (
+	fakeAdjectives = []string{"Silent", "Last", "Broken", "Golden", "Hidden", "Dark", "Lost", "Eternal", "Wild", "Crimson"}
+	fakeNouns      = []string{"Horizon", "Empire", "River", "Night", "Garden", "Signal", "Kingdom", "Shadow", "Machine", "Voyage"}
+	fakeGenres     = []string{"Action", "Adventure", "Comedy", "Crime", "Drama", "Fantasy", "Horror", "Mystery", "Romance", "Sci-Fi", "Thriller"}
+	fakeFirstNames = []string{"Anna", "James", "Maria", "Kenji", "Olivia", "Lucas", "Priya", "Noah", "Elena", "Omar"}
+	fakeLastNames  = []string{"Smith", "Garcia", "Tanaka", "Rossi", "Novak", "Khan", "Müller", "Silva", "Brown", "Dubois"}
+	fakeLanguages  = []string{"English", "French", "Spanish", "Japanese", "German", "Italian", "Hindi"}
+	fakeCountries  = []string{"United States", "France", "Spain", "Japan", "Germany", "Italy", "India", "United Kingdom"}
+	fakeRated      = []string{"G", "PG", "PG-13", "R", "TV-14", "TV-MA"}
+)
+
+//Faker generates realistic random results, with the formats OMDB uses for
+//dates, runtimes, ratings and votes, for property tests and for developing
+//against the models without the API. The same seed generates the same
+//results. A Faker is not safe for concurrent use.
+type Faker struct {
+	rnd *rand.Rand
+}
+
+//NewFaker creates a Faker generating its results from seed.
+func NewFaker(seed int64) *Faker {
+	return &Faker{rnd: rand.New(rand.NewSource(seed))}
+}
+
+//Movie returns a random movie.
+func (f *Faker) Movie() omdb.MovieResult {
+
+	released := f.date(1930, 2024)
+	rating := f.rating()
+	return omdb.MovieResult{
+		Title:      f.title(),
+		Year:       strconv.Itoa(released.Year()),
+		Rated:      f.pick(fakeRated),
+		Released:   released.Format("02 Jan 2006"),
+		Runtime:    strconv.Itoa(80+f.rnd.Intn(100)) + " min",
+		Genre:      f.list(fakeGenres, 3),
+		Director:   f.person(),
+		Writer:     f.people(2),
+		Actors:     f.people(3),
+		Plot:       f.plot(),
+		Language:   f.list(fakeLanguages, 2),
+		Country:    f.list(fakeCountries, 2),
+		Awards:     f.awards(),
+		Poster:     "https://m.media-amazon.com/images/M/" + f.id("MV5B", 10) + "._V1_SX300.jpg",
+		Ratings:    f.ratings(rating),
+		Metascore:  strconv.Itoa(20 + f.rnd.Intn(80)),
+		ImdbRating: fmt.Sprintf("%.1f", rating),
+		ImdbVotes:  f.votes(),
+		ImdbID:     f.id("tt", 7),
+		DVD:        released.AddDate(0, 6, 0).Format("02 Jan 2006"),
+		BoxOffice:  "$" + thousands(1e5+f.rnd.Int63n(5e8)),
+		Production: "N/A",
+		Website:    "N/A",
+	}
+}
+
+//Series returns a random series.
+func (f *Faker) Series() omdb.SeriesResult {
+
+	released := f.date(1960, 2022)
+	seasons := 1 + f.rnd.Intn(8)
+	rating := f.rating()
+	return omdb.SeriesResult{
+		Title:        f.title(),
+		Year:         strconv.Itoa(released.Year()) + "–" + strconv.Itoa(released.Year()+seasons),
+		Rated:        f.pick(fakeRated),
+		Released:     released.Format("02 Jan 2006"),
+		Runtime:      strconv.Itoa(20+f.rnd.Intn(40)) + " min",
+		Genre:        f.list(fakeGenres, 3),
+		Director:     "N/A",
+		Writer:       f.people(2),
+		Actors:       f.people(3),
+		Plot:         f.plot(),
+		Language:     f.list(fakeLanguages, 2),
+		Country:      f.list(fakeCountries, 1),
+		Awards:       f.awards(),
+		Poster:       "https://m.media-amazon.com/images/M/" + f.id("MV5B", 10) + "._V1_SX300.jpg",
+		Ratings:      f.ratings(rating)[:1],
+		Metascore:    "N/A",
+		ImdbRating:   fmt.Sprintf("%.1f", rating),
+		ImdbVotes:    f.votes(),
+		ImdbID:       f.id("tt", 7),
+		TotalSeasons: strconv.Itoa(seasons),
+	}
+}
+
+//Episode returns a random episode of the series seriesID.
+func (f *Faker) Episode(seriesID string) omdb.EpisodeResult {
+
+	released := f.date(1960, 2024)
+	rating := f.rating()
+	return omdb.EpisodeResult{
+		Title:      f.title(),
+		Year:       strconv.Itoa(released.Year()),
+		Rated:      f.pick(fakeRated),
+		Released:   released.Format("02 Jan 2006"),
+		Runtime:    strconv.Itoa(20+f.rnd.Intn(40)) + " min",
+		Genre:      f.list(fakeGenres, 2),
+		Director:   f.person(),
+		Writer:     f.people(2),
+		Actors:     f.people(3),
+		Plot:       f.plot(),
+		Language:   f.list(fakeLanguages, 1),
+		Country:    f.list(fakeCountries, 1),
+		Awards:     "N/A",
+		Poster:     "N/A",
+		Ratings:    f.ratings(rating)[:1],
+		Metascore:  "N/A",
+		ImdbRating: fmt.Sprintf("%.1f", rating),
+		ImdbVotes:  f.votes(),
+		ImdbID:     f.id("tt", 7),
+		SeriesID:   seriesID,
+		Season:     strconv.Itoa(1 + f.rnd.Intn(8)),
+		Episode:    strconv.Itoa(1 + f.rnd.Intn(20)),
+	}
+}
+
+func (f *Faker) pick(list []string) string {
+	return list[f.rnd.Intn(len(list))]
+}
+
+//list joins up to n distinct items of list like OMDB does.
+func (f *Faker) list(list []string, n int) string {
+	n = 1 + f.rnd.Intn(n)
+	items := make([]string, 0, n)
+	for _, i := range f.rnd.Perm(len(list))[:n] {
+		items = append(items, list[i])
+	}
+	return strings.Join(items, ", ")
+}
+
+func (f *Faker) title() string {
+	if f.rnd.Intn(3) == 0 {
+		return "The " + f.pick(fakeNouns)
+	}
+	return f.pick(fakeAdjectives) + " " + f.pick(fakeNouns)
+}
+
+func (f *Faker) person() string {
+	return f.pick(fakeFirstNames) + " " + f.pick(fakeLastNames)
+}
+
+func (f *Faker) people(n int) string {
+	n = 1 + f.rnd.Intn(n)
+	names := make([]string, n)
+	for i := range names {
+		names[i] = f.person()
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f *Faker) plot() string {
+	return "A " + strings.ToLower(f.pick(fakeAdjectives)) + " " + strings.ToLower(f.pick(fakeNouns)) +
+		" changes everything for " + f.person() + "."
+}
+
+func (f *Faker) date(from, to int) time.Time {
+	start := time.Date(from, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(to, 12, 31, 0, 0, 0, 0, time.UTC).Sub(start).Hours() / 24)
+	return start.AddDate(0, 0, f.rnd.Intn(days))
+}
+
+//rating returns an IMDb rating between 1.0 and 9.9.
+func (f *Faker) rating() float64 {
+	return float64(10+f.rnd.Intn(90)) / 10
+}
+
+//ratings returns the IMDb, Rotten Tomatoes and Metacritic ratings around the
+//IMDb rating.
+func (f *Faker) ratings(imdb float64) []omdb.Rating {
+	tomatoes := int(imdb*10) + f.rnd.Intn(21) - 10
+	if tomatoes < 0 {
+		tomatoes = 0
+	}
+	if tomatoes > 100 {
+		tomatoes = 100
+	}
+	return []omdb.Rating{
+		{Source: "Internet Movie Database", Value: fmt.Sprintf("%.1f/10", imdb)},
+		{Source: "Rotten Tomatoes", Value: strconv.Itoa(tomatoes) + "%"},
+		{Source: "Metacritic", Value: strconv.Itoa(20+f.rnd.Intn(80)) + "/100"},
+	}
+}
+
+func (f *Faker) votes() string {
+	return thousands(100 + f.rnd.Int63n(2000000))
+}
+
+func (f *Faker) awards() string {
+	switch f.rnd.Intn(3) {
+	case 0:
+		return "N/A"
+	case 1:
+		return fmt.Sprintf("%d wins & %d nominations", 1+f.rnd.Intn(20), 1+f.rnd.Intn(40))
+	}
+	return fmt.Sprintf("Won %d Oscars. %d wins & %d nominations total", 1+f.rnd.Intn(5), 10+f.rnd.Intn(100), 20+f.rnd.Intn(150))
+}
+
+//id returns prefix followed by n random digits.
+func (f *Faker) id(prefix string, n int) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i := 0; i < n; i++ {
+		b.WriteByte(byte('0' + f.rnd.Intn(10)))
+	}
+	return b.String()
+}
+
+//thousands formats n with comma separated thousands, e.g. "1,234,567".
+func thousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
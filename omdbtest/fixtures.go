@@ -0,0 +1,84 @@
+package omdbtest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+//LoadFixture reads a golden JSON response of a lookup, as saved from OMDB,
+//and decodes it into the MovieResult, SeriesResult or EpisodeResult matching
+//its Type.
+func LoadFixture(path string) (interface{}, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := struct {
+		Type     string
+		Response string
+		Error    string
+	}{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Response == "False" {
+		return nil, &omdb.APIError{Message: envelope.Error}
+	}
+
+	var res interface{}
+	switch envelope.Type {
+	case string(omdb.TypeMovie):
+		movie := omdb.MovieResult{}
+		err = json.Unmarshal(data, &movie)
+		res = movie
+	case string(omdb.TypeSeries):
+		series := omdb.SeriesResult{}
+		err = json.Unmarshal(data, &series)
+		res = series
+	case string(omdb.TypeEpisode):
+		episode := omdb.EpisodeResult{}
+		err = json.Unmarshal(data, &episode)
+		res = episode
+	default:
+		return nil, errors.New("omdbtest: Unknown result type " + envelope.Type + " in " + path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+//Fixture is like LoadFixture but fails tb on error.
+func Fixture(tb testing.TB, path string) interface{} {
+	tb.Helper()
+	res, err := LoadFixture(path)
+	if err != nil {
+		tb.Fatalf("omdbtest: loading fixture %s: %v", path, err)
+	}
+	return res
+}
+
+//Load adds the results of the golden fixtures at paths to s, see LoadFixture.
+func (s *Server) Load(paths ...string) error {
+	for _, path := range paths {
+		res, err := LoadFixture(path)
+		if err != nil {
+			return err
+		}
+		switch r := res.(type) {
+		case omdb.MovieResult:
+			s.AddMovie(r)
+		case omdb.SeriesResult:
+			s.AddSeries(r)
+		case omdb.EpisodeResult:
+			s.AddEpisode(r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+//Package omdbtest provides a fake OMDB server for hermetic integration tests.
+//It answers lookups by id and title, text searches, seasons and episodes from
+//in-memory fixtures, with the same error envelopes as OMDB.
+//
+//	srv := omdbtest.NewServer()
+//	defer srv.Close()
+//	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+//	client := srv.Client()
+package omdbtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ahin/omdb"
+)
+
+//APIKey is the only API key the Server accepts.
+const APIKey = "omdbtestkey"
+
+//pageSize is the number of results per search page, as with OMDB.
+const pageSize = 10
+
+//Server is a fake OMDB API running on an httptest.Server. Only the JSON
+//response format is served. Fixtures can be added while the server runs.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	results  map[string]fixture
+	order    []string
+	seasons  map[string]omdb.SeasonResult
+	requests int
+}
+
+//fixture is a result with the type reported in its envelope.
+type fixture struct {
+	typ    string
+	title  string
+	year   string
+	poster string
+	result interface{}
+}
+
+//NewServer starts a Server without fixtures. It has to be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		results: map[string]fixture{},
+		seasons: map[string]omdb.SeasonResult{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+//Client returns an omdb.Client sending its requests to s with APIKey.
+func (s *Server) Client(opts ...omdb.Option) *omdb.Client {
+	opts = append([]omdb.Option{omdb.WithBaseURL(s.URL + "/")}, opts...)
+	return omdb.NewClient(APIKey, s.Server.Client(), opts...)
+}
+
+//Requests returns the number of requests served so far.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+//AddMovie adds a movie, found by its ImdbID, Title and in searches.
+func (s *Server) AddMovie(m omdb.MovieResult) {
+	s.add(m.ImdbID, fixture{typ: string(omdb.TypeMovie), title: m.Title, year: m.Year, poster: m.Poster, result: m})
+}
+
+//AddSeries adds a series, found by its ImdbID, Title and in searches.
+func (s *Server) AddSeries(r omdb.SeriesResult) {
+	s.add(r.ImdbID, fixture{typ: string(omdb.TypeSeries), title: r.Title, year: r.Year, poster: r.Poster, result: r})
+}
+
+//AddEpisode adds an episode, found by its ImdbID, Title and in searches.
+//Add the season listing it with AddSeason to find it by its series, season
+//and episode numbers too.
+func (s *Server) AddEpisode(e omdb.EpisodeResult) {
+	s.add(e.ImdbID, fixture{typ: string(omdb.TypeEpisode), title: e.Title, year: e.Year, poster: e.Poster, result: e})
+}
+
+//AddSeason adds the episode list of a season of the series seriesID.
+func (s *Server) AddSeason(seriesID string, season omdb.SeasonResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seasons[seriesID+"/"+season.Season] = season
+}
+
+func (s *Server) add(id string, f fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.results[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.results[id] = f
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	q := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	switch key := q.Get("apikey"); {
+	case key == "":
+		writeError(w, http.StatusUnauthorized, "No API key provided.")
+		return
+	case key != APIKey:
+		writeError(w, http.StatusUnauthorized, "Invalid API key!")
+		return
+	}
+
+	switch {
+	case q.Get("s") != "":
+		s.search(w, q.Get("s"), q.Get("type"), q.Get("y"), q.Get("page"))
+	case q.Get("i") != "" && q.Get("Season") != "":
+		s.season(w, q.Get("i"), q.Get("Season"), q.Get("Episode"))
+	case q.Get("i") != "":
+		f, ok := s.results[q.Get("i")]
+		if !ok {
+			writeError(w, http.StatusOK, "Incorrect IMDb ID.")
+			return
+		}
+		writeResult(w, f.typ, f.result)
+	case q.Get("t") != "":
+		for _, id := range s.order {
+			f := s.results[id]
+			if strings.EqualFold(f.title, q.Get("t")) && matches(f, q.Get("type"), q.Get("y")) {
+				writeResult(w, f.typ, f.result)
+				return
+			}
+		}
+		writeError(w, http.StatusOK, "Movie not found!")
+	default:
+		writeError(w, http.StatusOK, "Incorrect IMDb ID.")
+	}
+}
+
+func (s *Server) search(w http.ResponseWriter, text, typ, year, page string) {
+
+	p := 1
+	if page != "" {
+		var err error
+		p, err = strconv.Atoi(page)
+		if err != nil || p < 1 || p > 100 {
+			writeError(w, http.StatusOK, "The offset specified in a OFFSET clause may not be negative.")
+			return
+		}
+	}
+
+	var found []omdb.SearchResult
+	for _, id := range s.order {
+		f := s.results[id]
+		if strings.Contains(strings.ToLower(f.title), strings.ToLower(text)) && matches(f, typ, year) {
+			found = append(found, omdb.SearchResult{Title: f.title, Year: f.year, ImdbID: id, Type: f.typ, Poster: f.poster})
+		}
+	}
+
+	start := (p - 1) * pageSize
+	if start >= len(found) {
+		writeError(w, http.StatusOK, "Movie not found!")
+		return
+	}
+	end := start + pageSize
+	if end > len(found) {
+		end = len(found)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Search":       omdbJSON(found[start:end]),
+		"totalResults": strconv.Itoa(len(found)),
+		"Response":     "True",
+	})
+}
+
+func (s *Server) season(w http.ResponseWriter, seriesID, season, episode string) {
+
+	list, ok := s.seasons[seriesID+"/"+season]
+	if !ok {
+		writeError(w, http.StatusOK, "Series or season not found!")
+		return
+	}
+
+	if episode == "" {
+		writeJSON(w, http.StatusOK, withEnvelope(list, ""))
+		return
+	}
+
+	for _, e := range list.Episodes {
+		if e.Episode != episode {
+			continue
+		}
+		if f, ok := s.results[e.ImdbID]; ok {
+			writeResult(w, f.typ, f.result)
+			return
+		}
+	}
+	writeError(w, http.StatusOK, "Series or episode not found!")
+}
+
+//matches reports whether f passes the type and year filters of a query.
+//The year of a series matches the first year of its run.
+func matches(f fixture, typ, year string) bool {
+	return (typ == "" || typ == f.typ) && (year == "" || strings.HasPrefix(f.year, year))
+}
+
+func writeResult(w http.ResponseWriter, typ string, result interface{}) {
+	writeJSON(w, http.StatusOK, withEnvelope(result, typ))
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"Response": "False", "Error": msg})
+}
+
+//withEnvelope adds the Response and, when set, Type fields OMDB reports
+//along with every result.
+func withEnvelope(result interface{}, typ string) map[string]interface{} {
+	fields, _ := omdbJSON(result).(map[string]interface{})
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	if typ != "" {
+		fields["Type"] = typ
+	}
+	fields["Response"] = "True"
+	return fields
+}
+
+//omdbJSON returns v as decoded JSON with the keys OMDB uses, e.g. "imdbID"
+//and "totalSeasons" rather than the names of the fields of the omdb result
+//structs. The Season of the episodes of a season is left out, OMDB doesn't
+//report it.
+func omdbJSON(v interface{}) interface{} {
+	data, _ := json.Marshal(v)
+	var decoded interface{}
+	_ = json.Unmarshal(data, &decoded)
+	return renameKeys(decoded, false)
+}
+
+func renameKeys(v interface{}, episode bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if episode && key == "Season" {
+				continue
+			}
+			renamed[omdbKey(key)] = renameKeys(value, key == "Episodes")
+		}
+		return renamed
+	case []interface{}:
+		for i := range v {
+			v[i] = renameKeys(v[i], episode)
+		}
+	}
+	return v
+}
+
+//omdbKey returns the key OMDB uses for the field name of a result struct.
+func omdbKey(field string) string {
+	switch {
+	case field == "TotalSeasons", field == "SeriesID", field == "TotalResults",
+		strings.HasPrefix(field, "Imdb"), strings.HasPrefix(field, "Tomato"):
+		return strings.ToLower(field[:1]) + field[1:]
+	}
+	return field
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,67 @@
+package omdbtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func get(t *testing.T, s *Server, query string) map[string]interface{} {
+	t.Helper()
+	res, err := http.Get(s.URL + "/?apikey=" + APIKey + "&" + query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	fields := map[string]interface{}{}
+	if err := json.NewDecoder(res.Body).Decode(&fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestServerUsesOMDBKeys(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.AddSeries(omdb.SeriesResult{Title: "Severance", ImdbID: "tt11280740", ImdbRating: "8.7", ImdbVotes: "300,000", TotalSeasons: "2"})
+	s.AddEpisode(omdb.EpisodeResult{Title: "Good News About Hell", ImdbID: "tt11650328", SeriesID: "tt11280740"})
+	s.AddSeason("tt11280740", omdb.SeasonResult{Title: "Severance", Season: "1", TotalSeasons: "2", Episodes: []omdb.SeasonEpisode{
+		{Title: "Good News About Hell", Episode: "1", Season: "1", ImdbRating: "8.2", ImdbID: "tt11650328"},
+	}})
+
+	series := get(t, s, "i=tt11280740")
+	for _, key := range []string{"imdbID", "imdbRating", "imdbVotes", "totalSeasons", "Title", "Type", "Response"} {
+		if _, ok := series[key]; !ok {
+			t.Errorf("series has no %q: %v", key, series)
+		}
+	}
+	if _, ok := series["ImdbID"]; ok {
+		t.Errorf("series has the field name ImdbID: %v", series)
+	}
+
+	if episode := get(t, s, "i=tt11650328"); episode["seriesID"] != "tt11280740" {
+		t.Errorf("episode has no seriesID: %v", episode)
+	}
+
+	season := get(t, s, "i=tt11280740&Season=1")
+	episodes, _ := season["Episodes"].([]interface{})
+	if len(episodes) != 1 || season["totalSeasons"] != "2" || season["Season"] != "1" {
+		t.Fatalf("got season %v", season)
+	}
+	e := episodes[0].(map[string]interface{})
+	if e["imdbID"] != "tt11650328" || e["imdbRating"] != "8.2" {
+		t.Errorf("got episode %v", e)
+	}
+	if _, ok := e["Season"]; ok {
+		t.Errorf("episode of a season has a Season: %v", e)
+	}
+
+	search := get(t, s, "s=severance")
+	results, _ := search["Search"].([]interface{})
+	if len(results) != 1 || results[0].(map[string]interface{})["imdbID"] != "tt11280740" || search["totalResults"] != "1" {
+		t.Errorf("got search %v", search)
+	}
+}
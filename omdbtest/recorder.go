@@ -0,0 +1,196 @@
+package omdbtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+)
+
+//Mode tells a Recorder whether to send requests or replay recorded ones.
+type Mode int
+
+const (
+	//ModeAuto replays the cassette when its file exists, otherwise it
+	//records one.
+	ModeAuto Mode = iota
+	//ModeRecord always sends the requests and rewrites the cassette.
+	ModeRecord
+	//ModeReplay only replays the cassette, a request without a recorded
+	//response fails.
+	ModeReplay
+)
+
+//scrubbed replaces the API key in the recorded URLs.
+const scrubbed = "REDACTED"
+
+//Recorder is an http.RoundTripper recording the responses of OMDB to a
+//cassette file on the first run and replaying them afterwards, so tests
+//against the real API stay deterministic and don't use up the quota:
+//
+//	rec := omdbtest.NewRecorder("testdata/matrix.json", nil)
+//	client := omdb.NewClient(key, &http.Client{Transport: rec})
+//
+//Requests are matched by method and URL, ignoring the API key, which is never
+//written to the cassette. A recorded request is replayed in the order it was
+//recorded, the last response of a request is reused once its responses run
+//out.
+type Recorder struct {
+	//Transport sends the requests while recording, http.DefaultTransport
+	//when nil.
+	Transport http.RoundTripper
+
+	path         string
+	mode         Mode
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     map[int]bool
+	loaded       bool
+	err          error
+}
+
+//Interaction is a request and its response as stored in a cassette.
+type Interaction struct {
+	Method string
+	URL    string
+	Status int
+	Header http.Header
+	Body   string
+}
+
+//NewRecorder creates a Recorder using the cassette at path in ModeAuto.
+//transport sends the requests while recording, http.DefaultTransport when
+//nil.
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	return &Recorder{path: path, Transport: transport}
+}
+
+//SetMode replaces ModeAuto with mode. It has to be called before the first
+//request.
+func (r *Recorder) SetMode(mode Mode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mode = mode
+}
+
+//RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	key := scrubURL(req.URL)
+	if r.mode == ModeReplay {
+		return r.replay(req, key)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.interactions = append(r.interactions, Interaction{
+		Method: req.Method,
+		URL:    key,
+		Status: res.StatusCode,
+		Header: res.Header,
+		Body:   string(body),
+	})
+	return res, r.save()
+}
+
+//load reads the cassette on the first request and settles ModeAuto.
+func (r *Recorder) load() error {
+
+	if r.loaded {
+		return r.err
+	}
+	r.loaded = true
+	r.replayed = map[int]bool{}
+
+	data, err := ioutil.ReadFile(r.path)
+	switch {
+	case r.mode == ModeRecord:
+		return nil
+	case errors.Is(err, os.ErrNotExist) && r.mode == ModeAuto:
+		r.mode = ModeRecord
+		return nil
+	case err != nil:
+		r.err = err
+		return err
+	}
+
+	r.mode = ModeReplay
+	r.err = json.Unmarshal(data, &r.interactions)
+	return r.err
+}
+
+func (r *Recorder) replay(req *http.Request, key string) (*http.Response, error) {
+
+	last := -1
+	for i, in := range r.interactions {
+		if in.Method != req.Method || in.URL != key {
+			continue
+		}
+		last = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, errors.New("omdbtest: No recorded response for " + req.Method + " " + key)
+	}
+	r.replayed[last] = true
+
+	in := r.interactions[last]
+	return &http.Response{
+		Status:        strconv.Itoa(in.Status) + " " + http.StatusText(in.Status),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(in.Body))),
+		ContentLength: int64(len(in.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.interactions, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, data, 0o644)
+}
+
+//scrubURL returns u with its apikey parameter redacted and its parameters
+//sorted, so it can be written to a cassette and matched against.
+func scrubURL(u *url.URL) string {
+	cp := *u
+	params := cp.Query()
+	if params.Get("apikey") != "" {
+		params.Set("apikey", scrubbed)
+	}
+	cp.RawQuery = params.Encode()
+	return cp.String()
+}
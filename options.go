@@ -0,0 +1,412 @@
+package omdb
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//Option configures a Client, it is passed to New or NewClient.
+type Option func(*Client) error
+
+//WithHTTPClient sets the http.Client used to send requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) error {
+		if client == nil {
+			return errors.New("omdb: http.Client should not be nil")
+		}
+		c.httpClient = client
+		return nil
+	}
+}
+
+//WithBaseURL sends requests to baseURL instead of DefaultURL, e.g. a mirror
+//of the API or a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		if err := checkBaseURL(baseURL); err != nil {
+			return err
+		}
+		c.baseURL = baseURL
+		return nil
+	}
+}
+
+//WithPosterBaseURL sends Poster API requests to baseURL instead of
+//DefaultPosterURL.
+func WithPosterBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		if err := checkBaseURL(baseURL); err != nil {
+			return err
+		}
+		c.posterURL = baseURL
+		return nil
+	}
+}
+
+func checkBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("omdb: Base URL should be an http or https URL")
+	}
+	return nil
+}
+
+//WithTimeout limits every request, retries included, to d. Unlike a Timeout
+//set on the http.Client it doesn't affect other users of that client.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("omdb: Timeout should be greater than 0")
+		}
+		c.timeout = d
+		return nil
+	}
+}
+
+//WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+//WithRetry retries a request up to attempts times when it fails with a
+//network error, a 429 or a 5xx response. The first retry waits backoff, the
+//wait is doubled for each following one. See WithRetryPolicy for jitter and
+//a retry budget.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return WithRetryPolicy(RetryPolicy{MaxAttempts: attempts, Base: backoff})
+}
+
+//WithRetryPolicy retries failed requests according to policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy.MaxAttempts < 0 || policy.Base < 0 || policy.Max < 0 || policy.Budget < 0 {
+			return errors.New("omdb: Retry attempts, backoff and budget should not be negative")
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return errors.New("omdb: Retry jitter should be between 0 and 1")
+		}
+		c.retry = policy
+		return nil
+	}
+}
+
+//WithDefaultTimeout sets the deadline applied to requests which have no other
+//deadline, i.e. when the http.Client has no Timeout and the context passed to
+//a method carries no deadline. It defaults to DefaultTimeout, a zero value
+//disables it and lets such requests wait forever.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return errors.New("omdb: Default timeout should not be negative")
+		}
+		c.defaultTimeout = d
+		return nil
+	}
+}
+
+//WithMaxResponseSize sets the largest response body, in bytes, the client
+//reads from the API. A larger response fails with an error matching
+//ErrResponseTooLarge instead of being buffered, so a misbehaving server or
+//mirror can't exhaust memory. It defaults to DefaultMaxResponseSize.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) error {
+		if n < 1 {
+			return errors.New("omdb: Max response size should be greater than 0")
+		}
+		c.maxBodySize = n
+		return nil
+	}
+}
+
+//WithDebug writes every request of the client to w, with the API key masked,
+//followed by the status and body of its response or by its error. It is
+//meant to find out why a lookup fails, not for production logging.
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) error {
+		if w == nil {
+			return errors.New("omdb: Debug writer is nil")
+		}
+		c.debug = &debugLog{w: w}
+		return nil
+	}
+}
+
+//WithLogger emits structured events to logger: "omdb request started",
+//"omdb request finished" and "omdb request failed" for every attempt,
+//"omdb request retry", "omdb rate limit wait", "omdb cache hit" and "omdb cache
+//miss". Their attributes are named url (with the API key masked), attempt,
+//status, duration, error, backoff, wait and cache_key. Failures are logged
+//at the warn level, retries at the info level and the rest at the debug
+//level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+//WithLenientDecode makes lookups tolerate a Type which doesn't match the rest
+//of the payload, as seen with some OMDB mirrors. When the result decoded for
+//the reported Type misses its Title or ImdbID, or the payload carries fields
+//of another type (TotalSeasons, seriesID), every result type is tried and the
+//best populated one is returned. By default the reported Type is trusted.
+func WithLenientDecode() Option {
+	return func(c *Client) error {
+		c.lenientDecode = true
+		return nil
+	}
+}
+
+//WithEmptyNotAvailable makes every result report missing data as an empty
+//string instead of OMDB's "N/A" placeholder, including the fields of Ratings,
+//search results and season episodes.
+func WithEmptyNotAvailable() Option {
+	return func(c *Client) error {
+		c.emptyNA = true
+		return nil
+	}
+}
+
+//WithRawResponse keeps the payload of every response in the Raw field of
+//the result, JSON or XML depending on WithResponseFormat, so fields the
+//result structs don't model yet can still be decoded by the caller.
+func WithRawResponse() Option {
+	return func(c *Client) error {
+		c.keepRaw = true
+		return nil
+	}
+}
+
+//WithStrictDecode makes every JSON response with a field the result structs
+//don't model fail with an error matching ErrUnknownField, which names the
+//fields. It lets integrators notice when OMDB adds or renames fields instead
+//of silently dropping them.
+func WithStrictDecode() Option {
+	return func(c *Client) error {
+		c.strictDecode = true
+		return nil
+	}
+}
+
+//WithDefaultSearchType sets the SearchType used by SearchByTitle and
+//SearchByText when the query leaves it blank, e.g. TypeMovie for a movies only
+//application. A SearchType set on the query still takes precedence.
+func WithDefaultSearchType(searchType SearchType) Option {
+	return func(c *Client) error {
+		if !searchType.Valid() {
+			return errors.New("omdb: Default searchtype should be one of following: movie, series, episode")
+		}
+		c.defaultSearchType = searchType
+		return nil
+	}
+}
+
+//WithDefaultPlot sets the Plot, PlotShort or PlotFull, requested by the
+//lookups when the query leaves it blank. A Plot set on the query or
+//WithFullPlot still takes precedence.
+func WithDefaultPlot(plot Plot) Option {
+	return func(c *Client) error {
+		if !plot.Valid() {
+			return errors.New("omdb: Default plot should be one of following: short, full")
+		}
+		c.defaultPlot = plot
+		return nil
+	}
+}
+
+//WithResponseFormat selects the format OMDB responds in, FormatJSON (the
+//default) or FormatXML. Either way responses are decoded into the same
+//result structs, but the XML format carries no Ratings.
+func WithResponseFormat(format string) Option {
+	return func(c *Client) error {
+		if format != FormatJSON && format != FormatXML {
+			return errors.New("omdb: Response format should be one of following: json, xml")
+		}
+		c.format = format
+		return nil
+	}
+}
+
+//WithCache makes the client look responses up in cache before requesting
+//them, successful responses are stored for ttl, or DefaultCacheTTL when ttl
+//is 0. OMDB data rarely changes, so caching saves most of the daily quota of
+//repeated lookups.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) error {
+		if cache == nil {
+			return errors.New("omdb: Cache should not be nil")
+		}
+		if ttl < 0 {
+			return errors.New("omdb: Cache ttl should not be negative")
+		}
+		if ttl == 0 {
+			ttl = DefaultCacheTTL
+		}
+		c.cache = cache
+		c.cacheTTL = ttl
+		return nil
+	}
+}
+
+//WithNegativeCache makes the client remember for ttl that a lookup was not
+//found, so that repeating it, e.g. for a misnamed file during a library scan,
+//doesn't use up the daily quota. The cache given to WithCache stores the
+//not found responses, negative caching is disabled without one. ttl is
+//usually shorter than the one of found results as OMDB adds new titles.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("omdb: Negative cache ttl should be greater than 0")
+		}
+		c.negativeTTL = ttl
+		return nil
+	}
+}
+
+//WithStaleWhileRevalidate keeps cached responses for staleFor past their
+//ttl. A lookup hitting such a stale entry gets it immediately while the entry
+//is refreshed in the background, so only the first lookup of a title ever
+//waits on the network. It requires WithCache; entries are stored with a small
+//header, so the cache should only be shared with clients using this mode or
+//WithConditionalRequests.
+func WithStaleWhileRevalidate(staleFor time.Duration) Option {
+	return func(c *Client) error {
+		if staleFor <= 0 {
+			return errors.New("omdb: Stale period should be greater than 0")
+		}
+		c.staleTTL = staleFor
+		return nil
+	}
+}
+
+//WithConditionalRequests keeps cached responses for keepFor past their ttl
+//together with the ETag and Last-Modified validators the API sent. Once such
+//an entry expires it is refreshed with a conditional request, a 304 response
+//renews the cached body without downloading or parsing it again. It requires
+//WithCache and, like WithStaleWhileRevalidate, stores entries with a small
+//header.
+func WithConditionalRequests(keepFor time.Duration) Option {
+	return func(c *Client) error {
+		if keepFor <= 0 {
+			return errors.New("omdb: Validator keep period should be greater than 0")
+		}
+		c.keepTTL = keepFor
+		return nil
+	}
+}
+
+//WithRateLimit limits the requests sent by the client, retries included, to
+//limit per second with bursts of up to burst requests; requests wait for
+//their turn. It can be given several times, e.g. to keep bulk jobs under both
+//a requests per second ceiling and the daily cap of a free key:
+//
+//	omdb.WithRateLimit(5, 1)
+//	omdb.WithRateLimit(omdb.PerDay(omdb.FreeTierDailyLimit), 100)
+//
+//Responses served from the cache don't count, and waiting before the first
+//attempt of a request doesn't count against WithTimeout or the default
+//timeout.
+func WithRateLimit(limit Limit, burst int) Option {
+	return func(c *Client) error {
+		if limit <= 0 {
+			return errors.New("omdb: Rate limit should be greater than 0")
+		}
+		c.limiters = append(c.limiters, NewLimiter(limit, burst))
+		return nil
+	}
+}
+
+//WithDailyQuota counts the requests sent by the client in tracker, once its
+//daily limit is used up requests fail with ErrQuotaExhausted without being
+//sent. Use the same tracker for every client sharing an API key:
+//
+//	tracker := omdb.NewQuotaTracker(omdb.FreeTierDailyLimit, omdb.FileQuotaStore{Path: "quota.json"})
+//	client, err := omdb.New(key, omdb.WithDailyQuota(tracker))
+func WithDailyQuota(tracker *QuotaTracker) Option {
+	return func(c *Client) error {
+		if tracker == nil {
+			return errors.New("omdb: Quota tracker should not be nil")
+		}
+		c.quota = tracker
+		return nil
+	}
+}
+
+//WithAPIKey replaces the API key the client was created with, and drops the
+//key pool of WithAPIKeys, e.g. to derive a client for another tenant with
+//Clone.
+func WithAPIKey(key string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return errors.New("omdb: API key should not be empty")
+		}
+		c.apiKey = key
+		c.keys = nil
+		return nil
+	}
+}
+
+//WithAPIKeys adds keys to the key the client was created with, forming a pool
+//the client rotates through: when OMDB answers a request with "Request limit
+//reached!" or rejects the key, the request is sent again with the next key
+//and the rejected one is skipped for the rest of the day, UTC. Once every key
+//is rejected requests fail with ErrDailyLimitExceeded. See Client.KeyUsage.
+//Empty keys are skipped, the client key included, so a client created with
+//NewClient("", nil, WithAPIKeys(keys...)) uses keys alone.
+func WithAPIKeys(keys ...string) Option {
+	return func(c *Client) error {
+		var pool []string
+		for _, key := range append([]string{c.apiKey}, keys...) {
+			if key != "" {
+				pool = append(pool, key)
+			}
+		}
+		if len(pool) == 0 {
+			return errors.New("omdb: API keys should not be empty")
+		}
+		c.apiKey = pool[0]
+		c.keys = newKeyPool(pool)
+		return nil
+	}
+}
+
+//WithCircuitBreaker stops sending requests for cooldown once threshold
+//requests in a row failed because OMDB is down or overloaded (network errors,
+//429 and 5xx responses after retries), requests fail fast with ErrCircuitOpen
+//instead. After cooldown a single request is let through: the breaker closes
+//if it succeeds, or opens again for another cooldown.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) error {
+		if threshold < 1 || cooldown <= 0 {
+			return errors.New("omdb: Circuit breaker threshold and cooldown should be greater than 0")
+		}
+		c.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+		return nil
+	}
+}
+
+//WithHedging cuts tail latency: when a request got no response after delay,
+//typically the p95 latency of OMDB, a second identical request is sent and
+//whichever response comes first is used, the other request is cancelled.
+//Hedged requests use more of the daily quota, at most one extra request per
+//slow request, and count against WithRateLimit: a request is only hedged
+//when the rate limiters allow one more right away.
+func WithHedging(delay time.Duration) Option {
+	return func(c *Client) error {
+		if delay <= 0 {
+			return errors.New("omdb: Hedging delay should be greater than 0")
+		}
+		c.hedgeDelay = delay
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+//Package oteltrace implements omdb.Tracer with OpenTelemetry, so OMDB
+//requests show up in the distributed traces of the services embedding the
+//client.
+//
+//	client := omdb.NewClient(key, nil, omdb.WithTracer(oteltrace.New(otel.GetTracerProvider())))
+package oteltrace
+
+import (
+	"context"
+
+	"github.com/ahin/omdb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//instrumentation is the name of the tracer, as the import path of the
+//instrumented package.
+const instrumentation = "github.com/ahin/omdb"
+
+//Attributes set on the spans, on top of http.response.status_code.
+const (
+	AttrOp       = attribute.Key("omdb.op")
+	AttrImdbID   = attribute.Key("omdb.imdb_id")
+	AttrTitle    = attribute.Key("omdb.title")
+	AttrCacheHit = attribute.Key("omdb.cache_hit")
+)
+
+//Tracer is an omdb.Tracer starting a client span named "omdb <op>" for every
+//request.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ omdb.Tracer = (*Tracer)(nil)
+
+//New creates a Tracer using a tracer of provider.
+func New(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(instrumentation)}
+}
+
+//StartRequest implements omdb.Tracer.
+func (t *Tracer) StartRequest(ctx context.Context, info omdb.RequestInfo) (context.Context, omdb.RequestSpan) {
+
+	attrs := []attribute.KeyValue{AttrOp.String(info.Op)}
+	if info.ImdbID != "" {
+		attrs = append(attrs, AttrImdbID.String(info.ImdbID))
+	}
+	if info.Title != "" {
+		attrs = append(attrs, AttrTitle.String(info.Title))
+	}
+
+	ctx, span := t.tracer.Start(ctx, "omdb "+info.Op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return ctx, requestSpan{span}
+}
+
+type requestSpan struct {
+	span trace.Span
+}
+
+func (s requestSpan) CacheHit(hit bool) {
+	s.span.SetAttributes(AttrCacheHit.Bool(hit))
+}
+
+func (s requestSpan) UpstreamStatus(code int) {
+	s.span.SetAttributes(attribute.Int("http.response.status_code", code))
+}
+
+func (s requestSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,78 @@
+package oteltrace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/oteltrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestTracer(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := srv.Client(omdb.WithTracer(oteltrace.New(provider)), omdb.WithCache(omdb.NewLRUCache(10), 0))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbIDContext(ctx, omdb.QueryData{ImdbID: "tt0133093"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.SearchByTitleContext(ctx, omdb.QueryData{Title: "Unknown"}); err == nil {
+		t.Fatal("got no error for an unknown title")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	miss, hit, failed := spans[0], spans[1], spans[2]
+	if miss.Name() != "omdb "+omdb.OpByID || miss.SpanKind() != trace.SpanKindClient {
+		t.Errorf("got span %q of kind %v", miss.Name(), miss.SpanKind())
+	}
+	a := attrs(miss)
+	if a[oteltrace.AttrOp].AsString() != omdb.OpByID || a[oteltrace.AttrImdbID].AsString() != "tt0133093" ||
+		a[oteltrace.AttrCacheHit].AsBool() || a["http.response.status_code"].AsInt64() != 200 {
+		t.Errorf("got attributes %v", a)
+	}
+	if miss.Status().Code != codes.Unset {
+		t.Errorf("got status %v", miss.Status())
+	}
+
+	a = attrs(hit)
+	if !a[oteltrace.AttrCacheHit].AsBool() {
+		t.Errorf("got attributes %v, want a cache hit", a)
+	}
+	if _, ok := a["http.response.status_code"]; ok {
+		t.Errorf("got a status code for a cache hit: %v", a)
+	}
+
+	a = attrs(failed)
+	if failed.Name() != "omdb "+omdb.OpByTitle || a[oteltrace.AttrTitle].AsString() != "Unknown" {
+		t.Errorf("got span %q with attributes %v", failed.Name(), a)
+	}
+	if failed.Status().Code != codes.Error || failed.Status().Description == "" || len(failed.Events()) != 1 {
+		t.Errorf("got status %v and events %v, want the error recorded", failed.Status(), failed.Events())
+	}
+}
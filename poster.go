@@ -0,0 +1,209 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//PosterURL returns the Poster API URL of the poster of imdbID, height pixels
+//high, or the default height of the API when height is 0. The URL carries the
+//API key of the client, so it should not be shared.
+func (c *Client) PosterURL(imdbID string, height int) (*url.URL, error) {
+
+	params, err := posterParams(imdbID, height)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("apikey", c.apiKey)
+
+	u, err := url.Parse(c.posterURL)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = params.Encode()
+
+	return u, nil
+}
+
+//GetPoster downloads the poster of imdbID from the Poster API, see PosterURL
+//for height. The caller must close the returned reader. The Poster API is
+//only available to patrons, other keys get an error matching
+//ErrInvalidAPIKey; a missing poster is an error matching ErrNotFound.
+func (c *Client) GetPoster(ctx context.Context, imdbID string, height int) (io.ReadCloser, error) {
+
+	params, err := posterParams(imdbID, height)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, c.posterURL, params, nil)
+	if err != nil {
+		var status statusError
+		if errors.As(err, &status) && status == http.StatusNotFound {
+			return nil, &wrappedError{msg: "omdb: Poster of " + imdbID + " not found", err: ErrNotFound}
+		}
+		return nil, err
+	}
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "image/") {
+		//errors are reported as a plain text body with a 200 status.
+		defer res.Body.Close()
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+		return nil, posterError(strings.TrimSpace(string(msg)))
+	}
+
+	return res.Body, nil
+}
+
+func posterParams(imdbID string, height int) (url.Values, error) {
+
+	if imdbID == "" {
+		return nil, invalidRequest("omdb: ImdbID is missing")
+	}
+	imdbID, err := NormalizeImdbID(imdbID)
+	if err != nil {
+		return nil, err
+	}
+	if height < 0 {
+		return nil, invalidRequest("omdb: Height should not be negative")
+	}
+
+	params := url.Values{}
+	params.Set("i", imdbID)
+	if height > 0 {
+		params.Set("h", strconv.Itoa(height))
+	}
+	return params, nil
+}
+
+//posterError maps an error message of the Poster API to an error.
+func posterError(msg string) error {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "key"), strings.Contains(lower, "patron"):
+		return &wrappedError{msg: "omdb: Error from Poster API: " + msg, err: ErrInvalidAPIKey}
+	case strings.Contains(lower, "not found"):
+		return &wrappedError{msg: "omdb: Error from Poster API: " + msg, err: ErrNotFound}
+	}
+	return errors.New("omdb: Error from Poster API: " + msg)
+}
+
+//WritePoster looks up imdbID and writes the image behind its Poster URL to w.
+//Unlike GetPoster it doesn't need a patron API key. ErrNotAvailable is
+//returned when the title has no poster.
+func (c *Client) WritePoster(ctx context.Context, imdbID string, w io.Writer) error {
+
+	res, err := c.fetchPoster(ctx, imdbID, time.Time{})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	_, err = io.Copy(w, res.Body)
+	return err
+}
+
+//DownloadPoster looks up imdbID and saves the image behind its Poster URL to
+//path. When path already exists the download is made conditional on the
+//image being modified since the file was written, so an up-to-date file is
+//left untouched; downloaded reports whether path was (re)written.
+func (c *Client) DownloadPoster(ctx context.Context, imdbID, path string) (downloaded bool, err error) {
+
+	var since time.Time
+	if fi, err := os.Stat(path); err == nil {
+		since = fi.ModTime()
+	}
+
+	res, err := c.fetchPoster(ctx, imdbID, since)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+
+	//write next to path and rename, so a failed download never leaves a
+	//truncated poster behind.
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, res.Body)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+//fetchPoster requests the Poster URL of imdbID, conditionally when since is
+//not zero. The response is either a 200 with an image or a 304.
+func (c *Client) fetchPoster(ctx context.Context, imdbID string, since time.Time) (*http.Response, error) {
+
+	res, err := c.SearchByImdbIDContext(ctx, QueryData{ImdbID: imdbID})
+	if err != nil {
+		return nil, err
+	}
+	r, ok := res.(Result)
+	if !ok || isNilResult(r) {
+		return nil, ErrNotAvailable
+	}
+	poster, err := r.PosterURL()
+	if err != nil {
+		return nil, err
+	}
+
+	//the image is bounded by the timeout of API requests, see withTimeout.
+	ctx, cancel := c.withTimeout(ctx)
+	req, err := http.NewRequestWithContext(ctx, "GET", poster.String(), nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if !since.IsZero() {
+		req.Header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+
+	//redirects are followed by the http.Client, below the middlewares.
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	//the deadline has to outlive this function as the caller reads the body.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, statusError(resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "image/") {
+		resp.Body.Close()
+		return nil, errors.New("omdb: Poster has unexpected content type " + ct)
+	}
+
+	return resp, nil
+}
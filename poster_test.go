@@ -0,0 +1,143 @@
+package omdb_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestDownloadPoster(t *testing.T) {
+
+	modified := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "poster.jpg", modified, bytes.NewReader([]byte("\xff\xd8image")))
+	}))
+	defer images.Close()
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093", Poster: images.URL + "/poster.jpg"})
+	srv.AddMovie(omdb.MovieResult{Title: "Unknown", ImdbID: "tt0000001", Poster: "N/A"})
+	client := srv.Client()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "poster.jpg")
+	downloaded, err := client.DownloadPoster(ctx, "tt0133093", path)
+	if err != nil || !downloaded {
+		t.Fatalf("got %v, %v, want the poster downloaded", downloaded, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "\xff\xd8image" {
+		t.Errorf("got poster %q", data)
+	}
+
+	//the file is newer than the image.
+	downloaded, err = client.DownloadPoster(ctx, "tt0133093", path)
+	if err != nil || downloaded {
+		t.Errorf("got %v, %v, want the poster left as it is", downloaded, err)
+	}
+
+	var buf bytes.Buffer
+	if err := client.WritePoster(ctx, "tt0000001", &buf); !errors.Is(err, omdb.ErrNotAvailable) {
+		t.Errorf("got error %v, want ErrNotAvailable", err)
+	}
+}
+
+func TestPosterTimeout(t *testing.T) {
+
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer images.Close()
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093", Poster: images.URL + "/poster.jpg"})
+
+	//the image is limited like the API requests.
+	client := srv.Client(omdb.WithTimeout(50 * time.Millisecond))
+	start := time.Now()
+	if err := client.WritePoster(context.Background(), "tt0133093", io.Discard); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("got the poster after %v", elapsed)
+	}
+}
+
+func TestPosterURL(t *testing.T) {
+
+	client := omdb.NewClient("secret", nil, omdb.WithPosterBaseURL("https://img.example.com/"))
+	u, err := client.PosterURL("0133093", 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "img.example.com" || u.Query().Get("apikey") != "secret" || u.Query().Get("i") != "tt0133093" || u.Query().Get("h") != "600" {
+		t.Errorf("got URL %v", u)
+	}
+	if u, err := client.PosterURL("tt0133093", 0); err != nil || u.Query().Has("h") {
+		t.Errorf("got URL %v, %v, want no height", u, err)
+	}
+	if _, err := client.PosterURL("tt0133093", -1); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for a negative height, want ErrInvalidRequest", err)
+	}
+	if _, err := client.PosterURL("", 0); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v without an id, want ErrInvalidRequest", err)
+	}
+}
+
+func TestGetPoster(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("apikey") != "patron":
+			w.Write([]byte("Invalid API key. This API is only available to patrons."))
+		case q.Get("i") == "tt0000001":
+			w.Write([]byte("Error: Poster not found!"))
+		case q.Get("i") == "tt0000002":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("\xff\xd8" + q.Get("h")))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := omdb.NewClient("patron", nil, omdb.WithPosterBaseURL(srv.URL))
+	body, err := client.GetPoster(ctx, "tt0133093", 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "\xff\xd8300" {
+		t.Errorf("got poster %q", data)
+	}
+
+	for _, tt := range []struct {
+		key, id string
+		height  int
+		want    error
+	}{
+		{"free", "tt0133093", 0, omdb.ErrInvalidAPIKey},
+		{"patron", "tt0000001", 0, omdb.ErrNotFound},
+		{"patron", "tt0000002", 0, omdb.ErrNotFound},
+		{"patron", "tt0133093", -1, omdb.ErrInvalidRequest},
+	} {
+		client := omdb.NewClient(tt.key, nil, omdb.WithPosterBaseURL(srv.URL))
+		if _, err := client.GetPoster(ctx, tt.id, tt.height); !errors.Is(err, tt.want) {
+			t.Errorf("%s %s %d: got error %v, want %v", tt.key, tt.id, tt.height, err, tt.want)
+		}
+	}
+}
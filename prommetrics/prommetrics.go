@@ -0,0 +1,130 @@
+//Package prommetrics implements omdb.Metrics as a Prometheus collector.
+//
+//	m := prommetrics.New("myapp")
+//	prometheus.MustRegister(m)
+//	client := omdb.NewClient(key, nil, omdb.WithMetrics(m))
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ omdb.TimingsMetrics = (*Metrics)(nil)
+
+//Metrics is an omdb.Metrics and a prometheus.Collector. It exports:
+//
+//	<namespace>_omdb_requests_total{op, class}
+//	<namespace>_omdb_request_duration_seconds{op}
+//	<namespace>_omdb_cache_lookups_total{op, result}
+//	<namespace>_omdb_quota_remaining
+//	<namespace>_omdb_request_phase_seconds{op, phase}
+//
+//where class is the error class of the request (see omdb.ErrorClass), "ok"
+//on success, and result is "hit" or "miss". The phases (dns, connect, tls,
+//ttfb and body_read) are only observed with omdb.WithHTTPTrace.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	cache    *prometheus.CounterVec
+	quota    prometheus.Gauge
+	phases   *prometheus.HistogramVec
+}
+
+//New creates the Metrics of a client, namespace prefixes every metric name
+//and may be empty.
+func New(namespace string) *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "omdb",
+			Name:      "requests_total",
+			Help:      "OMDB requests by kind of request and error class.",
+		}, []string{"op", "class"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "omdb",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of OMDB requests, including cache hits.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"op"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "omdb",
+			Name:      "cache_lookups_total",
+			Help:      "OMDB cache lookups by kind of request and result.",
+		}, []string{"op", "result"}),
+		quota: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "omdb",
+			Name:      "quota_remaining",
+			Help:      "Requests left in the daily quota.",
+		}),
+		phases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "omdb",
+			Name:      "request_phase_seconds",
+			Help:      "Latency of the phases of OMDB HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"op", "phase"}),
+	}
+}
+
+//Request implements omdb.Metrics.
+func (m *Metrics) Request(op string, latency time.Duration, errClass string) {
+	if errClass == "" {
+		errClass = "ok"
+	}
+	m.requests.WithLabelValues(op, errClass).Inc()
+	m.latency.WithLabelValues(op).Observe(latency.Seconds())
+}
+
+//Cache implements omdb.Metrics.
+func (m *Metrics) Cache(op string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cache.WithLabelValues(op, result).Inc()
+}
+
+//QuotaRemaining implements omdb.Metrics.
+func (m *Metrics) QuotaRemaining(remaining int) {
+	m.quota.Set(float64(remaining))
+}
+
+//Timings implements omdb.TimingsMetrics. Phases which didn't happen, like
+//dns on a reused connection, are not observed.
+func (m *Metrics) Timings(op string, t omdb.Timings) {
+	for phase, d := range map[string]time.Duration{
+		"dns":       t.DNS,
+		"connect":   t.Connect,
+		"tls":       t.TLS,
+		"ttfb":      t.TTFB,
+		"body_read": t.BodyRead,
+	} {
+		if d > 0 {
+			m.phases.WithLabelValues(op, phase).Observe(d.Seconds())
+		}
+	}
+}
+
+//Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.latency.Describe(ch)
+	m.cache.Describe(ch)
+	m.quota.Describe(ch)
+	m.phases.Describe(ch)
+}
+
+//Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.latency.Collect(ch)
+	m.cache.Collect(ch)
+	m.quota.Collect(ch)
+	m.phases.Collect(ch)
+}
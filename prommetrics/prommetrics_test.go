@@ -0,0 +1,84 @@
+package prommetrics_test
+
+import (
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/prommetrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+//find returns the metric of family name with labels, or nil.
+func find(families []*dto.MetricFamily, name string, labels map[string]string) *dto.Metric {
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"})
+
+	m := prommetrics.New("test")
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m)
+
+	quota := omdb.NewQuotaTracker(100, nil)
+	client := srv.Client(omdb.WithMetrics(m), omdb.WithCache(omdb.NewLRUCache(10), 0), omdb.WithDailyQuota(quota), omdb.WithHTTPTrace())
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchByImdbID(omdb.QueryData{ImdbID: "tt0133093"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.SearchByImdbID(omdb.QueryData{ImdbID: "tt0000001"}); err == nil {
+		t.Fatal("got no error for an unknown id")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := find(families, "test_omdb_requests_total", map[string]string{"op": omdb.OpByID, "class": "ok"})
+	if ok.GetCounter().GetValue() != 2 {
+		t.Errorf("got %v successful requests, want 2", ok.GetCounter().GetValue())
+	}
+	notFound := find(families, "test_omdb_requests_total", map[string]string{"op": omdb.OpByID, "class": omdb.ErrorClass(omdb.ErrNotFound)})
+	if notFound.GetCounter().GetValue() != 1 {
+		t.Errorf("got %v failed requests, want 1", notFound.GetCounter().GetValue())
+	}
+	if latency := find(families, "test_omdb_request_duration_seconds", map[string]string{"op": omdb.OpByID}); latency.GetHistogram().GetSampleCount() != 3 {
+		t.Errorf("got %d latencies, want 3", latency.GetHistogram().GetSampleCount())
+	}
+	if hits := find(families, "test_omdb_cache_lookups_total", map[string]string{"op": omdb.OpByID, "result": "hit"}); hits.GetCounter().GetValue() != 1 {
+		t.Errorf("got %v cache hits, want 1", hits.GetCounter().GetValue())
+	}
+	if misses := find(families, "test_omdb_cache_lookups_total", map[string]string{"op": omdb.OpByID, "result": "miss"}); misses.GetCounter().GetValue() != 2 {
+		t.Errorf("got %v cache misses, want 2", misses.GetCounter().GetValue())
+	}
+	if remaining := find(families, "test_omdb_quota_remaining", nil); remaining.GetGauge().GetValue() != 98 {
+		t.Errorf("got %v requests remaining, want 98", remaining.GetGauge().GetValue())
+	}
+	if ttfb := find(families, "test_omdb_request_phase_seconds", map[string]string{"op": omdb.OpByID, "phase": "ttfb"}); ttfb.GetHistogram().GetSampleCount() != 2 {
+		t.Errorf("got %d ttfb phases, want 2", ttfb.GetHistogram().GetSampleCount())
+	}
+	if dns := find(families, "test_omdb_request_phase_seconds", map[string]string{"phase": "dns"}); dns != nil {
+		t.Errorf("got a dns phase %v for a request to an IP address", dns)
+	}
+}
@@ -0,0 +1,170 @@
+package omdb
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+//SearchType restricts a title lookup or search to one kind of title,
+//TypeMovie, TypeSeries or TypeEpisode.
+type SearchType string
+
+//Values accepted by QueryData.SearchType. Compare them with the Type of the
+//results as strings, e.g. string(TypeMovie), or with SearchType.Is.
+const (
+	TypeMovie   SearchType = "movie"
+	TypeSeries  SearchType = "series"
+	TypeEpisode SearchType = "episode"
+)
+
+//Is reports whether typ, the Type of a result, is t.
+func (t SearchType) Is(typ string) bool {
+	return string(t) == typ
+}
+
+//Valid reports whether t is TypeMovie, TypeSeries or TypeEpisode.
+func (t SearchType) Valid() bool {
+	return t == TypeMovie || t == TypeSeries || t == TypeEpisode
+}
+
+//Plot selects the length of the plot returned by a lookup.
+type Plot string
+
+//Values accepted by QueryData.Plot.
+const (
+	PlotShort Plot = "short"
+	PlotFull  Plot = "full"
+)
+
+//Valid reports whether p is PlotShort or PlotFull.
+func (p Plot) Valid() bool {
+	return p == PlotShort || p == PlotFull
+}
+
+//Operations accepted by CacheKey, one per kind of API request.
+const (
+	OpByID    = "id"      // SearchByImdbID
+	OpByTitle = "title"   // SearchByTitle
+	OpSearch  = "search"  // SearchByText
+	OpSeason  = "season"  // GetSeason
+	OpEpisode = "episode" // GetEpisode
+)
+
+//queryParams builds the API parameters of op from q. Fields which op doesn't
+//use are ignored, validation is left to the caller.
+func queryParams(op string, q QueryData) url.Values {
+
+	params := url.Values{}
+	add := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			params.Add(key, value)
+		}
+	}
+	addTomatoes := func() {
+		if q.Tomatoes {
+			params.Add("tomatoes", "true")
+		}
+	}
+
+	switch op {
+	case OpByID:
+		add("i", q.ImdbID)
+		add("plot", string(q.Plot))
+		addTomatoes()
+	case OpByTitle:
+		add("t", q.Title)
+		add("type", string(q.SearchType))
+		add("y", q.Year)
+		add("plot", string(q.Plot))
+		addTomatoes()
+	case OpSeason:
+		add("i", q.ImdbID)
+		add("Season", q.Season)
+	case OpEpisode:
+		add("i", q.ImdbID)
+		add("Season", q.Season)
+		add("Episode", q.Episode)
+		add("plot", string(q.Plot))
+	case OpSearch:
+		add("s", q.Title)
+		add("type", string(q.SearchType))
+		add("y", q.Year)
+		add("page", q.Page)
+	}
+
+	return params
+}
+
+//CacheKey returns the key under which a client using the JSON format caches
+//the result of op (one of OpByID, OpByTitle, OpSearch, OpSeason or OpEpisode)
+//for q. The key is built from the sorted request parameters, without the API
+//key, so it is stable across runs and clients and can be used to coordinate
+//an external cache. The ImdbID of q is normalized like the lookups do, e.g.
+//"0133093" and "https://www.imdb.com/title/tt0133093/" give the key of
+//"tt0133093"; the defaults of a client are not applied, see Client.CacheKey.
+func CacheKey(q QueryData, op string) string {
+	if id, err := normalizeID(q.ImdbID); err == nil {
+		q.ImdbID = id
+	}
+	return queryParams(op, q).Encode()
+}
+
+//CacheKey is like the CacheKey function, but the key is the one c caches the
+//result under: the default Plot and SearchType of c, and the plot of opts,
+//are applied to q like the lookups do, and so is the response format of c.
+func (c *Client) CacheKey(q QueryData, op string, opts ...CallOption) string {
+
+	_, o := withCallOptions(context.Background(), opts)
+	q = c.withDefaults(op, q, o)
+	if id, err := normalizeID(q.ImdbID); err == nil {
+		q.ImdbID = id
+	}
+	params := queryParams(op, q)
+	if c.format != FormatJSON {
+		params.Set("r", c.format)
+	}
+	return params.Encode()
+}
+
+//withDefaults returns q with the plot of o, or else the default Plot of c,
+//and the default SearchType of c applied where op uses them.
+func (c *Client) withDefaults(op string, q QueryData, o callOptions) QueryData {
+
+	if o.plot != "" {
+		q.Plot = o.plot
+	}
+	if q.Plot == "" {
+		q.Plot = c.defaultPlot
+	}
+	if q.SearchType == "" && (op == OpByTitle || op == OpSearch) {
+		q.SearchType = c.defaultSearchType
+	}
+	return q
+}
+
+//CacheKeyOp returns the operation (OpByID, OpByTitle, OpSearch, OpSeason or
+//OpEpisode) a key built like CacheKey belongs to, or "" when key is not such
+//a key. Cache implementations can use it to namespace or expire entries by
+//kind of request.
+func CacheKeyOp(key string) string {
+
+	params, err := url.ParseQuery(key)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case params.Get("s") != "":
+		return OpSearch
+	case params.Get("t") != "":
+		return OpByTitle
+	case params.Get("Episode") != "":
+		return OpEpisode
+	case params.Get("Season") != "":
+		return OpSeason
+	case params.Get("i") != "":
+		return OpByID
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package omdb_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestCacheKeyNormalizesImdbID(t *testing.T) {
+
+	want := omdb.CacheKey(omdb.QueryData{ImdbID: "tt0133093"}, omdb.OpByID)
+	for _, id := range []string{"0133093", "133093", "TT0133093", " https://www.imdb.com/title/tt0133093/ "} {
+		if got := omdb.CacheKey(omdb.QueryData{ImdbID: id}, omdb.OpByID); got != want {
+			t.Errorf("%q: got key %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestClientCacheKeyMatchesCache(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+
+	ctx := context.Background()
+	cache := omdb.NewLRUCache(10)
+	client := srv.Client(omdb.WithCache(cache, 0), omdb.WithDefaultPlot(omdb.PlotFull), omdb.WithDefaultSearchType(omdb.TypeMovie))
+
+	byID := omdb.QueryData{ImdbID: "0133093"}
+	if _, err := client.SearchByImdbIDContext(ctx, byID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, client.CacheKey(byID, omdb.OpByID)); err != nil {
+		t.Errorf("lookup by id not cached under the key of the client: %v", err)
+	}
+
+	byTitle := omdb.QueryData{Title: "The Matrix"}
+	if _, err := client.SearchByTitleContext(ctx, byTitle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, client.CacheKey(byTitle, omdb.OpByTitle)); err != nil {
+		t.Errorf("lookup by title not cached under the key of the client: %v", err)
+	}
+	if key := omdb.CacheKey(byTitle, omdb.OpByTitle); key == client.CacheKey(byTitle, omdb.OpByTitle) {
+		t.Errorf("the defaults of the client are not in its key %q", key)
+	}
+}
+
+func TestClientCacheKeyXML(t *testing.T) {
+
+	xml := `<?xml version="1.0" encoding="UTF-8"?><root response="True"><movie title="The Matrix" year="1999" imdbID="tt0133093" type="movie"/></root>`
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(xml))
+	}))
+	defer stub.Close()
+
+	ctx := context.Background()
+	cache := omdb.NewLRUCache(10)
+	client := omdb.NewClient("key", nil, omdb.WithBaseURL(stub.URL), omdb.WithCache(cache, 0), omdb.WithResponseFormat(omdb.FormatXML))
+	q := omdb.QueryData{ImdbID: "tt0133093"}
+	if _, err := client.SearchByImdbIDContext(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+	key := client.CacheKey(q, omdb.OpByID)
+	if _, err := cache.Get(ctx, key); err != nil {
+		t.Fatalf("XML lookup not cached under the key of the client %q: %v", key, err)
+	}
+	if err := cache.Delete(ctx, key); err != nil || cache.Len() != 0 {
+		t.Errorf("the key doesn't delete the entry: %v, %d entries left", err, cache.Len())
+	}
+}
@@ -0,0 +1,184 @@
+package omdb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//QuotaStore persists the number of requests made on a day, so that a
+//QuotaTracker survives restarts. day is a UTC date like "2006-01-02".
+type QuotaStore interface {
+	//Load returns the number of requests made on day, 0 when unknown.
+	Load(ctx context.Context, day string) (int, error)
+	//Save records that used requests were made on day.
+	Save(ctx context.Context, day string, used int) error
+}
+
+//QuotaTracker counts the requests made with an API key each day, UTC, and
+//refuses requests once the daily limit is used up. It is safe for concurrent
+//use.
+type QuotaTracker struct {
+	mu    sync.Mutex
+	limit int
+	store QuotaStore
+	day   string
+	used  int
+}
+
+//NewQuotaTracker returns a QuotaTracker allowing limit requests a day,
+//persisted in store, which may be nil to only count in memory.
+func NewQuotaTracker(limit int, store QuotaStore) *QuotaTracker {
+	return &QuotaTracker{limit: limit, store: store}
+}
+
+//Used returns the number of requests made today.
+func (q *QuotaTracker) Used() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover(context.Background())
+	return q.used
+}
+
+//Remaining returns the number of requests still allowed today.
+func (q *QuotaTracker) Remaining() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover(context.Background())
+	if q.used >= q.limit {
+		return 0
+	}
+	return q.limit - q.used
+}
+
+//take counts a request, it returns ErrQuotaExhausted when none is left.
+func (q *QuotaTracker) take(ctx context.Context) error {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(ctx)
+	if q.used >= q.limit {
+		return ErrQuotaExhausted
+	}
+	q.used++
+
+	if q.store != nil {
+		//the request is allowed anyway, the next Save catches up.
+		_ = q.store.Save(ctx, q.day, q.used)
+	}
+	return nil
+}
+
+//rollover resets the counter when the day changed, loading the persisted
+//count of the new day. q.mu must be held.
+func (q *QuotaTracker) rollover(ctx context.Context) {
+
+	day := time.Now().UTC().Format("2006-01-02")
+	if day == q.day {
+		return
+	}
+
+	q.day, q.used = day, 0
+	if q.store != nil {
+		//an unreadable store starts the day from 0, the next Save fixes it.
+		if used, err := q.store.Load(ctx, day); err == nil {
+			q.used = used
+		}
+	}
+}
+
+//FileQuotaStore is a QuotaStore keeping the count of the current day in a
+//JSON file.
+type FileQuotaStore struct {
+	Path string
+}
+
+type quotaFile struct {
+	Day  string `json:"day"`
+	Used int    `json:"used"`
+}
+
+//Load implements QuotaStore.
+func (f FileQuotaStore) Load(ctx context.Context, day string) (int, error) {
+
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	qf := quotaFile{}
+	if err := json.Unmarshal(data, &qf); err != nil {
+		return 0, err
+	}
+	if qf.Day != day {
+		return 0, nil
+	}
+	return qf.Used, nil
+}
+
+//Save implements QuotaStore.
+func (f FileQuotaStore) Save(ctx context.Context, day string, used int) error {
+
+	data, err := json.Marshal(quotaFile{Day: day, Used: used})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+//CacheQuotaStore is a QuotaStore keeping the counts in a Cache, e.g. the
+//Redis cache shared by several instances. Note that the instances don't
+//coordinate, each one adds its own count to the last one saved.
+type CacheQuotaStore struct {
+	Cache Cache
+	//Key prefixes the day in the cache key, "omdb-quota:" when empty.
+	Key string
+}
+
+//Load implements QuotaStore.
+func (s CacheQuotaStore) Load(ctx context.Context, day string) (int, error) {
+	data, err := s.Cache.Get(ctx, s.key(day))
+	if err == ErrCacheMiss {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(data))
+}
+
+//Save implements QuotaStore.
+func (s CacheQuotaStore) Save(ctx context.Context, day string, used int) error {
+	//kept a little over a day, the tracker only reads the current day.
+	return s.Cache.Set(ctx, s.key(day), []byte(strconv.Itoa(used)), 48*time.Hour)
+}
+
+func (s CacheQuotaStore) key(day string) string {
+	if s.Key == "" {
+		return "omdb-quota:" + day
+	}
+	return s.Key + day
+}
@@ -0,0 +1,133 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+//FreeTierDailyLimit is the number of requests a day allowed to free API keys.
+const FreeTierDailyLimit = 1000
+
+//Limit is a rate of requests per second.
+type Limit float64
+
+//Every returns the Limit of one request per interval.
+func Every(interval time.Duration) Limit {
+	if interval <= 0 {
+		return Limit(math.Inf(1))
+	}
+	return Limit(float64(time.Second) / float64(interval))
+}
+
+//PerDay returns the Limit of n requests a day, e.g. PerDay(FreeTierDailyLimit).
+func PerDay(n int) Limit {
+	return Limit(float64(n) / (24 * 60 * 60))
+}
+
+//Limiter is a token bucket: it holds up to burst tokens, refilled at limit
+//tokens per second, and every request takes one. It is safe for concurrent
+//use.
+type Limiter struct {
+	mu     sync.Mutex
+	limit  Limit
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+//NewLimiter returns a Limiter allowing limit requests per second with bursts
+//of up to burst requests. The bucket starts full.
+func NewLimiter(limit Limit, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		limit:  limit,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+//Wait blocks until a request is allowed or ctx is done. It fails right away
+//when ctx would expire before the request is allowed.
+func (l *Limiter) Wait(ctx context.Context) error {
+	return waitAll(ctx, []*Limiter{l})
+}
+
+//reserve takes a token, returning how long the request has to wait for it.
+func (l *Limiter) reserve(now time.Time) time.Duration {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*float64(l.limit))
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.limit) * float64(time.Second))
+}
+
+//waitAll reserves a token of every limiter at once and blocks until they all
+//allow the request or ctx is done, see Wait. When it fails, the tokens are
+//refunded to every limiter.
+func waitAll(ctx context.Context, limiters []*Limiter) error {
+
+	now := time.Now()
+	var wait time.Duration
+	for _, l := range limiters {
+		if w := l.reserve(now); w > wait {
+			wait = w
+		}
+	}
+	if wait == 0 {
+		return nil
+	}
+	refund := func() {
+		for _, l := range limiters {
+			l.refund()
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		refund()
+		return errors.New("omdb: Rate limit wait would exceed the context deadline")
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		refund()
+		return ctx.Err()
+	}
+}
+
+//allow takes a token when one is available right away, without waiting.
+func (l *Limiter) allow() bool {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*float64(l.limit))
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+//refund returns the token of a request which was given up on.
+func (l *Limiter) refund() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
@@ -0,0 +1,46 @@
+package omdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWaitAllRefunds(t *testing.T) {
+
+	hourly, single := NewLimiter(Every(time.Hour), 2), NewLimiter(Every(time.Hour), 1)
+	limiters := []*Limiter{hourly, single}
+	if err := waitAll(context.Background(), limiters); err != nil {
+		t.Fatal(err)
+	}
+
+	//single has no token left, so the token hourly allows is given back.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := waitAll(ctx, limiters); err == nil {
+		t.Fatal("got no error waiting past the deadline")
+	}
+	if !hourly.allow() {
+		t.Error("got the token of the first limiter taken by a failed wait")
+	}
+	if single.allow() {
+		t.Error("got a token of the second limiter")
+	}
+
+	//a canceled wait gives back every token too.
+	limiters = []*Limiter{NewLimiter(Every(time.Hour), 1), NewLimiter(Every(time.Hour), 1)}
+	if err := waitAll(context.Background(), limiters[1:]); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := waitAll(ctx, limiters); err != context.Canceled {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if !limiters[0].allow() {
+		t.Error("got the token of the first limiter taken by a canceled wait")
+	}
+}
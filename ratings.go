@@ -0,0 +1,118 @@
+package omdb
+
+import (
+	"strconv"
+	"strings"
+)
+
+//RatingSource identifies the source of a Rating.
+type RatingSource int
+
+//Sources OMDB reports ratings from.
+const (
+	SourceUnknown RatingSource = iota
+	SourceIMDb
+	SourceRottenTomatoes
+	SourceMetacritic
+)
+
+//ratingSources maps the Source names used by OMDB to a RatingSource.
+var ratingSources = map[string]RatingSource{
+	"Internet Movie Database": SourceIMDb,
+	"Rotten Tomatoes":         SourceRottenTomatoes,
+	"Metacritic":              SourceMetacritic,
+}
+
+//String returns the name OMDB uses for the source.
+func (s RatingSource) String() string {
+	for name, source := range ratingSources {
+		if source == s {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+//Kind returns the RatingSource of the rating, SourceUnknown for a source
+//OMDB didn't report before.
+func (r Rating) Kind() RatingSource {
+	return ratingSources[r.Source]
+}
+
+//Normalized returns the Value of the rating on a 0-100 scale, whatever the
+//format of its source ("8.5/10", "94%" or "82/100"). 0 is returned when the
+//value can't be parsed.
+func (r Rating) Normalized() float64 {
+	score, _ := r.normalize()
+	return score
+}
+
+//NormalizedRating is a rating from a single source scaled to 0-100.
+type NormalizedRating struct {
+	Source string
+	Score  float64
+}
+
+//normalize converts the Value of a rating to a 0-100 scale. The recognized
+//formats are "8.5/10" (Internet Movie Database), "94%" (Rotten Tomatoes) and
+//"82/100" (Metacritic); any other "value/scale" pair is scaled the same way.
+//ok is false when the value can't be parsed.
+func (r Rating) normalize() (score float64, ok bool) {
+
+	v := strings.TrimSpace(r.Value)
+
+	if strings.HasSuffix(v, "%") {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil || f < 0 || f > 100 {
+			return 0, false
+		}
+		return f, true
+	}
+
+	i := strings.Index(v, "/")
+	if i < 0 {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v[:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	scale, err := strconv.ParseFloat(v[i+1:], 64)
+	if err != nil || scale <= 0 || f < 0 || f > scale {
+		return 0, false
+	}
+
+	return f * 100 / scale, true
+}
+
+//normalizedRatings converts ratings to a 0-100 scale, skipping unparseable
+//values.
+func normalizedRatings(ratings []Rating) []NormalizedRating {
+	var out []NormalizedRating
+	for _, r := range ratings {
+		if score, ok := r.normalize(); ok {
+			out = append(out, NormalizedRating{Source: r.Source, Score: score})
+		}
+	}
+	return out
+}
+
+//NormalizedRatings returns every rating of the movie scaled to 0-100.
+//Recognized values are "8.5/10" (Internet Movie Database), "94%" (Rotten
+//Tomatoes), "82/100" (Metacritic) and any other "value/scale" pair.
+//Ratings which can't be parsed are skipped.
+func (m MovieResult) NormalizedRatings() []NormalizedRating {
+	return normalizedRatings(m.Ratings)
+}
+
+//NormalizedRatings returns every rating of the series scaled to 0-100.
+//Ratings which can't be parsed are skipped.
+func (s SeriesResult) NormalizedRatings() []NormalizedRating {
+	return normalizedRatings(s.Ratings)
+}
+
+//NormalizedRatings returns every rating of the episode scaled to 0-100.
+//Ratings which can't be parsed are skipped.
+func (e EpisodeResult) NormalizedRatings() []NormalizedRating {
+	return normalizedRatings(e.Ratings)
+}
@@ -0,0 +1,91 @@
+package omdb_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestRatingNormalized(t *testing.T) {
+
+	for _, tt := range []struct {
+		value string
+		want  float64
+	}{
+		{"8.7/10", 87},
+		{"88%", 88},
+		{"73/100", 73},
+		{" 4/5 ", 80},
+		{"0/10", 0},
+		{"N/A", 0},
+		{"", 0},
+		{"87", 0},
+		{"eight/10", 0},
+		{"8.7/ten", 0},
+		{"8.7/0", 0},
+		{"11/10", 0},
+		{"-1/10", 0},
+		{"101%", 0},
+		{"%", 0},
+	} {
+		if got := (omdb.Rating{Value: tt.value}).Normalized(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Normalized(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRatingKind(t *testing.T) {
+
+	for source, want := range map[string]omdb.RatingSource{
+		"Internet Movie Database": omdb.SourceIMDb,
+		"Rotten Tomatoes":         omdb.SourceRottenTomatoes,
+		"Metacritic":              omdb.SourceMetacritic,
+		"Letterboxd":              omdb.SourceUnknown,
+	} {
+		kind := (omdb.Rating{Source: source}).Kind()
+		if kind != want {
+			t.Errorf("%s: got kind %v, want %v", source, kind, want)
+		}
+		if kind != omdb.SourceUnknown && kind.String() != source {
+			t.Errorf("%s: got name %q", source, kind.String())
+		}
+	}
+	if name := omdb.SourceUnknown.String(); name != "Unknown" {
+		t.Errorf("got name %q for SourceUnknown", name)
+	}
+}
+
+func TestNormalizedRatings(t *testing.T) {
+
+	ratings := []omdb.Rating{
+		{Source: "Internet Movie Database", Value: "8.7/10"},
+		{Source: "Rotten Tomatoes", Value: "88%"},
+		{Source: "Metacritic", Value: "73/100"},
+		{Source: "Letterboxd", Value: "N/A"},
+		{Source: "Metacritic", Value: "73"},
+	}
+	want := []omdb.NormalizedRating{
+		{Source: "Internet Movie Database", Score: 87},
+		{Source: "Rotten Tomatoes", Score: 88},
+		{Source: "Metacritic", Score: 73},
+	}
+	for name, got := range map[string][]omdb.NormalizedRating{
+		"movie":   omdb.MovieResult{Ratings: ratings}.NormalizedRatings(),
+		"series":  omdb.SeriesResult{Ratings: ratings}.NormalizedRatings(),
+		"episode": omdb.EpisodeResult{Ratings: ratings}.NormalizedRatings(),
+	} {
+		if len(got) != len(want) {
+			t.Errorf("%s: got %+v, want %+v", name, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i].Source != want[i].Source || math.Abs(got[i].Score-want[i].Score) > 1e-9 {
+				t.Errorf("%s: got %+v, want %+v", name, got[i], want[i])
+			}
+		}
+	}
+	if got := (omdb.MovieResult{}).NormalizedRatings(); got != nil {
+		t.Errorf("got %+v without ratings", got)
+	}
+}
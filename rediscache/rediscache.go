@@ -0,0 +1,89 @@
+//Package rediscache implements omdb.Cache on top of Redis, so that several
+//instances of a service can share their OMDB lookups.
+package rediscache
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/redis/go-redis/v9"
+)
+
+//DefaultPrefix is prepended to every key unless WithPrefix is given.
+const DefaultPrefix = "omdb:"
+
+//Cache is an omdb.Cache storing responses in Redis. Keys are namespaced by
+//prefix and kind of request, e.g. "omdb:id:i=tt0133093".
+type Cache struct {
+	client redis.UniversalClient
+	prefix string
+	ttls   map[string]time.Duration
+}
+
+//Option configures a Cache.
+type Option func(*Cache)
+
+//WithPrefix replaces DefaultPrefix as the prefix of every key.
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+//WithTTL stores the responses of op (omdb.OpByID, omdb.OpSearch, ...) for ttl,
+//e.g. to expire searches sooner than lookups. It replaces the ttl given to Set
+//on every write of op, so with omdb.WithStaleWhileRevalidate it should cover
+//the period stale entries are served for.
+func WithTTL(op string, ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttls[op] = ttl
+	}
+}
+
+//New creates a Cache using client.
+func New(client redis.UniversalClient, opts ...Option) *Cache {
+	c := &Cache{
+		client: client,
+		prefix: DefaultPrefix,
+		ttls:   make(map[string]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//Get implements omdb.Cache.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err == redis.Nil {
+		return nil, omdb.ErrCacheMiss
+	}
+	return data, err
+}
+
+//Set implements omdb.Cache.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if d, ok := c.ttls[omdb.CacheKeyOp(key)]; ok {
+		ttl = d
+	}
+	//a negative ttl is KEEPTTL to go-redis, it is expired already here.
+	if ttl < 0 {
+		return c.client.Del(ctx, c.key(key)).Err()
+	}
+	return c.client.Set(ctx, c.key(key), value, ttl).Err()
+}
+
+//Delete implements omdb.Cache.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.key(key)).Err()
+}
+
+func (c *Cache) key(key string) string {
+	op := omdb.CacheKeyOp(key)
+	if op == "" {
+		op = "other"
+	}
+	return c.prefix + op + ":" + key
+}
@@ -0,0 +1,72 @@
+package rediscache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/rediscache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCache(t *testing.T) {
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	cache := rediscache.New(client, rediscache.WithTTL(omdb.OpSearch, time.Minute))
+	byID := omdb.CacheKey(omdb.QueryData{ImdbID: "tt0133093"}, omdb.OpByID)
+	search := omdb.CacheKey(omdb.QueryData{Title: "matrix"}, omdb.OpSearch)
+
+	if _, err := cache.Get(ctx, byID); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Fatalf("got error %v, want ErrCacheMiss", err)
+	}
+	if err := cache.Set(ctx, byID, []byte("movie"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := cache.Get(ctx, byID); err != nil || string(data) != "movie" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if ttl := mr.TTL("omdb:id:" + byID); ttl != time.Hour {
+		t.Errorf("got ttl %v, want 1h", ttl)
+	}
+
+	//the ttl of WithTTL replaces the one given to Set.
+	for _, ttl := range []time.Duration{2 * time.Hour, 0} {
+		if err := cache.Set(ctx, search, []byte("results"), ttl); err != nil {
+			t.Fatal(err)
+		}
+		if got := mr.TTL("omdb:search:" + search); got != time.Minute {
+			t.Errorf("Set with %v: got ttl %v, want the ttl of WithTTL", ttl, got)
+		}
+	}
+
+	//a ttl of 0 never expires, a negative one is expired already.
+	if err := cache.Set(ctx, byID, []byte("movie"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL("omdb:id:" + byID); ttl != 0 || !mr.Exists("omdb:id:"+byID) {
+		t.Errorf("got ttl %v, want none", ttl)
+	}
+	if err := cache.Set(ctx, byID, []byte("movie"), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, byID); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v for a negative ttl, want ErrCacheMiss", err)
+	}
+	if err := cache.Set(ctx, byID, []byte("movie"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Delete(ctx, byID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, byID); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v after Delete, want ErrCacheMiss", err)
+	}
+}
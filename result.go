@@ -0,0 +1,76 @@
+package omdb
+
+import "net/url"
+
+//Result is implemented by MovieResult, SeriesResult and EpisodeResult, so the
+//values returned by SearchByImdbID and SearchByTitle can be handled without a
+//type switch:
+//
+//	res, err := client.SearchByImdbID(q)
+//	if r, ok := res.(omdb.Result); ok {
+//		fmt.Println(r.TitleName(), r.YearOf())
+//	}
+type Result interface {
+	TitleName() string
+	YearOf() string
+	IMDBID() string
+	PosterURL() (*url.URL, error)
+	RatingsList() []Rating
+}
+
+var (
+	_ Result = MovieResult{}
+	_ Result = SeriesResult{}
+	_ Result = EpisodeResult{}
+)
+
+//TitleName returns the Title of the movie.
+func (m MovieResult) TitleName() string { return m.Title }
+
+//YearOf returns the Year of the movie.
+func (m MovieResult) YearOf() string { return m.Year }
+
+//IMDBID returns the ImdbID of the movie.
+func (m MovieResult) IMDBID() string { return m.ImdbID }
+
+//RatingsList returns the Ratings of the movie.
+func (m MovieResult) RatingsList() []Rating { return m.Ratings }
+
+//TitleName returns the Title of the series.
+func (s SeriesResult) TitleName() string { return s.Title }
+
+//YearOf returns the Year of the series, a range like "2008–2013".
+func (s SeriesResult) YearOf() string { return s.Year }
+
+//IMDBID returns the ImdbID of the series.
+func (s SeriesResult) IMDBID() string { return s.ImdbID }
+
+//RatingsList returns the Ratings of the series.
+func (s SeriesResult) RatingsList() []Rating { return s.Ratings }
+
+//TitleName returns the Title of the episode.
+func (e EpisodeResult) TitleName() string { return e.Title }
+
+//YearOf returns the Year of the episode.
+func (e EpisodeResult) YearOf() string { return e.Year }
+
+//IMDBID returns the ImdbID of the episode.
+func (e EpisodeResult) IMDBID() string { return e.ImdbID }
+
+//RatingsList returns the Ratings of the episode.
+func (e EpisodeResult) RatingsList() []Rating { return e.Ratings }
+
+//isNilResult reports whether r holds no result, its methods can't be called.
+func isNilResult(r Result) bool {
+	switch r := r.(type) {
+	case nil:
+		return true
+	case *MovieResult:
+		return r == nil
+	case *SeriesResult:
+		return r == nil
+	case *EpisodeResult:
+		return r == nil
+	}
+	return false
+}
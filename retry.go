@@ -0,0 +1,82 @@
+package omdb
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+//RetryPolicy configures the retries of requests failing with a network
+//error, a 429 or a 5xx response. Only GET requests are sent to OMDB, so
+//retrying is always safe.
+type RetryPolicy struct {
+	//MaxAttempts is the number of retries after the first attempt, 0
+	//disables retrying.
+	MaxAttempts int
+
+	//Base is the wait before the first retry, it is doubled for each
+	//following one up to Max, when Max is not 0.
+	Base time.Duration
+	Max  time.Duration
+
+	//Jitter randomizes each wait by up to this fraction of it, between 0 and
+	//1, so that clients failing together don't retry together.
+	Jitter float64
+
+	//Budget limits the retries to this fraction of the requests sent, e.g.
+	//0.1 allows one retry per ten requests on average, so that retries don't
+	//pile up on an overloaded API. 0 means no budget.
+	Budget float64
+}
+
+//maxBackoff is the longest wait backoff doubles up to before jitter.
+const maxBackoff = time.Duration(math.MaxInt64 / 2)
+
+//backoff returns the wait before retry number n, starting from 0.
+func (p RetryPolicy) backoff(n int) time.Duration {
+
+	//without Max the doubling stops before d, and d plus its jitter,
+	//overflow.
+	d := p.Base
+	for i := 0; i < n && (p.Max == 0 || d < p.Max) && d <= maxBackoff/2; i++ {
+		d *= 2
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+//maxBudget caps the retries saved up by a quiet client.
+const maxBudget = 10
+
+//retryBudget holds the retries a client may still make under the Budget of
+//its RetryPolicy. Every request adds Budget, every retry takes 1.
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+}
+
+func (b *retryBudget) deposit(ratio float64) {
+	b.mu.Lock()
+	b.tokens += ratio
+	if b.tokens > maxBudget {
+		b.tokens = maxBudget
+	}
+	b.mu.Unlock()
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
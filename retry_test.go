@@ -0,0 +1,80 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+
+	p := RetryPolicy{Base: 100 * time.Millisecond, Max: time.Second}
+	for n, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		if got := p.backoff(n); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+	if got := p.backoff(1000); got != time.Second {
+		t.Errorf("backoff(1000) = %v, want Max", got)
+	}
+}
+
+func TestBackoffDoesNotOverflow(t *testing.T) {
+
+	for _, p := range []RetryPolicy{
+		{Base: time.Second},
+		{Base: time.Second, Jitter: 1},
+		{Base: maxBackoff, Jitter: 1},
+	} {
+		prev := time.Duration(0)
+		for n := 0; n < 200; n++ {
+			d := p.backoff(n)
+			if d < 0 {
+				t.Fatalf("%+v: backoff(%d) = %v overflowed", p, n, d)
+			}
+			if p.Jitter == 0 && d < prev {
+				t.Fatalf("%+v: backoff(%d) = %v is less than backoff(%d) = %v", p, n, d, n-1, prev)
+			}
+			prev = d
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Title":"The Matrix","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithRetry(2, time.Millisecond))
+	if _, err := client.SearchByImdbID(QueryData{ImdbID: "tt0133093"}); err != nil {
+		t.Fatalf("got error %v after 2 retries", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+
+	//a client error isn't retried.
+	atomic.StoreInt32(&requests, 0)
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer bad.Close()
+	client = NewClient("key", nil, WithBaseURL(bad.URL), WithRetry(2, time.Millisecond))
+	if _, err := client.SearchByImdbIDContext(context.Background(), QueryData{ImdbID: "tt0133093"}); err == nil {
+		t.Error("got no error for a 400 response")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("got %d requests for a 400 response, want 1", n)
+	}
+}
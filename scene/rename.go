@@ -0,0 +1,149 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//Rename is a proposed move of a file to its canonical path.
+type Rename struct {
+	From string
+	To   string
+}
+
+func (r Rename) String() string {
+	return r.From + " -> " + r.To
+}
+
+//CanonicalPath returns the path, relative to a library root, a Matched or
+//Ambiguous entry is named by in the layout media servers expect:
+//
+//	Title (Year)/Title (Year).ext
+//	Series/Season 01/Series - S01E02 - Episode Title.ext
+//
+//Characters which aren't allowed in file names are removed. ok is false when
+//the entry has no result to name it by.
+func CanonicalPath(e Entry) (path string, ok bool) {
+
+	if e.Status != Matched && e.Status != Ambiguous {
+		return "", false
+	}
+	ext := filepath.Ext(e.Path)
+
+	switch r := e.Result.(type) {
+	case omdb.MovieResult:
+		name := sanitize(r.Title)
+		if year := firstYear(r.Year); year != "" {
+			name += " (" + year + ")"
+		}
+		if name == "" {
+			return "", false
+		}
+		return filepath.Join(name, name+ext), true
+	case omdb.EpisodeResult:
+		if e.Series == nil || e.Release.Season < 1 || e.Release.Episode < 1 {
+			return "", false
+		}
+		series := sanitize(e.Series.Title)
+		if series == "" {
+			return "", false
+		}
+		name := fmt.Sprintf("%s - S%02dE%02d", series, e.Release.Season, e.Release.Episode)
+		if title := sanitize(r.Title); title != "" {
+			name += " - " + title
+		}
+		return filepath.Join(series, fmt.Sprintf("Season %02d", e.Release.Season), name+ext), true
+	}
+	return "", false
+}
+
+//Plan returns the renames moving the entries which have a CanonicalPath under
+//root. Entries already at their canonical path are left out, so are entries
+//whose path another entry claimed first.
+func Plan(entries []Entry, root string) []Rename {
+
+	var renames []Rename
+	claimed := make(map[string]bool)
+	for _, e := range entries {
+		rel, ok := CanonicalPath(e)
+		if !ok {
+			continue
+		}
+		to := filepath.Join(root, rel)
+		if filepath.Clean(e.Path) == to || claimed[to] {
+			continue
+		}
+		claimed[to] = true
+		renames = append(renames, Rename{From: e.Path, To: to})
+	}
+	return renames
+}
+
+//Apply moves the files of renames, creating the directories they need, and
+//writes each rename to w, when not nil, as "From -> To". With dryRun nothing
+//is moved, only written. An existing file is never overwritten; Apply stops
+//at the first error. Files are moved with os.Rename, so root has to be on the
+//file system of the files.
+func Apply(renames []Rename, dryRun bool, w io.Writer) error {
+
+	for _, r := range renames {
+		if w != nil {
+			if _, err := fmt.Fprintln(w, r); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			continue
+		}
+
+		if _, err := os.Lstat(r.To); err == nil {
+			return errors.New("scene: Not renaming " + r.From + ", " + r.To + " exists")
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(r.To), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(r.From, r.To); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//sanitize removes the characters Windows, macOS or Linux don't allow in file
+//names, and the dots and spaces Windows doesn't allow at their end.
+func sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 32, strings.ContainsRune(`<>:"/\|?*`, r):
+			return -1
+		}
+		return r
+	}, name)
+	return strings.TrimRight(strings.TrimSpace(name), ". ")
+}
+
+//firstYear returns the first year of a Year field like "1999" or
+//"2008–2013".
+func firstYear(year string) string {
+	if len(year) >= 4 && digitsOnly(year[:4]) {
+		return year[:4]
+	}
+	return ""
+}
+
+func digitsOnly(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
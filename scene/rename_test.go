@@ -0,0 +1,109 @@
+package scene_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/scene"
+)
+
+var breakingBad = &omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747"}
+
+func TestCanonicalPath(t *testing.T) {
+
+	for _, tc := range []struct {
+		name string
+		e    scene.Entry
+		want string
+	}{
+		{"movie", scene.Entry{Path: "The.Matrix.1999.mkv", Status: scene.Matched, Result: omdb.MovieResult{Title: "The Matrix", Year: "1999"}},
+			filepath.Join("The Matrix (1999)", "The Matrix (1999).mkv")},
+		{"sanitized", scene.Entry{Path: "x.mp4", Status: scene.Ambiguous, Result: omdb.MovieResult{Title: "Mission: Impossible...", Year: "N/A"}},
+			filepath.Join("Mission Impossible", "Mission Impossible.mp4")},
+		{"episode", scene.Entry{Path: "bb.s01e02.mkv", Status: scene.Matched, Result: omdb.EpisodeResult{Title: "Cat's in the Bag..."}, Series: breakingBad, Release: scene.Release{Season: 1, Episode: 2}},
+			filepath.Join("Breaking Bad", "Season 01", "Breaking Bad - S01E02 - Cat's in the Bag.mkv")},
+		{"episode without title", scene.Entry{Path: "bb.s01e02.mkv", Status: scene.Matched, Result: omdb.EpisodeResult{Title: "?"}, Series: breakingBad, Release: scene.Release{Season: 1, Episode: 2}},
+			filepath.Join("Breaking Bad", "Season 01", "Breaking Bad - S01E02.mkv")},
+		{"episode without series", scene.Entry{Path: "bb.s01e02.mkv", Status: scene.Matched, Result: omdb.EpisodeResult{Title: "Pilot"}, Release: scene.Release{Season: 1, Episode: 2}}, ""},
+		{"series", scene.Entry{Path: "bb.s01.mkv", Status: scene.Matched, Result: *breakingBad}, ""},
+		{"missed", scene.Entry{Path: "x.mkv", Status: scene.Missed, Result: omdb.MovieResult{Title: "The Matrix"}}, ""},
+	} {
+		got, ok := scene.CanonicalPath(tc.e)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s: got %q, %v, want %q", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+
+	root := t.TempDir()
+	touch(t, root,
+		"The.Matrix.1999.1080p.mkv",
+		"The.Matrix.1999.720p.mkv",
+		"Dune (2021)/Dune (2021).mkv",
+		"Breaking.Bad.S01E02.mkv",
+	)
+	matrix := omdb.MovieResult{Title: "The Matrix", Year: "1999"}
+	entries := []scene.Entry{
+		{Path: filepath.Join(root, "The.Matrix.1999.1080p.mkv"), Status: scene.Matched, Result: matrix},
+		//a second copy of a title claims the same path.
+		{Path: filepath.Join(root, "The.Matrix.1999.720p.mkv"), Status: scene.Matched, Result: matrix},
+		{Path: filepath.Join(root, "Dune (2021)", "Dune (2021).mkv"), Status: scene.Matched, Result: omdb.MovieResult{Title: "Dune", Year: "2021"}},
+		{Path: filepath.Join(root, "Breaking.Bad.S01E02.mkv"), Status: scene.Matched, Result: omdb.EpisodeResult{Title: "Cat's in the Bag..."}, Series: breakingBad, Release: scene.Release{Season: 1, Episode: 2}},
+		{Path: filepath.Join(root, "Unknown.mkv"), Status: scene.Missed},
+	}
+
+	renames := scene.Plan(entries, root)
+	matrixPath := filepath.Join(root, "The Matrix (1999)", "The Matrix (1999).mkv")
+	episodePath := filepath.Join(root, "Breaking Bad", "Season 01", "Breaking Bad - S01E02 - Cat's in the Bag.mkv")
+	want := []scene.Rename{
+		{From: entries[0].Path, To: matrixPath},
+		{From: entries[3].Path, To: episodePath},
+	}
+	if len(renames) != len(want) || renames[0] != want[0] || renames[1] != want[1] {
+		t.Fatalf("got renames %v, want %v", renames, want)
+	}
+
+	//a dry run only writes the renames.
+	var buf bytes.Buffer
+	if err := scene.Apply(renames, true, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want[0].String()+"\n"+want[1].String()+"\n" {
+		t.Errorf("got %q", buf.String())
+	}
+	if _, err := os.Stat(entries[0].Path); err != nil {
+		t.Errorf("got %v, want the file left after a dry run", err)
+	}
+
+	if err := scene.Apply(renames, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range renames {
+		if _, err := os.Stat(r.From); !os.IsNotExist(err) {
+			t.Errorf("got %v for %s, want it moved", err, r.From)
+		}
+		if _, err := os.Stat(r.To); err != nil {
+			t.Errorf("%s: %v", r.To, err)
+		}
+	}
+
+	//an existing file is never overwritten, Apply stops there.
+	collision := []scene.Rename{
+		{From: entries[1].Path, To: matrixPath},
+		{From: filepath.Join(root, "Dune (2021)", "Dune (2021).mkv"), To: filepath.Join(root, "Dune.mkv")},
+	}
+	if err := scene.Apply(collision, false, nil); err == nil {
+		t.Error("got no error renaming to an existing file")
+	}
+	if _, err := os.Stat(entries[1].Path); err != nil {
+		t.Errorf("got %v, want the colliding file left", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "Dune.mkv")); !os.IsNotExist(err) {
+		t.Errorf("got %v, want the renames after the error left", err)
+	}
+}
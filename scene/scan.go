@@ -0,0 +1,134 @@
+package scene
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//Status tells how the lookup of a scanned file went.
+type Status int
+
+const (
+	//Matched files were found on OMDB.
+	Matched Status = iota
+	//Missed files were not found on OMDB.
+	Missed
+	//Ambiguous files were found, but their name without year fits several
+	//titles, listed in Entry.Candidates; Entry.Result is the one OMDB
+	//picked.
+	Ambiguous
+	//Failed files could not be looked up, see Entry.Err.
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Matched:
+		return "matched"
+	case Missed:
+		return "missed"
+	case Ambiguous:
+		return "ambiguous"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+//Entry is the report of a scanned file.
+type Entry struct {
+	//Path is the path of the file, starting with the scanned root.
+	Path    string
+	Release Release
+	Status  Status
+	//Result is the omdb.MovieResult, omdb.SeriesResult or
+	//omdb.EpisodeResult of a Matched or Ambiguous file.
+	Result interface{}
+	//Series is the series of an episode.
+	Series     *omdb.SeriesResult
+	Candidates []omdb.SearchResult
+	Err        error
+}
+
+//Scan walks the directory tree at root and looks up every video file, see
+//VideoExtensions, like Lookup, calling report with the Entry of each file in
+//walking order. Hidden directories and samples are skipped. The lookups are
+//sent one after another with c, so the cache and rate limiters of an
+//omdb.Client apply. Scan stops at the first error walking the tree, or when
+//ctx is done.
+func Scan(ctx context.Context, c omdb.API, root string, report func(Entry), opts ...omdb.CallOption) error {
+
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isVideo(name) || strings.Contains(strings.ToLower(name), "sample") {
+			return nil
+		}
+
+		report(lookupFile(ctx, c, path, opts))
+		return nil
+	})
+}
+
+func lookupFile(ctx context.Context, c omdb.API, path string, opts []omdb.CallOption) Entry {
+
+	res, series, rel, err := lookup(ctx, c, path, opts)
+	entry := Entry{Path: path, Release: rel, Result: res, Series: series, Err: err}
+	switch {
+	case errors.Is(err, omdb.ErrNotFound):
+		entry.Status, entry.Err = Missed, nil
+		return entry
+	case err != nil:
+		entry.Status = Failed
+		return entry
+	}
+
+	//a name without year may fit remakes or series sharing the title.
+	if rel.Year == 0 && !rel.IsEpisode() {
+		q := omdb.QueryData{Title: rel.Title, SearchType: omdb.TypeMovie}
+		if rel.IsSeries() {
+			q.SearchType = omdb.TypeSeries
+		}
+		if found, err := c.SearchByTextContext(ctx, q, opts...); err == nil {
+			for _, r := range found.Search {
+				if strings.EqualFold(r.Title, rel.Title) {
+					entry.Candidates = append(entry.Candidates, r)
+				}
+			}
+		}
+		if len(entry.Candidates) > 1 {
+			entry.Status = Ambiguous
+			return entry
+		}
+		entry.Candidates = nil
+	}
+
+	entry.Status = Matched
+	return entry
+}
+
+func isVideo(name string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	for _, e := range VideoExtensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package scene_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/scene"
+)
+
+//touch creates empty files at paths under root, with their directories.
+func touch(t *testing.T, root string, paths ...string) {
+
+	t.Helper()
+	for _, p := range paths {
+		p = filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+	srv.AddMovie(omdb.MovieResult{Title: "Solaris", Year: "1972", ImdbID: "tt0069293"})
+	srv.AddMovie(omdb.MovieResult{Title: "Solaris", Year: "2002", ImdbID: "tt0307479"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747"})
+	srv.AddEpisode(omdb.EpisodeResult{Title: "Cat's in the Bag...", ImdbID: "tt1054724", SeriesID: "tt0903747"})
+	srv.AddSeason("tt0903747", omdb.SeasonResult{Title: "Breaking Bad", Season: "1", Episodes: []omdb.SeasonEpisode{
+		{Title: "Cat's in the Bag...", Episode: "2", ImdbID: "tt1054724"},
+	}})
+
+	root := t.TempDir()
+	touch(t, root,
+		".hidden/Dune.2021.mkv",
+		"1080p.mkv",
+		"Breaking Bad/Season 01/Breaking.Bad.S01E02.720p.HDTV.mkv",
+		"Solaris.mkv",
+		"The.Matrix.1999.1080p.mkv",
+		"The.Matrix.1999.Sample.mkv",
+		"Unknown.Movie.2020.mp4",
+		"notes.txt",
+	)
+
+	ctx := context.Background()
+	var entries []scene.Entry
+	if err := scene.Scan(ctx, srv.Client(), root, func(e scene.Entry) { entries = append(entries, e) }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		path   string
+		status scene.Status
+		imdbID string
+	}{
+		{"1080p.mkv", scene.Failed, ""},
+		{"Breaking Bad/Season 01/Breaking.Bad.S01E02.720p.HDTV.mkv", scene.Matched, "tt1054724"},
+		{"Solaris.mkv", scene.Ambiguous, "tt0069293"},
+		{"The.Matrix.1999.1080p.mkv", scene.Matched, "tt0133093"},
+		{"Unknown.Movie.2020.mp4", scene.Missed, ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Path != filepath.Join(root, filepath.FromSlash(w.path)) || e.Status != w.status {
+			t.Errorf("entry %d: got %s %v, want %s %v", i, e.Path, e.Status, w.path, w.status)
+			continue
+		}
+		imdbID := ""
+		switch r := e.Result.(type) {
+		case omdb.MovieResult:
+			imdbID = r.ImdbID
+		case omdb.EpisodeResult:
+			imdbID = r.ImdbID
+		}
+		if imdbID != w.imdbID {
+			t.Errorf("%s: got result %q, want %q", w.path, imdbID, w.imdbID)
+		}
+	}
+
+	if e := entries[0]; !errors.Is(e.Err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for a name without title, want ErrInvalidRequest", e.Err)
+	}
+	if e := entries[1]; e.Series == nil || e.Series.ImdbID != "tt0903747" || e.Release.Season != 1 || e.Release.Episode != 2 {
+		t.Errorf("got series %+v, release %+v", e.Series, e.Release)
+	}
+	if e := entries[2]; len(e.Candidates) != 2 {
+		t.Errorf("got candidates %+v, want both Solaris", e.Candidates)
+	}
+	if e := entries[4]; e.Err != nil {
+		t.Errorf("got error %v for a missed file", e.Err)
+	}
+
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := scene.Scan(done, srv.Client(), root, func(scene.Entry) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if err := scene.Scan(ctx, srv.Client(), filepath.Join(root, "missing"), func(scene.Entry) {}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v for a missing root, want ErrNotExist", err)
+	}
+}
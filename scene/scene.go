@@ -0,0 +1,208 @@
+//Package scene parses scene release names, the naming of most downloaded
+//video files, and looks the titles they name up on OMDB:
+//
+//	rel := scene.Parse("The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv")
+//	//rel.Title == "The Matrix", rel.Year == 1999
+//
+//	res, rel, err := scene.Lookup(ctx, client, "Breaking.Bad.S01E02.720p.HDTV.x264-CTU.mkv")
+//	//res is the omdb.EpisodeResult of the second episode of the first season
+package scene
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ahin/omdb"
+)
+
+//Release is what Parse learns from a release name. Fields not found in the
+//name are left blank.
+type Release struct {
+	Title   string
+	Year    int
+	Season  int
+	Episode int
+
+	//Resolution is e.g. "1080p", Source e.g. "BluRay" or "WEB-DL", Codec
+	//e.g. "x264", as written in the name.
+	Resolution string
+	Source     string
+	Codec      string
+	//Group is the release group, following the last "-" of the name.
+	Group string
+	//Ext is the file extension, without the dot.
+	Ext string
+}
+
+//IsEpisode reports whether the release is an episode of a series.
+func (r Release) IsEpisode() bool {
+	return r.Season > 0 && r.Episode > 0
+}
+
+//IsSeries reports whether the release names a series: an episode, or a
+//whole season.
+func (r Release) IsSeries() bool {
+	return r.Season > 0
+}
+
+//VideoExtensions are the extensions, without the dot, of the files Parse
+//removes from a name along with the ones of subtitles.
+var VideoExtensions = []string{"mkv", "mp4", "avi", "m4v", "mov", "wmv", "mpg", "mpeg", "ts", "m2ts", "webm", "flv", "ogm", "divx"}
+
+var subtitleExtensions = []string{"srt", "sub", "idx", "ass", "ssa", "vtt", "nfo"}
+
+var (
+	episodeRe     = regexp.MustCompile(`(?i)^s(\d{1,2})[ ._-]?e(\d{1,3})(?:-?e\d{1,3})*$`)
+	crossRe       = regexp.MustCompile(`^(\d{1,2})x(\d{2,3})$`)
+	seasonRe      = regexp.MustCompile(`(?i)^s(\d{1,2})$`)
+	yearRe        = regexp.MustCompile(`^[(\[]?((?:19|20)\d\d)[)\]]?$`)
+	resolutionRe  = regexp.MustCompile(`(?i)^(?:\d{3,4}[pi]|4k|uhd)$`)
+	sourceRe      = regexp.MustCompile(`(?i)^(?:blu-?ray|bdrip|brrip|bdremux|remux|web-?dl|webrip|web|hdtv|pdtv|dvdrip|dvdscr|dvd|hdrip|hdcam|cam|ts|telesync|amzn|nf|dsnp|hmax|atvp)$`)
+	codecRe       = regexp.MustCompile(`(?i)^(?:[xh]26[45]|hevc|avc|xvid|divx|av1|vp9)$`)
+	dottedCodecRe = regexp.MustCompile(`(?i)\b([xh])\.(26[45])\b`)
+	//otherRe matches tags which end the title without being parsed.
+	otherRe = regexp.MustCompile(`(?i)^(?:proper|repack|internal|limited|extended|unrated|remastered|dubbed|subbed|multi|hdr|hdr10|dv|10bit|aac|ac3|dts|ddp?5\.?1|atmos|complete)$`)
+)
+
+//Parse reads the title, year, season, episode and release tags from name, a
+//file name or path. The title is what comes before the first tag; a year
+//directly before the other tags is the year of release, so a title like
+//"2001 A Space Odyssey 1968" keeps its leading number.
+func Parse(name string) Release {
+
+	rel := Release{}
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), ".")); isKnownExt(ext) {
+		rel.Ext = ext
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+
+	//the group follows the last dash, unless it is part of a tag like
+	//WEB-DL or of the title.
+	if i := strings.LastIndexByte(name, '-'); i > 0 && i < len(name)-1 && !strings.ContainsAny(name[i+1:], " ._[]()") {
+		if !sourceRe.MatchString("web-" + name[i+1:]) {
+			rel.Group = name[i+1:]
+			name = name[:i]
+		}
+	}
+
+	//keep the dot of codecs like H.264 from splitting them.
+	name = dottedCodecRe.ReplaceAllString(name, "$1$2")
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '.' || r == '_' || r == ' '
+	})
+
+	end := len(tokens)
+	for i, tok := range tokens {
+		switch {
+		case episodeRe.MatchString(tok):
+			m := episodeRe.FindStringSubmatch(tok)
+			rel.Season, _ = strconv.Atoi(m[1])
+			rel.Episode, _ = strconv.Atoi(m[2])
+		case crossRe.MatchString(tok) && i > 0:
+			m := crossRe.FindStringSubmatch(tok)
+			rel.Season, _ = strconv.Atoi(m[1])
+			rel.Episode, _ = strconv.Atoi(m[2])
+		case seasonRe.MatchString(tok) && i > 0:
+			rel.Season, _ = strconv.Atoi(seasonRe.FindStringSubmatch(tok)[1])
+		case resolutionRe.MatchString(tok):
+			if rel.Resolution == "" {
+				rel.Resolution = tok
+			}
+		case sourceRe.MatchString(tok) && i > 0:
+			if rel.Source == "" {
+				rel.Source = tok
+			}
+		case codecRe.MatchString(tok):
+			rel.Codec = tok
+		case otherRe.MatchString(tok) && i > 0:
+		default:
+			continue
+		}
+		if i < end {
+			end = i
+		}
+	}
+
+	//a year right before the first tag, or ending the name, is the year of
+	//release as long as a title is left before it.
+	if end > 1 && yearRe.MatchString(tokens[end-1]) {
+		rel.Year, _ = strconv.Atoi(yearRe.FindStringSubmatch(tokens[end-1])[1])
+		end--
+	}
+
+	rel.Title = strings.TrimSpace(strings.Join(tokens[:end], " "))
+	return rel
+}
+
+func isKnownExt(ext string) bool {
+	for _, list := range [][]string{VideoExtensions, subtitleExtensions} {
+		for _, e := range list {
+			if e == ext {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//Lookup parses name and looks the title up with c: an episode by the title
+//of its series, then its season and episode, a season pack as a series, and
+//anything else as a movie, each restricted to the year of the release when
+//it has one. The result is an omdb.MovieResult, omdb.SeriesResult or
+//omdb.EpisodeResult, returned with the parsed Release.
+func Lookup(ctx context.Context, c omdb.API, name string, opts ...omdb.CallOption) (interface{}, Release, error) {
+	res, _, rel, err := lookup(ctx, c, name, opts)
+	return res, rel, err
+}
+
+//lookup is Lookup, also returning the series of an episode.
+func lookup(ctx context.Context, c omdb.API, name string, opts []omdb.CallOption) (interface{}, *omdb.SeriesResult, Release, error) {
+
+	rel := Parse(name)
+	if rel.Title == "" {
+		return nil, nil, rel, &lookupError{name: name}
+	}
+
+	q := omdb.QueryData{Title: rel.Title}
+	if rel.Year > 0 {
+		q.Year = strconv.Itoa(rel.Year)
+	}
+
+	if !rel.IsSeries() {
+		movie, err := c.GetMovieByTitle(ctx, q, opts...)
+		if err != nil {
+			return nil, nil, rel, err
+		}
+		return *movie, nil, rel, nil
+	}
+
+	series, err := c.GetSeriesByTitle(ctx, q, opts...)
+	if err != nil {
+		return nil, nil, rel, err
+	}
+	if !rel.IsEpisode() {
+		return *series, nil, rel, nil
+	}
+
+	episode, err := c.GetEpisode(ctx, series.ImdbID, rel.Season, rel.Episode, opts...)
+	if err != nil {
+		return nil, nil, rel, err
+	}
+	return *episode, series, rel, nil
+}
+
+//lookupError is returned by Lookup for a name without title, it matches
+//omdb.ErrInvalidRequest.
+type lookupError struct {
+	name string
+}
+
+func (e *lookupError) Error() string {
+	return "scene: No title found in " + e.name
+}
+
+func (e *lookupError) Unwrap() error { return omdb.ErrInvalidRequest }
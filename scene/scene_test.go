@@ -0,0 +1,73 @@
+package scene_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/scene"
+)
+
+func TestParse(t *testing.T) {
+
+	for _, tc := range []struct {
+		name string
+		want scene.Release
+	}{
+		{"The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv", scene.Release{Title: "The Matrix", Year: 1999, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GROUP", Ext: "mkv"}},
+		{"/media/tv/Breaking.Bad.S01E02.720p.HDTV.x264-CTU.mkv", scene.Release{Title: "Breaking Bad", Season: 1, Episode: 2, Resolution: "720p", Source: "HDTV", Codec: "x264", Group: "CTU", Ext: "mkv"}},
+		{"2001.A.Space.Odyssey.1968.2160p.WEB-DL.H.265.mkv", scene.Release{Title: "2001 A Space Odyssey", Year: 1968, Resolution: "2160p", Source: "WEB-DL", Codec: "H265", Ext: "mkv"}},
+		{"The Office 2x05 HDTV.avi", scene.Release{Title: "The Office", Season: 2, Episode: 5, Source: "HDTV", Ext: "avi"}},
+		{"Severance.S01.COMPLETE.1080p.WEB", scene.Release{Title: "Severance", Season: 1, Resolution: "1080p", Source: "WEB"}},
+		{`C:\Movies\Alien (1979).mp4`, scene.Release{Title: "Alien", Year: 1979, Ext: "mp4"}},
+		{"Spider-Man.No.Way.Home.2021.srt", scene.Release{Title: "Spider-Man No Way Home", Year: 2021, Ext: "srt"}},
+	} {
+		if got := scene.Parse(tc.name); got != tc.want {
+			t.Errorf("%q:\ngot  %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747"})
+	srv.AddEpisode(omdb.EpisodeResult{Title: "Cat's in the Bag...", ImdbID: "tt1054724", SeriesID: "tt0903747"})
+	srv.AddSeason("tt0903747", omdb.SeasonResult{Title: "Breaking Bad", Season: "1", Episodes: []omdb.SeasonEpisode{
+		{Title: "Cat's in the Bag...", Episode: "2", ImdbID: "tt1054724"},
+	}})
+	client := srv.Client()
+	ctx := context.Background()
+
+	res, rel, err := scene.Lookup(ctx, client, "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := res.(omdb.MovieResult); !ok || m.ImdbID != "tt0133093" || rel.Year != 1999 {
+		t.Errorf("got %+v, %+v", res, rel)
+	}
+
+	res, _, err = scene.Lookup(ctx, client, "Breaking.Bad.S01E02.720p.HDTV.x264-CTU.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := res.(omdb.EpisodeResult); !ok || e.ImdbID != "tt1054724" {
+		t.Errorf("got %+v, want the episode", res)
+	}
+
+	res, _, err = scene.Lookup(ctx, client, "Breaking.Bad.S01.1080p.BluRay")
+	if s, ok := res.(omdb.SeriesResult); err != nil || !ok || s.ImdbID != "tt0903747" {
+		t.Errorf("got %+v, %v, want the series", res, err)
+	}
+
+	if _, _, err := scene.Lookup(ctx, client, "1080p.BluRay.x264.mkv"); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for a name without title, want ErrInvalidRequest", err)
+	}
+	if _, _, err := scene.Lookup(ctx, client, "Unknown.Movie.2020.mkv"); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,187 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+const (
+	//pageSize is the number of results OMDB returns per search page.
+	pageSize = 10
+	//maxPage is the highest page number OMDB serves for a text search.
+	maxPage = 100
+)
+
+//SearchLimit performs a text search like SearchByText and keeps requesting
+//the following pages until n results are collected or no results are left.
+//At most n results are returned. Paging starts at q.Page, or the first page
+//when q.Page is blank.
+func (c *Client) SearchLimit(ctx context.Context, q QueryData, n int) ([]SearchResult, error) {
+
+	if n < 1 {
+		return nil, invalidRequest("omdb: Limit should be greater than 0")
+	}
+
+	var results []SearchResult
+	err := c.searchPages(ctx, q, func(page []SearchResult) bool {
+		results = append(results, page...)
+		return len(results) < n
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > n {
+		results = results[:n]
+	}
+	return results, nil
+}
+
+//SearchAll performs a text search like SearchByText and collects the results
+//of every page, starting at q.Page or the first page, into a single slice.
+//Results repeated on several pages are kept once, by ImdbID. At most
+//maxPage*pageSize results are returned, or fewer with WithMaxResults.
+func (c *Client) SearchAll(ctx context.Context, q QueryData, opts ...CallOption) ([]SearchResult, error) {
+
+	ctx, o := withCallOptions(ctx, opts)
+	max := o.max
+	if max < 1 || max > maxPage*pageSize {
+		max = maxPage * pageSize
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+	err := c.searchPages(ctx, q, func(page []SearchResult) bool {
+		for _, r := range page {
+			if seen[r.ImdbID] {
+				continue
+			}
+			seen[r.ImdbID] = true
+			results = append(results, r)
+			if len(results) == max {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+//SearchYears performs a text search like SearchAll for each year from from
+//to to, inclusive, since OMDB only filters searches by a single year, and
+//merges the results in order of year. q.Year is ignored. The searches are
+//sent one after another, each through the rate limiters and cache of the
+//client, so a wide range uses up quota: every year takes at least one
+//request. Years without results are skipped. WithMaxResults caps the merged
+//results.
+func (c *Client) SearchYears(ctx context.Context, q QueryData, from, to int, opts ...CallOption) ([]SearchResult, error) {
+
+	if from < 1888 || to < from {
+		return nil, invalidRequest("omdb: Year range should start after 1887 and end after its start")
+	}
+
+	ctx, o := withCallOptions(ctx, opts)
+	max := o.max
+	if max < 1 {
+		max = -1
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+	for year := from; year <= to && len(results) != max; year++ {
+		q.Year = strconv.Itoa(year)
+		err := c.searchPages(ctx, q, func(page []SearchResult) bool {
+			for _, r := range page {
+				if seen[r.ImdbID] {
+					continue
+				}
+				seen[r.ImdbID] = true
+				results = append(results, r)
+				if len(results) == max {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+//SearchStream performs a text search like SearchByText and sends the results
+//of every page, starting at q.Page, on the returned results channel. The
+//results channel is closed once the last page is sent, ctx is done or a
+//request fails; the error channel then receives the error, if any, and is
+//closed too. The caller has to drain the results channel or cancel ctx.
+func (c *Client) SearchStream(ctx context.Context, q QueryData) (<-chan SearchResult, <-chan error) {
+
+	results := make(chan SearchResult)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		//ctx is only reported when it stopped the stream, not when the
+		//caller cancels it after receiving the last result.
+		canceled := false
+		err := c.searchPages(ctx, q, func(page []SearchResult) bool {
+			for _, r := range page {
+				select {
+				case results <- r:
+				case <-ctx.Done():
+					canceled = true
+					return false
+				}
+			}
+			return true
+		})
+		if err == nil && canceled {
+			err = ctx.Err()
+		}
+		close(results)
+		if err != nil {
+			errc <- err
+		}
+	}()
+
+	return results, errc
+}
+
+//searchPages requests the pages of a text search, starting at q.Page or the
+//first page, and passes the results of each page to yield until it returns
+//false or the last page is reached.
+func (c *Client) searchPages(ctx context.Context, q QueryData, yield func([]SearchResult) bool) error {
+
+	page := 1
+	if q.Page != "" {
+		i, err := strconv.Atoi(q.Page)
+		if err != nil {
+			return invalidRequest("omdb: Page should be either blank or a valid number")
+		}
+		page = i
+	}
+
+	for ; page <= maxPage; page++ {
+		q.Page = strconv.Itoa(page)
+		res, err := c.SearchByTextContext(ctx, q)
+		if err != nil {
+			return err
+		}
+		if !yield(res.Search) {
+			return nil
+		}
+
+		//stop at the last page, OMDB reports any page past it as an error.
+		if len(res.Search) == 0 || !res.HasMore(page) {
+			return nil
+		}
+	}
+	return nil
+}
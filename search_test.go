@@ -0,0 +1,182 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func searchServer(n int) *omdbtest.Server {
+	srv := omdbtest.NewServer()
+	for i := 1; i <= n; i++ {
+		srv.AddMovie(omdb.MovieResult{Title: "Star " + strconv.Itoa(i), Year: "2000", ImdbID: "tt" + strconv.Itoa(1000000+i)})
+	}
+	return srv
+}
+
+//failingClient returns a client of srv whose requests for page fail, as when
+//the daily limit is reached during the paging.
+func failingClient(t *testing.T, srv *omdbtest.Server, page int) *omdb.Client {
+
+	t.Helper()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == strconv.Itoa(page) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"Response":"False","Error":"Request limit reached!"}`))
+			return
+		}
+		srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+	return omdb.NewClient(omdbtest.APIKey, nil, omdb.WithBaseURL(proxy.URL))
+}
+
+func TestSearchStreamCancelAfterLastResult(t *testing.T) {
+
+	srv := searchServer(15)
+	defer srv.Close()
+
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		results, errc := srv.Client().SearchStream(ctx, omdb.QueryData{Title: "star"})
+		n := 0
+		for range results {
+			if n++; n == 15 {
+				cancel()
+			}
+		}
+		cancel()
+		if err := <-errc; err != nil {
+			t.Fatalf("got error %v after receiving every result", err)
+		}
+		if n != 15 {
+			t.Fatalf("got %d results, want 15", n)
+		}
+	}
+}
+
+func TestSearchStreamCanceled(t *testing.T) {
+
+	srv := searchServer(15)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, errc := srv.Client().SearchStream(ctx, omdb.QueryData{Title: "star"})
+	<-results
+	cancel()
+	for range results {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestSearchAll(t *testing.T) {
+
+	srv := searchServer(25)
+	defer srv.Close()
+	ctx := context.Background()
+
+	results, err := srv.Client().SearchAll(ctx, omdb.QueryData{Title: "star"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 25 || results[0].ImdbID != "tt1000001" || results[24].ImdbID != "tt1000025" || srv.Requests() != 3 {
+		t.Errorf("got %d results in %d requests, want 25 in 3", len(results), srv.Requests())
+	}
+
+	//WithMaxResults stops the paging early.
+	results, err = srv.Client().SearchAll(ctx, omdb.QueryData{Title: "star"}, omdb.WithMaxResults(12))
+	if err != nil || len(results) != 12 || results[11].ImdbID != "tt1000012" {
+		t.Errorf("got %d results, %v, want 12", len(results), err)
+	}
+	if srv.Requests() != 5 {
+		t.Errorf("got %d requests for 12 results, want 2", srv.Requests()-3)
+	}
+
+	if _, err := failingClient(t, srv, 2).SearchAll(ctx, omdb.QueryData{Title: "star"}); !errors.Is(err, omdb.ErrDailyLimitExceeded) {
+		t.Errorf("got error %v on page 2, want ErrDailyLimitExceeded", err)
+	}
+}
+
+func TestSearchYears(t *testing.T) {
+
+	srv := searchServer(12)
+	defer srv.Close()
+	for i := 1; i <= 3; i++ {
+		srv.AddMovie(omdb.MovieResult{Title: "Star " + strconv.Itoa(20+i), Year: "2001", ImdbID: "tt" + strconv.Itoa(1000020+i)})
+	}
+	ctx := context.Background()
+
+	results, err := srv.Client().SearchYears(ctx, omdb.QueryData{Title: "star"}, 2000, 2002)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 15 || results[11].Year != "2000" || results[12].Year != "2001" {
+		t.Errorf("got %d results %+v, want 12 of 2000 then 3 of 2001", len(results), results)
+	}
+	//2 pages of 2000, 1 of 2001 and 1 without results for 2002.
+	if srv.Requests() != 4 {
+		t.Errorf("got %d requests, want 4", srv.Requests())
+	}
+
+	results, err = srv.Client().SearchYears(ctx, omdb.QueryData{Title: "star"}, 2000, 2002, omdb.WithMaxResults(11))
+	if err != nil || len(results) != 11 {
+		t.Errorf("got %d results, %v, want 11", len(results), err)
+	}
+	if srv.Requests() != 6 {
+		t.Errorf("got %d requests for 11 results, want 2", srv.Requests()-4)
+	}
+
+	if _, err := failingClient(t, srv, 2).SearchYears(ctx, omdb.QueryData{Title: "star"}, 2000, 2001); !errors.Is(err, omdb.ErrDailyLimitExceeded) {
+		t.Errorf("got error %v on page 2, want ErrDailyLimitExceeded", err)
+	}
+	for _, years := range [][2]int{{1887, 2000}, {2001, 2000}} {
+		if _, err := srv.Client().SearchYears(ctx, omdb.QueryData{Title: "star"}, years[0], years[1]); !errors.Is(err, omdb.ErrInvalidRequest) {
+			t.Errorf("%d-%d: got error %v, want ErrInvalidRequest", years[0], years[1], err)
+		}
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+
+	srv := searchServer(25)
+	defer srv.Close()
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		limit, results, requests int
+	}{
+		{5, 5, 1},
+		{10, 10, 1},
+		{11, 11, 2},
+		{20, 20, 2},
+		{100, 25, 3},
+	} {
+		before := srv.Requests()
+		results, err := srv.Client().SearchLimit(ctx, omdb.QueryData{Title: "star"}, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != tt.results || results[len(results)-1].ImdbID != "tt"+strconv.Itoa(1000000+tt.results) {
+			t.Errorf("limit %d: got %d results, want %d", tt.limit, len(results), tt.results)
+		}
+		if n := srv.Requests() - before; n != tt.requests {
+			t.Errorf("limit %d: got %d requests, want %d", tt.limit, n, tt.requests)
+		}
+	}
+
+	if _, err := srv.Client().SearchLimit(ctx, omdb.QueryData{Title: "star"}, 0); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for a limit of 0, want ErrInvalidRequest", err)
+	}
+	if _, err := failingClient(t, srv, 2).SearchLimit(ctx, omdb.QueryData{Title: "star"}, 15); !errors.Is(err, omdb.ErrDailyLimitExceeded) {
+		t.Errorf("got error %v on page 2, want ErrDailyLimitExceeded", err)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package omdb
+
+import (
+	"context"
+	"iter"
+)
+
+//SearchIter performs a text search like SearchByText and returns an iterator
+//over the results of every page, starting at q.Page or the first page. Pages
+//are requested as the iteration reaches them, breaking out of the loop stops
+//the paging. A failed request is yielded as the last pair, with a zero
+//SearchResult.
+func (c *Client) SearchIter(ctx context.Context, q QueryData) iter.Seq2[SearchResult, error] {
+	return func(yield func(SearchResult, error) bool) {
+
+		err := c.searchPages(ctx, q, func(page []SearchResult) bool {
+			for _, r := range page {
+				if !yield(r, nil) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			yield(SearchResult{}, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//go:build go1.23
+
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestSearchIter(t *testing.T) {
+
+	srv := searchServer(25)
+	defer srv.Close()
+	ctx := context.Background()
+
+	var ids []string
+	for r, err := range srv.Client().SearchIter(ctx, omdb.QueryData{Title: "star"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, r.ImdbID)
+	}
+	if len(ids) != 25 || ids[0] != "tt1000001" || ids[24] != "tt1000025" {
+		t.Errorf("got %d results %v, want the 3 pages", len(ids), ids)
+	}
+	if srv.Requests() != 3 {
+		t.Errorf("got %d requests, want 3", srv.Requests())
+	}
+
+	//breaking out of the loop stops the paging.
+	n := 0
+	for _, err := range srv.Client().SearchIter(ctx, omdb.QueryData{Title: "star"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n++; n == 12 {
+			break
+		}
+	}
+	if srv.Requests() != 5 {
+		t.Errorf("got %d requests for 12 results, want 2", srv.Requests()-3)
+	}
+
+	var got []error
+	n = 0
+	for r, err := range failingClient(t, srv, 2).SearchIter(ctx, omdb.QueryData{Title: "star"}) {
+		if err != nil {
+			got = append(got, err)
+			if r != (omdb.SearchResult{}) {
+				t.Errorf("got result %+v with the error", r)
+			}
+			continue
+		}
+		n++
+	}
+	if n != 10 || len(got) != 1 || !errors.Is(got[0], omdb.ErrDailyLimitExceeded) {
+		t.Errorf("got %d results and errors %v, want the first page and the error of the second", n, got)
+	}
+}
@@ -0,0 +1,161 @@
+package omdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+//GetSeason returns the episode list of a season of the series identified by
+//seriesID. Seasons are numbered from 1.
+func (c *Client) GetSeason(ctx context.Context, seriesID string, season int, opts ...CallOption) (*SeasonResult, error) {
+
+	if seriesID == "" {
+		return nil, invalidRequest("omdb: Series ImdbID is missing")
+	}
+	if season < 1 {
+		return nil, invalidRequest("omdb: Season should be greater than 0")
+	}
+	seriesID, err := NormalizeImdbID(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, _ = withCallOptions(ctx, opts)
+	q := QueryData{ImdbID: seriesID, Season: strconv.Itoa(season)}
+	data, err := c.requestOmdbAPI(ctx, queryParams(OpSeason, q))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.format == FormatXML {
+		root, err := decodeXML(data)
+		if err != nil {
+			return nil, err
+		}
+		res := xmlSeason(root)
+		if c.emptyNA {
+			clearNotAvailable(res)
+		}
+		if c.keepRaw {
+			res.Raw = append([]byte(nil), data...)
+		}
+		return res, nil
+	}
+
+	//the envelope is decoded along with the season, the payload is parsed
+	//once.
+	decoded := struct {
+		Response string
+		Error    string
+		SeasonResult
+	}{}
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded.Response == "False" {
+		return nil, &APIError{Message: decoded.Error}
+	}
+
+	result := decoded.SeasonResult
+
+	if c.strictDecode {
+		if err := unknownFields(data, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.emptyNA {
+		clearNotAvailable(&result)
+	}
+	if c.keepRaw {
+		result.Raw = append([]byte(nil), data...)
+	}
+	return &result, nil
+}
+
+//GetEpisode looks up an episode of the series identified by seriesID by its
+//season and episode numbers, both starting from 1.
+func (c *Client) GetEpisode(ctx context.Context, seriesID string, season, episode int, opts ...CallOption) (*EpisodeResult, error) {
+
+	if seriesID == "" {
+		return nil, invalidRequest("omdb: Series ImdbID is missing")
+	}
+	if season < 1 || episode < 1 {
+		return nil, invalidRequest("omdb: Season and Episode should be greater than 0")
+	}
+	seriesID, err := NormalizeImdbID(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, o := withCallOptions(ctx, opts)
+	q := QueryData{ImdbID: seriesID, Season: strconv.Itoa(season), Episode: strconv.Itoa(episode), Plot: o.plot}
+	if q.Plot == "" {
+		q.Plot = c.defaultPlot
+	}
+	res, err := c.lookup(ctx, queryParams(OpEpisode, q))
+	return asEpisode(res, err)
+}
+
+//GetAllEpisodes returns every episode of the series identified by seriesID,
+//ordered by season and episode. The first season is requested to learn the
+//number of seasons, the remaining ones are requested with up to workers
+//concurrent requests (1 when workers is less than 1). Seasons OMDB doesn't
+//know are skipped.
+func (c *Client) GetAllEpisodes(ctx context.Context, seriesID string, workers int) ([]SeasonEpisode, error) {
+
+	first, err := c.GetSeason(ctx, seriesID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := strconv.Atoi(first.TotalSeasons)
+	if err != nil || total < 1 {
+		total = 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	seasons := make([]*SeasonResult, total)
+	errs := make([]error, total)
+	seasons[0] = first
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				seasons[i], errs[i] = c.GetSeason(ctx, seriesID, i+1)
+			}
+		}()
+	}
+	for i := 1; i < total; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var episodes []SeasonEpisode
+	for i, season := range seasons {
+		if errs[i] != nil {
+			if errors.Is(errs[i], ErrNotFound) {
+				continue
+			}
+			return nil, errs[i]
+		}
+		for _, episode := range season.Episodes {
+			episode.Season = strconv.Itoa(i + 1)
+			episodes = append(episodes, episode)
+		}
+	}
+
+	return episodes, nil
+}
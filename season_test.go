@@ -0,0 +1,143 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//breakingBad is a fake server with the first seasons of Breaking Bad.
+func breakingBad(t *testing.T) *omdbtest.Server {
+
+	t.Helper()
+	srv := omdbtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747", TotalSeasons: "2"})
+	srv.AddSeason("tt0903747", omdb.SeasonResult{Title: "Breaking Bad", Season: "1", TotalSeasons: "2", Episodes: []omdb.SeasonEpisode{
+		{Title: "Pilot", Released: "2008-01-20", Episode: "1", ImdbRating: "9.0", ImdbID: "tt0959621"},
+		{Title: "Cat's in the Bag...", Released: "2008-01-27", Episode: "2", ImdbRating: "N/A", ImdbID: "tt1054724"},
+	}})
+	srv.AddSeason("tt0903747", omdb.SeasonResult{Title: "Breaking Bad", Season: "2", TotalSeasons: "2", Episodes: []omdb.SeasonEpisode{
+		{Title: "Seven Thirty-Seven", Released: "2009-03-08", Episode: "1", ImdbRating: "8.6", ImdbID: "tt1232244"},
+	}})
+	srv.AddEpisode(omdb.EpisodeResult{Title: "Cat's in the Bag...", Year: "2008", ImdbID: "tt1054724", SeriesID: "tt0903747", Season: "1", Episode: "2"})
+	return srv
+}
+
+func TestGetSeason(t *testing.T) {
+
+	ctx := context.Background()
+	srv := breakingBad(t)
+
+	season, err := srv.Client().GetSeason(ctx, "tt0903747", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if season.Title != "Breaking Bad" || season.Season != "1" || season.TotalSeasons != "2" || len(season.Episodes) != 2 {
+		t.Fatalf("got season %+v", season)
+	}
+	if e := season.Episodes[0]; e.Title != "Pilot" || e.Released != "2008-01-20" || e.Episode != "1" || e.ImdbRating != "9.0" || e.ImdbID != "tt0959621" {
+		t.Errorf("got episode %+v", e)
+	}
+	if e := season.Episodes[1]; e.ImdbRating != "N/A" {
+		t.Errorf("got rating %q, want N/A", e.ImdbRating)
+	}
+
+	season, err = srv.Client(omdb.WithEmptyNotAvailable()).GetSeason(ctx, "tt0903747", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := season.Episodes[1]; e.ImdbRating != "" {
+		t.Errorf("got rating %q with WithEmptyNotAvailable, want none", e.ImdbRating)
+	}
+
+	if _, err := srv.Client().GetSeason(ctx, "tt0903747", 3); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for an unknown season, want ErrNotFound", err)
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := srv.Client().GetSeason(ctx, "tt0903747", n); !errors.Is(err, omdb.ErrInvalidRequest) {
+			t.Errorf("season %d: got error %v, want ErrInvalidRequest", n, err)
+		}
+	}
+	if _, err := srv.Client().GetSeason(ctx, "", 1); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v without a series, want ErrInvalidRequest", err)
+	}
+	if srv.Requests() != 3 {
+		t.Errorf("got %d requests, invalid ones were sent", srv.Requests())
+	}
+}
+
+func TestGetEpisode(t *testing.T) {
+
+	ctx := context.Background()
+	srv := breakingBad(t)
+	client := srv.Client()
+
+	episode, err := client.GetEpisode(ctx, "tt0903747", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if episode.Title != "Cat's in the Bag..." || episode.ImdbID != "tt1054724" || episode.SeriesID != "tt0903747" || episode.Season != "1" || episode.Episode != "2" {
+		t.Errorf("got episode %+v", episode)
+	}
+
+	if _, err := client.GetEpisode(ctx, "tt0903747", 1, 9); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for an unknown episode, want ErrNotFound", err)
+	}
+	for _, n := range [][2]int{{0, 1}, {1, 0}, {-1, 2}, {1, -2}} {
+		if _, err := client.GetEpisode(ctx, "tt0903747", n[0], n[1]); !errors.Is(err, omdb.ErrInvalidRequest) {
+			t.Errorf("season %d episode %d: got error %v, want ErrInvalidRequest", n[0], n[1], err)
+		}
+	}
+	if _, err := client.GetEpisode(ctx, "Breaking Bad", 1, 2); !errors.Is(err, omdb.ErrInvalidRequest) {
+		t.Errorf("got error %v for an invalid series id, want ErrInvalidRequest", err)
+	}
+	if srv.Requests() != 2 {
+		t.Errorf("got %d requests, invalid ones were sent", srv.Requests())
+	}
+}
+
+func TestGetAllEpisodes(t *testing.T) {
+
+	ctx := context.Background()
+	srv := breakingBad(t)
+
+	for _, workers := range []int{0, 1, 4} {
+		episodes, err := srv.Client().GetAllEpisodes(ctx, "tt0903747", workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range episodes {
+			got = append(got, e.Season+"x"+e.Episode+" "+e.Title)
+		}
+		want := []string{"1x1 Pilot", "1x2 Cat's in the Bag...", "2x1 Seven Thirty-Seven"}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Errorf("%d workers: got %q, want %q", workers, got, want)
+		}
+	}
+
+	//seasons OMDB doesn't know are skipped.
+	srv = omdbtest.NewServer()
+	defer srv.Close()
+	for _, n := range []string{"1", "3"} {
+		srv.AddSeason("tt0903747", omdb.SeasonResult{Title: "Breaking Bad", Season: n, TotalSeasons: "3", Episodes: []omdb.SeasonEpisode{
+			{Title: "Episode " + n, Episode: "1"},
+		}})
+	}
+	episodes, err := srv.Client().GetAllEpisodes(ctx, "tt0903747", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 2 || episodes[0].Season != "1" || episodes[1].Season != "3" {
+		t.Errorf("got episodes %+v, want seasons 1 and 3", episodes)
+	}
+
+	if _, err := srv.Client().GetAllEpisodes(ctx, "tt0000001", 2); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for an unknown series, want ErrNotFound", err)
+	}
+}
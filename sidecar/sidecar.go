@@ -0,0 +1,144 @@
+//Package sidecar writes the metadata and poster files media servers read
+//next to video files, so a library can be described from OMDB without the
+//servers fetching anything online. The metadata is written as Kodi .nfo
+//files, see package nfo, which Jellyfin reads natively and Plex reads with
+//its XBMCnfo agents; the posters are named the way the local media agents of
+//each server look for them:
+//
+//	x := sidecar.New(sidecar.Jellyfin, client)
+//	err := x.Movie(ctx, "/media/The Matrix (1999)/The Matrix (1999).mkv", movie)
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/nfo"
+	"github.com/ahin/omdb/scene"
+)
+
+//Layout is the naming of the sidecar files of a media server.
+type Layout int
+
+const (
+	//Jellyfin names the files of a video after it: "<video>.nfo" and
+	//"<video>-poster.jpg" for a movie, "<video>-thumb.jpg" for an episode.
+	Jellyfin Layout = iota
+	//Plex names the poster of a movie and the thumbnail of an episode like
+	//the video, "<video>.jpg", and writes "<video>.nfo" next to it.
+	Plex
+)
+
+//Posters downloads the poster of a title to a file, *omdb.Client is one.
+type Posters interface {
+	DownloadPoster(ctx context.Context, imdbID, path string) (bool, error)
+}
+
+//Exporter writes the sidecar files of movies, series and episodes.
+type Exporter struct {
+	layout  Layout
+	posters Posters
+}
+
+//New returns an Exporter writing files in layout. Posters are downloaded
+//with posters, or not at all when it is nil.
+func New(layout Layout, posters Posters) *Exporter {
+	return &Exporter{layout: layout, posters: posters}
+}
+
+//Movie writes the nfo and poster of m next to the video file at video.
+func (x *Exporter) Movie(ctx context.Context, video string, m omdb.MovieResult) error {
+
+	if err := nfo.WriteFile(trimExt(video)+".nfo", nfo.Movie(m)); err != nil {
+		return err
+	}
+	return x.poster(ctx, m.ImdbID, x.imagePath(video, "-poster"))
+}
+
+//Series writes tvshow.nfo and poster.jpg of s in dir, the folder of the
+//series, which both servers read.
+func (x *Exporter) Series(ctx context.Context, dir string, s omdb.SeriesResult) error {
+
+	if err := nfo.WriteFile(filepath.Join(dir, nfo.TVShowFile), nfo.TVShow(s)); err != nil {
+		return err
+	}
+	return x.poster(ctx, s.ImdbID, filepath.Join(dir, "poster.jpg"))
+}
+
+//Episode writes the nfo and thumbnail of e, see nfo.Episode, next to the
+//video file at video.
+func (x *Exporter) Episode(ctx context.Context, video string, e omdb.EpisodeResult, showTitle string, season, episode int) error {
+
+	if err := nfo.WriteFile(nfo.EpisodeFile(video), nfo.Episode(e, showTitle, season, episode)); err != nil {
+		return err
+	}
+	return x.poster(ctx, e.ImdbID, x.imagePath(video, "-thumb"))
+}
+
+//Entry writes the sidecars of a Matched or Ambiguous entry of scene.Scan,
+//other entries are skipped. The series of an episode or season is written to
+//the folder above its "Season NN" folder, or to the folder of the file when
+//it isn't in one.
+func (x *Exporter) Entry(ctx context.Context, e scene.Entry) error {
+
+	if e.Status != scene.Matched && e.Status != scene.Ambiguous {
+		return nil
+	}
+
+	switch r := e.Result.(type) {
+	case omdb.MovieResult:
+		return x.Movie(ctx, e.Path, r)
+	case omdb.SeriesResult:
+		return x.Series(ctx, seriesDir(e.Path), r)
+	case omdb.EpisodeResult:
+		if e.Series == nil {
+			return errors.New("sidecar: No series for episode " + e.Path)
+		}
+		if err := x.Series(ctx, seriesDir(e.Path), *e.Series); err != nil {
+			return err
+		}
+		return x.Episode(ctx, e.Path, r, e.Series.Title, e.Release.Season, e.Release.Episode)
+	}
+	return nil
+}
+
+//imagePath returns the path of an image of the video file at video, with
+//suffix appended to its name in the Jellyfin layout.
+func (x *Exporter) imagePath(video, suffix string) string {
+	if x.layout == Plex {
+		return trimExt(video) + ".jpg"
+	}
+	return trimExt(video) + suffix + ".jpg"
+}
+
+func trimExt(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+//poster downloads the poster of imdbID to path, titles without poster are
+//skipped.
+func (x *Exporter) poster(ctx context.Context, imdbID, path string) error {
+
+	if x.posters == nil || imdbID == "" {
+		return nil
+	}
+	_, err := x.posters.DownloadPoster(ctx, imdbID, path)
+	if errors.Is(err, omdb.ErrNotAvailable) {
+		return nil
+	}
+	return err
+}
+
+var seasonDirRe = regexp.MustCompile(`(?i)^(?:season|staffel|series|s)[ ._-]?\d+$|^specials$`)
+
+func seriesDir(video string) string {
+	dir := filepath.Dir(video)
+	if seasonDirRe.MatchString(filepath.Base(dir)) {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
@@ -0,0 +1,157 @@
+package sidecar_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/scene"
+	"github.com/ahin/omdb/sidecar"
+)
+
+//posters writes the id of the title to each poster file, titles without a
+//poster are not available.
+type posters struct{}
+
+func (posters) DownloadPoster(ctx context.Context, imdbID, path string) (bool, error) {
+	if imdbID == "tt0000000" {
+		return false, omdb.ErrNotAvailable
+	}
+	return true, os.WriteFile(path, []byte(imdbID), 0644)
+}
+
+//files returns the paths of the files under dir, relative to it.
+func files(t *testing.T, dir string) string {
+
+	t.Helper()
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		paths = append(paths, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ", ")
+}
+
+func TestLayouts(t *testing.T) {
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		layout sidecar.Layout
+		want   string
+	}{
+		{sidecar.Jellyfin, "Breaking Bad/Season 01/Pilot-thumb.jpg, Breaking Bad/Season 01/Pilot.mkv, Breaking Bad/Season 01/Pilot.nfo, Breaking Bad/poster.jpg, Breaking Bad/tvshow.nfo, The Matrix-poster.jpg, The Matrix.mkv, The Matrix.nfo"},
+		{sidecar.Plex, "Breaking Bad/Season 01/Pilot.jpg, Breaking Bad/Season 01/Pilot.mkv, Breaking Bad/Season 01/Pilot.nfo, Breaking Bad/poster.jpg, Breaking Bad/tvshow.nfo, The Matrix.jpg, The Matrix.mkv, The Matrix.nfo"},
+	} {
+		dir := t.TempDir()
+		show := filepath.Join(dir, "Breaking Bad")
+		if err := os.MkdirAll(filepath.Join(show, "Season 01"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		movie := filepath.Join(dir, "The Matrix.mkv")
+		episode := filepath.Join(show, "Season 01", "Pilot.mkv")
+		for _, video := range []string{movie, episode} {
+			if err := os.WriteFile(video, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		x := sidecar.New(tt.layout, posters{})
+		if err := x.Movie(ctx, movie, omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := x.Series(ctx, show, omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := x.Episode(ctx, episode, omdb.EpisodeResult{Title: "Pilot", ImdbID: "tt0959621"}, "Breaking Bad", 1, 1); err != nil {
+			t.Fatal(err)
+		}
+		if got := files(t, dir); got != tt.want {
+			t.Errorf("layout %d: got files %s, want %s", tt.layout, got, tt.want)
+		}
+
+		data, err := os.ReadFile(filepath.Join(show, "tvshow.nfo"))
+		if err != nil || !strings.Contains(string(data), "<title>Breaking Bad</title>") {
+			t.Errorf("layout %d: got tvshow.nfo %s, %v", tt.layout, data, err)
+		}
+		data, err = os.ReadFile(filepath.Join(show, "Season 01", "Pilot.nfo"))
+		if err != nil || !strings.Contains(string(data), "<showtitle>Breaking Bad</showtitle>") {
+			t.Errorf("layout %d: got Pilot.nfo %s, %v", tt.layout, data, err)
+		}
+	}
+}
+
+func TestNoPoster(t *testing.T) {
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Unknown.mkv")
+
+	//titles without poster, or exporters without Posters, write the nfo only.
+	if err := sidecar.New(sidecar.Jellyfin, posters{}).Movie(ctx, video, omdb.MovieResult{Title: "Unknown", ImdbID: "tt0000000"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sidecar.New(sidecar.Jellyfin, nil).Movie(ctx, filepath.Join(dir, "Dune.mkv"), omdb.MovieResult{Title: "Dune", ImdbID: "tt1160419"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := files(t, dir); got != "Dune.nfo, Unknown.nfo" {
+		t.Errorf("got files %s", got)
+	}
+}
+
+func TestEntry(t *testing.T) {
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	x := sidecar.New(sidecar.Jellyfin, posters{})
+	series := &omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747"}
+
+	for _, e := range []scene.Entry{
+		{
+			Path:    filepath.Join(dir, "Breaking Bad", "Season 01", "Breaking.Bad.S01E01.mkv"),
+			Release: scene.Release{Title: "Breaking Bad", Season: 1, Episode: 1},
+			Status:  scene.Matched,
+			Result:  omdb.EpisodeResult{Title: "Pilot", ImdbID: "tt0959621"},
+			Series:  series,
+		},
+		{
+			Path:   filepath.Join(dir, "Dune.2021.mkv"),
+			Status: scene.Ambiguous,
+			Result: omdb.MovieResult{Title: "Dune", ImdbID: "tt1160419"},
+		},
+		{
+			Path:   filepath.Join(dir, "Sherlock", "Sherlock.mkv"),
+			Status: scene.Matched,
+			Result: omdb.SeriesResult{Title: "Sherlock", ImdbID: "tt1475582"},
+		},
+		{Path: filepath.Join(dir, "Missed.mkv"), Status: scene.Missed},
+		{Path: filepath.Join(dir, "Failed.mkv"), Status: scene.Failed, Result: omdb.MovieResult{ImdbID: "tt0133093"}},
+	} {
+		if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := x.Entry(ctx, e); err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+		}
+	}
+	want := "Breaking Bad/Season 01/Breaking.Bad.S01E01-thumb.jpg, Breaking Bad/Season 01/Breaking.Bad.S01E01.nfo, Breaking Bad/poster.jpg, Breaking Bad/tvshow.nfo, Dune.2021-poster.jpg, Dune.2021.nfo, Sherlock/poster.jpg, Sherlock/tvshow.nfo"
+	if got := files(t, dir); got != want {
+		t.Errorf("got files %s, want %s", got, want)
+	}
+
+	episode := scene.Entry{Path: filepath.Join(dir, "Pilot.mkv"), Status: scene.Matched, Result: omdb.EpisodeResult{Title: "Pilot"}}
+	if err := x.Entry(ctx, episode); err == nil {
+		t.Error("got no error for an episode without series")
+	}
+}
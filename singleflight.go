@@ -0,0 +1,94 @@
+package omdb
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+//flight is an in-flight request shared by concurrent identical lookups.
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+	meta metaRecorder
+
+	//waiters counts the callers waiting for the flight, the last one to
+	//give up cancels it. Both are guarded by the mutex of the group.
+	waiters int
+	cancel  context.CancelFunc
+}
+
+//flightGroup coalesces concurrent requests with the same cache key.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+//fetchShared is fetch, except that concurrent calls for the same key share a
+//single request. The request keeps the deadline of the caller which started
+//it, but one caller giving up doesn't fail the others: each caller returns as
+//soon as its own ctx is done, and the request is only canceled once every
+//caller has given up.
+func (c *Client) fetchShared(ctx context.Context, key string, params url.Values, prev *cacheEntry) ([]byte, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	//requests sent with different API keys are never shared.
+	flightKey := key
+	if apiKey := callOptionsFrom(ctx).apiKey; apiKey != "" {
+		flightKey += "&apikey=" + apiKey
+	}
+
+	g := &c.flights
+	g.mu.Lock()
+	f, ok := g.flights[flightKey]
+	if !ok {
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		fctx := context.WithoutCancel(ctx)
+		var cancelDeadline context.CancelFunc = func() {}
+		if deadline, ok := ctx.Deadline(); ok {
+			fctx, cancelDeadline = context.WithDeadline(fctx, deadline)
+		}
+		fctx, cancel := context.WithCancel(fctx)
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[flightKey] = f
+
+		go func() {
+			defer cancelDeadline()
+			f.data, f.err = c.fetch(withMetaRecorder(fctx, &f.meta), key, params, prev)
+			g.mu.Lock()
+			if g.flights[flightKey] == f {
+				delete(g.flights, flightKey)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		setMeta(ctx, f.meta.get())
+		return f.data, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			//nobody waits for the response anymore, later callers start a
+			//new flight.
+			if g.flights[flightKey] == f {
+				delete(g.flights, flightKey)
+			}
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,168 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//blockingServer answers lookups once release is closed, counting the
+//requests and those which were canceled by the client.
+type blockingServer struct {
+	*httptest.Server
+	release  chan struct{}
+	requests atomic.Int32
+	canceled atomic.Int32
+}
+
+func newBlockingServer() *blockingServer {
+	s := &blockingServer{release: make(chan struct{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		select {
+		case <-s.release:
+		case <-r.Context().Done():
+			s.canceled.Add(1)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Title":"Movie","Year":"1999","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+	return s
+}
+
+func (s *blockingServer) client() *Client {
+	return NewClient("key", s.Client(), WithBaseURL(s.URL+"/"))
+}
+
+func TestFlightCoalescesIdenticalRequests(t *testing.T) {
+
+	srv := newBlockingServer()
+	defer srv.Close()
+	c := srv.client()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetMovieByID(context.Background(), "tt0133093"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitFor(t, func() bool { return srv.requests.Load() == 1 })
+	//let the other callers join the flight.
+	time.Sleep(20 * time.Millisecond)
+	close(srv.release)
+	wg.Wait()
+
+	if n := srv.requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestFlightNotStartedWithCanceledContext(t *testing.T) {
+
+	srv := newBlockingServer()
+	defer srv.Close()
+	close(srv.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := srv.client().GetMovieByID(ctx, "tt0133093"); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if n := srv.requests.Load(); n != 0 {
+		t.Errorf("server got %d requests, want none", n)
+	}
+}
+
+func TestFlightCanceledWhenEveryCallerLeaves(t *testing.T) {
+
+	srv := newBlockingServer()
+	defer srv.Close()
+	defer close(srv.release)
+	c := srv.client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := c.GetMovieByID(ctx, "tt0133093")
+			errs <- err
+		}()
+	}
+	waitFor(t, func() bool { return srv.requests.Load() == 1 })
+	cancel()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+	}
+	waitFor(t, func() bool { return srv.canceled.Load() == 1 })
+}
+
+func TestFlightKeepsRunningForRemainingCallers(t *testing.T) {
+
+	srv := newBlockingServer()
+	defer srv.Close()
+	c := srv.client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.GetMovieByID(ctx, "tt0133093")
+		first <- err
+	}()
+	waitFor(t, func() bool { return srv.requests.Load() == 1 })
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.GetMovieByID(context.Background(), "tt0133093")
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller got error %v, want context.Canceled", err)
+	}
+	close(srv.release)
+	if err := <-second; err != nil {
+		t.Errorf("second caller got error %v", err)
+	}
+	if n, canceled := srv.requests.Load(), srv.canceled.Load(); n != 1 || canceled != 0 {
+		t.Errorf("server got %d requests, %d canceled, want 1 and none canceled", n, canceled)
+	}
+}
+
+func TestFlightKeepsDeadline(t *testing.T) {
+
+	srv := newBlockingServer()
+	defer srv.Close()
+	defer close(srv.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := srv.client().GetMovieByID(ctx, "tt0133093"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	waitFor(t, func() bool { return srv.canceled.Load() == 1 })
+}
+
+//waitFor polls cond for up to a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("condition not met within a second")
+}
@@ -0,0 +1,129 @@
+//Package sqlitecache implements omdb.Cache in a SQLite table. Besides the raw
+//response, the title, year, type and imdb id of lookups are stored in their
+//own columns so the cached data can be queried offline with SQL:
+//
+//	SELECT title, year FROM omdb_cache WHERE type = 'movie' ORDER BY year
+//
+//The package only uses database/sql, the caller opens the *sql.DB with the
+//SQLite driver of its choice (e.g. modernc.org/sqlite or
+//github.com/mattn/go-sqlite3).
+package sqlitecache
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+//DefaultTable is the name of the table used unless WithTable is given.
+const DefaultTable = "omdb_cache"
+
+//Cache is an omdb.Cache stored in a SQLite table with the columns key, op,
+//imdb_id, title, year, type, body and expires_at (unix seconds, 0 for an entry
+//which never expires).
+type Cache struct {
+	db    *sql.DB
+	table string
+}
+
+//Option configures a Cache.
+type Option func(*Cache)
+
+//WithTable stores the cache in table instead of DefaultTable.
+func WithTable(table string) Option {
+	return func(c *Cache) {
+		c.table = table
+	}
+}
+
+//New creates a Cache in db, the table and its indexes are created when they
+//don't exist.
+func New(ctx context.Context, db *sql.DB, opts ...Option) (*Cache, error) {
+
+	c := &Cache{db: db, table: DefaultTable}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS ` + c.table + ` (
+			key        TEXT PRIMARY KEY,
+			op         TEXT NOT NULL,
+			imdb_id    TEXT,
+			title      TEXT,
+			year       TEXT,
+			type       TEXT,
+			body       BLOB NOT NULL,
+			expires_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + c.table + `_imdb_id ON ` + c.table + ` (imdb_id)`,
+		`CREATE INDEX IF NOT EXISTS ` + c.table + `_title ON ` + c.table + ` (title)`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+//Get implements omdb.Cache.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+
+	var body []byte
+	err := c.db.QueryRowContext(ctx,
+		`SELECT body FROM `+c.table+` WHERE key = ? AND (expires_at = 0 OR expires_at > ?)`,
+		key, time.Now().Unix()).Scan(&body)
+	if err == sql.ErrNoRows {
+		return nil, omdb.ErrCacheMiss
+	}
+	return body, err
+}
+
+//Set implements omdb.Cache.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+
+	//search responses and XML bodies leave the columns NULL.
+	var fields struct {
+		ImdbID *string
+		Title  *string
+		Year   *string
+		Type   *string
+	}
+	if body := bytes.TrimSpace(omdb.CachedBody(value)); bytes.HasPrefix(body, []byte("{")) {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return errors.New("sqlitecache: Invalid response cached for " + key + ": " + err.Error())
+		}
+	}
+
+	var expires int64
+	if ttl != 0 {
+		expires = time.Now().Add(ttl).Unix()
+	}
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO `+c.table+` (key, op, imdb_id, title, year, type, body, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			op = excluded.op, imdb_id = excluded.imdb_id, title = excluded.title,
+			year = excluded.year, type = excluded.type, body = excluded.body,
+			expires_at = excluded.expires_at`,
+		key, omdb.CacheKeyOp(key), fields.ImdbID, fields.Title, fields.Year, fields.Type,
+		value, expires)
+	return err
+}
+
+//Delete implements omdb.Cache.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	_, err := c.db.ExecContext(ctx, `DELETE FROM `+c.table+` WHERE key = ?`, key)
+	return err
+}
+
+//Purge deletes every expired entry.
+func (c *Cache) Purge(ctx context.Context) error {
+	_, err := c.db.ExecContext(ctx, `DELETE FROM `+c.table+` WHERE expires_at != 0 AND expires_at <= ?`, time.Now().Unix())
+	return err
+}
@@ -0,0 +1,107 @@
+package sqlitecache_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/sqlitecache"
+
+	_ "modernc.org/sqlite"
+)
+
+func openCache(t *testing.T) (*sql.DB, *sqlitecache.Cache) {
+
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//every connection to :memory: is a database of its own.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	cache, err := sqlitecache.New(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, cache
+}
+
+func TestColumnsIndexed(t *testing.T) {
+
+	for name, opts := range map[string][]omdb.Option{
+		"plain":       nil,
+		"stale":       {omdb.WithStaleWhileRevalidate(time.Hour)},
+		"conditional": {omdb.WithConditionalRequests(time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+
+			db, cache := openCache(t)
+			srv := omdbtest.NewServer()
+			defer srv.Close()
+			srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+
+			client := srv.Client(append([]omdb.Option{omdb.WithCache(cache, time.Hour)}, opts...)...)
+			if _, err := client.GetMovieByID(context.Background(), "tt0133093"); err != nil {
+				t.Fatal(err)
+			}
+
+			var id, title, year, typ sql.NullString
+			err := db.QueryRow(`SELECT imdb_id, title, year, type FROM omdb_cache WHERE op = ?`, omdb.OpByID).
+				Scan(&id, &title, &year, &typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.String != "tt0133093" || title.String != "The Matrix" || year.String != "1999" || typ.String != "movie" {
+				t.Errorf("got columns %v %v %v %v", id, title, year, typ)
+			}
+		})
+	}
+}
+
+func TestGetSetDelete(t *testing.T) {
+
+	ctx := context.Background()
+	_, cache := openCache(t)
+
+	if _, err := cache.Get(ctx, "k"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Fatalf("got error %v, want ErrCacheMiss", err)
+	}
+	if err := cache.Set(ctx, "k", []byte(`{"Search":[]}`), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := cache.Get(ctx, "k"); err != nil || string(v) != `{"Search":[]}` {
+		t.Fatalf("got %q, %v", v, err)
+	}
+	if err := cache.Set(ctx, "expired", []byte(`<root/>`), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, "expired"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v for an expired entry, want ErrCacheMiss", err)
+	}
+
+	//a ttl of 0 never expires, nor is it purged.
+	if err := cache.Set(ctx, "forever", []byte(`{"Search":[]}`), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Purge(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, "forever"); err != nil {
+		t.Errorf("got error %v for an entry without a ttl", err)
+	}
+	if err := cache.Delete(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, "k"); !errors.Is(err, omdb.ErrCacheMiss) {
+		t.Errorf("got error %v after Delete, want ErrCacheMiss", err)
+	}
+	if err := cache.Set(ctx, "bad", []byte(`{"Title":`), time.Hour); err == nil {
+		t.Error("invalid JSON was cached without error")
+	}
+}
@@ -0,0 +1,58 @@
+package omdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRevalidateKeepsCallOptions(t *testing.T) {
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != "tenant" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"Response":"False","Error":"Invalid API key!"}`))
+			return
+		}
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Write([]byte(`{"Title":"The Matrix","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+			return
+		}
+		w.Write([]byte(`{"Title":"The Matrix Reloaded","imdbID":"tt0133093","Type":"movie","Response":"True"}`))
+	}))
+	defer srv.Close()
+
+	cache := NewLRUCache(10)
+	client := NewClient("other", nil, WithBaseURL(srv.URL), WithCache(cache, time.Millisecond), WithStaleWhileRevalidate(time.Hour))
+	q := QueryData{ImdbID: "tt0133093"}
+	title := func(res interface{}) string { return res.(MovieResult).Title }
+
+	if _, err := client.SearchByImdbIDContext(context.Background(), q, WithCallAPIKey("tenant")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	//the stale entry is served, and refreshed after the caller is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	var meta ResponseMeta
+	res, err := client.SearchByImdbIDContext(ctx, q, WithCallAPIKey("tenant"), WithResponseMeta(&meta))
+	cancel()
+	if err != nil || title(res) != "The Matrix" || !meta.Stale {
+		t.Fatalf("got %v, %v, %+v, want the stale entry", res, err, meta)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&requests) == 2 })
+	waitFor(t, func() bool {
+		entry, err := client.cached(context.Background(), client.CacheKey(q, OpByID))
+		return err == nil && strings.Contains(string(entry.body), "Reloaded")
+	})
+
+	res, err = client.SearchByImdbIDContext(context.Background(), q, WithCallAPIKey("tenant"))
+	if err != nil || title(res) != "The Matrix Reloaded" {
+		t.Errorf("got %v, %v, want the refreshed entry", res, err)
+	}
+}
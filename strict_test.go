@@ -0,0 +1,80 @@
+package omdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+//fixtureServer serves the file of testdata named by the t or s parameter,
+//e.g. "movie" for testdata/movie.json, or testdata/movie.xml with r=xml.
+func fixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		name, ext := q.Get("t"), ".json"
+		if name == "" {
+			name = q.Get("s")
+		}
+		if q.Get("r") == FormatXML {
+			ext = ".xml"
+		}
+		data, err := os.ReadFile("testdata/" + name + ext)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStrictDecode(t *testing.T) {
+
+	srv := fixtureServer(t)
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithStrictDecode())
+
+	for _, name := range []string{"movie", "series", "episode"} {
+		res, err := client.SearchByTitle(QueryData{Title: name})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if TypeOf(res) != name {
+			t.Errorf("%s: got a %s", name, TypeOf(res))
+		}
+	}
+
+	res, err := client.SearchByTitle(QueryData{Title: "episode"})
+	if e, ok := res.(EpisodeResult); err != nil || !ok || e.Season != "1" || e.Episode != "2" || e.SeriesID != "tt0903747" {
+		t.Errorf("got %+v, %v, want season 1 episode 2", res, err)
+	}
+}
+
+func TestStrictDecodeUnknownFields(t *testing.T) {
+
+	movie, err := os.ReadFile("testdata/movie.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := strings.Replace(string(movie), `"DVD"`, `"Trailer":"N/A","Ratings2":[],"DVD"`, 1)
+	body = strings.Replace(body, `"Value":"83%"`, `"Value":"83%","Count":"300"`, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	_, err = NewClient("key", nil, WithBaseURL(srv.URL), WithStrictDecode()).SearchByTitle(QueryData{Title: "movie"})
+	if !errors.Is(err, ErrUnknownField) || !strings.HasSuffix(err.Error(), "Ratings.Count, Ratings2, Trailer") {
+		t.Errorf("got error %v, want Ratings.Count, Ratings2 and Trailer unknown", err)
+	}
+
+	//without strict decoding the fields are dropped.
+	if _, err := NewClient("key", nil, WithBaseURL(srv.URL)).SearchByTitle(QueryData{Title: "movie"}); err != nil {
+		t.Errorf("got error %v without strict decoding", err)
+	}
+}
@@ -0,0 +1,47 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDefaultTimeout(t *testing.T) {
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewClient("key", &http.Client{}, WithBaseURL(srv.URL), WithDefaultTimeout(20*time.Millisecond))
+	start := time.Now()
+	if _, err := client.SearchByImdbID(QueryData{ImdbID: "tt0133093"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("the request took %v", d)
+	}
+
+	//a deadline of the caller takes precedence.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, err := client.SearchByImdbIDContext(ctx, QueryData{ImdbID: "tt0133093"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Errorf("the request was cancelled after %v, before the deadline of its context", d)
+	}
+
+	if err := NewClient("key", nil, WithDefaultTimeout(-time.Second)).err; err == nil {
+		t.Error("got no error for a negative default timeout")
+	}
+}
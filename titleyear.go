@@ -0,0 +1,62 @@
+package omdb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+//ParseTitleYear splits a name in the "Title (Year)" convention of media
+//libraries, e.g. "Blade Runner (1982)", into its title and year. The year of
+//a series run like "Breaking Bad (2008–2013)" is its first year. year is 0,
+//and title the trimmed s, when s doesn't end with a year in parentheses or
+//brackets.
+func ParseTitleYear(s string) (title string, year int) {
+
+	s = strings.TrimSpace(s)
+	if len(s) < 6 {
+		return s, 0
+	}
+
+	var open byte
+	switch s[len(s)-1] {
+	case ')':
+		open = '('
+	case ']':
+		open = '['
+	default:
+		return s, 0
+	}
+	i := strings.LastIndexByte(s, open)
+	if i < 0 {
+		return s, 0
+	}
+
+	inner := s[i+1 : len(s)-1]
+	if j := strings.IndexAny(inner, "-–"); j >= 0 {
+		inner = inner[:j]
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(inner))
+	if err != nil || y < 1888 || y > 9999 {
+		return s, 0
+	}
+
+	title = strings.TrimSpace(s[:i])
+	if title == "" {
+		return s, 0
+	}
+	return title, y
+}
+
+//SearchByTitleYear looks up a movie, series or episode like
+//SearchByTitleContext by a name in the "Title (Year)" format, see
+//ParseTitleYear. A name without year looks up the title alone.
+func (c *Client) SearchByTitleYear(ctx context.Context, name string, opts ...CallOption) (interface{}, error) {
+
+	title, year := ParseTitleYear(name)
+	q := QueryData{Title: title}
+	if year > 0 {
+		q.Year = strconv.Itoa(year)
+	}
+	return c.SearchByTitleContext(ctx, q, opts...)
+}
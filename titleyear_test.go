@@ -0,0 +1,35 @@
+package omdb_test
+
+import (
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestParseTitleYear(t *testing.T) {
+
+	for _, tt := range []struct {
+		s     string
+		title string
+		year  int
+	}{
+		{"The Matrix (1999)", "The Matrix", 1999},
+		{"  Blade Runner (1982) ", "Blade Runner", 1982},
+		{"Alien [1979]", "Alien", 1979},
+		{"Breaking Bad (2008–2013)", "Breaking Bad", 2008},
+		{"Doctor Who (2005-)", "Doctor Who", 2005},
+		{"The Matrix", "The Matrix", 0},
+		{"Monty Python (and the Holy Grail) (1975)", "Monty Python (and the Holy Grail)", 1975},
+		{"Monty Python (and the Holy Grail)", "Monty Python (and the Holy Grail)", 0},
+		{"Ocean's Eleven (remake)", "Ocean's Eleven (remake)", 0},
+		{"Metropolis (1887)", "Metropolis (1887)", 0},
+		{"(1999)", "(1999)", 0},
+		{"1999", "1999", 0},
+		{"", "", 0},
+	} {
+		title, year := omdb.ParseTitleYear(tt.s)
+		if title != tt.title || year != tt.year {
+			t.Errorf("ParseTitleYear(%q) = %q, %d, want %q, %d", tt.s, title, year, tt.title, tt.year)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package omdb
+
+import (
+	"context"
+	"net/url"
+)
+
+//Tracer starts a span for every request of a client, see WithTracer. The
+//oteltrace package implements it with OpenTelemetry. Implementations have to
+//be safe for concurrent use.
+type Tracer interface {
+	//StartRequest starts the span of a request described by info. The
+	//returned context carries the span to the HTTP request.
+	StartRequest(ctx context.Context, info RequestInfo) (context.Context, RequestSpan)
+}
+
+//RequestInfo describes a request to a Tracer.
+type RequestInfo struct {
+	//Op is the kind of request (OpByID, OpByTitle, OpSearch, OpSeason or
+	//OpEpisode).
+	Op string
+	//ImdbID is the id looked up, of the series for seasons and episodes.
+	ImdbID string
+	//Title is the title looked up or the text searched.
+	Title string
+}
+
+//RequestSpan is the span of a single request.
+type RequestSpan interface {
+	//CacheHit is called once the request was looked up in the cache.
+	CacheHit(hit bool)
+	//UpstreamStatus is called with the HTTP status of each response of
+	//OMDB, several times when the request is retried.
+	UpstreamStatus(code int)
+	//End ends the span with the error of the request, nil on success.
+	End(err error)
+}
+
+//WithTracer traces every request of the client with t.
+func WithTracer(t Tracer) Option {
+	return func(c *Client) error {
+		c.tracer = t
+		return nil
+	}
+}
+
+type spanKey struct{}
+
+//startSpan starts the span of the request for params, it is a no-op without
+//a tracer.
+func (c *Client) startSpan(ctx context.Context, params url.Values) (context.Context, RequestSpan) {
+	if c.tracer == nil {
+		return ctx, nopSpan{}
+	}
+	title := params.Get("t")
+	if title == "" {
+		title = params.Get("s")
+	}
+	ctx, span := c.tracer.StartRequest(ctx, RequestInfo{
+		Op:     CacheKeyOp(params.Encode()),
+		ImdbID: params.Get("i"),
+		Title:  title,
+	})
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+//spanFrom returns the span started for the request ctx belongs to.
+func spanFrom(ctx context.Context) RequestSpan {
+	if span, ok := ctx.Value(spanKey{}).(RequestSpan); ok {
+		return span
+	}
+	return nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) CacheHit(bool)      {}
+func (nopSpan) UpstreamStatus(int) {}
+func (nopSpan) End(error)          {}
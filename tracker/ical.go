@@ -0,0 +1,106 @@
+package tracker
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//releasedLayout is the format of the air dates reported by OMDB.
+const releasedLayout = "02 Jan 2006"
+
+//Aired returns the air date of the episode, ok is false when it isn't known.
+func (e Episode) Aired() (date time.Time, ok bool) {
+	t, err := time.Parse(releasedLayout, e.Released)
+	return t, err == nil
+}
+
+//WriteCalendar writes the episodes of series airing on or after the day of
+//from as an iCalendar (.ics) document, one all day event per episode,
+//for calendar apps to import or subscribe to. Episodes without a known air
+//date are left out.
+func WriteCalendar(w io.Writer, series []Series, from time.Time) error {
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	cal := &calendar{}
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//ahin//omdb tracker//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("X-WR-CALNAME:Upcoming episodes")
+	for _, s := range series {
+		for _, e := range s.Episodes {
+			aired, ok := e.Aired()
+			if !ok || aired.Before(day) {
+				continue
+			}
+			uid := e.ImdbID
+			if uid == "" {
+				uid = s.ImdbID + "-" + e.Code()
+			}
+			cal.line("BEGIN:VEVENT")
+			cal.line("UID:" + uid + "@omdb")
+			cal.line("DTSTAMP:" + stamp)
+			cal.line("DTSTART;VALUE=DATE:" + aired.Format("20060102"))
+			cal.line("DTEND;VALUE=DATE:" + aired.AddDate(0, 0, 1).Format("20060102"))
+			summary := s.Title + " " + e.Code()
+			if e.Title != "" {
+				summary += " - " + e.Title
+			}
+			cal.line("SUMMARY:" + escapeText(summary))
+			if e.ImdbID != "" {
+				cal.line("URL:https://www.imdb.com/title/" + e.ImdbID + "/")
+			}
+			cal.line("TRANSP:TRANSPARENT")
+			cal.line("END:VEVENT")
+		}
+	}
+	cal.line("END:VCALENDAR")
+
+	_, err := w.Write(cal.Bytes())
+	return err
+}
+
+//CalendarHandler serves the calendar of the upcoming episodes of the followed
+//series, see WriteCalendar, for calendar apps to subscribe to.
+func (t *Tracker) CalendarHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &bytes.Buffer{}
+		if err := WriteCalendar(buf, t.List(), time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write(buf.Bytes())
+	})
+}
+
+//calendar buffers the content lines of an iCalendar document, folded at 75
+//octets and ended by CRLF as RFC 5545 requires.
+type calendar struct {
+	bytes.Buffer
+}
+
+func (c *calendar) line(s string) {
+	//continuation lines start with a space, which counts towards their 75
+	//octets.
+	for max := 75; len(s) > max; max = 74 {
+		//don't split a UTF-8 sequence.
+		i := max
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		c.WriteString(s[:i] + "\r\n ")
+		s = s[i:]
+	}
+	c.WriteString(s + "\r\n")
+}
+
+//escapeText escapes a TEXT value of iCalendar.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,51 @@
+package tracker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCalendar(t *testing.T) {
+
+	title := strings.Repeat("Ünïcödé title, ", 12)
+	series := []Series{{ImdbID: "tt0903747", Title: "Breaking Bad", Episodes: []Episode{
+		{Season: 1, Episode: 1, Title: "Pilot", ImdbID: "tt0959621", Released: "20 Jan 2008"},
+		{Season: 5, Episode: 16, Title: title, Released: "29 Sep 2013"},
+		{Season: 6, Episode: 1, Title: "Unknown"},
+	}}}
+
+	buf := &bytes.Buffer{}
+	if err := WriteCalendar(buf, series, time.Date(2013, 9, 29, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+
+	lines := strings.Split(strings.TrimSuffix(doc, "\r\n"), "\r\n")
+	for _, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+	unfolded := strings.ReplaceAll(doc, "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:Breaking Bad S05E16 - "+escapeText(title)+"\r\n") {
+		t.Errorf("the summary doesn't unfold to the title:\n%s", doc)
+	}
+	if strings.Contains(doc, "S01E01") || strings.Contains(doc, "S06E01") {
+		t.Errorf("got episodes aired before from or without air date:\n%s", doc)
+	}
+	if !strings.Contains(doc, "UID:tt0903747-S05E16@omdb\r\n") || !strings.Contains(doc, "DTSTART;VALUE=DATE:20130929\r\n") {
+		t.Errorf("got event:\n%s", doc)
+	}
+}
+
+func TestCalendarLineFolding(t *testing.T) {
+
+	c := &calendar{}
+	c.line(strings.Repeat("a", 75+74+10))
+	want := strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n " + strings.Repeat("a", 10) + "\r\n"
+	if got := c.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
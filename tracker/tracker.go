@@ -0,0 +1,425 @@
+//Package tracker follows series and reports their new episodes and the air
+//dates which changed, polling OMDB with a client, so its cache and rate
+//limiters apply. The followed series are persisted in an omdb.Cache like a
+//watchlist:
+//
+//	t, err := tracker.Open(ctx, cache, "shows", client,
+//		tracker.OnNewEpisode(func(e tracker.Event) {
+//			fmt.Println(e.SeriesTitle, e.Episode.Code(), e.Episode.Title)
+//		}))
+//	...
+//	_, err = t.Follow(ctx, "tt0903747")
+//	...
+//	err = t.Run(ctx, 6*time.Hour)
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+//retention is how long the followed series are stored, the caches expire
+//every entry.
+const retention = 100 * 365 * 24 * time.Hour
+
+//Series is a followed series with the episodes known when it was last
+//checked.
+type Series struct {
+	ImdbID       string
+	Title        string
+	TotalSeasons int
+	Episodes     []Episode
+	Checked      time.Time
+	//Result is the series as OMDB reported it on the last check.
+	Result *omdb.SeriesResult `json:",omitempty"`
+}
+
+//Episode is an episode of a followed series. Released is the air date as
+//OMDB reports it, e.g. "20 Jan 2008", empty when it is not known yet.
+type Episode struct {
+	Season   int
+	Episode  int
+	Title    string
+	ImdbID   string `json:",omitempty"`
+	Released string `json:",omitempty"`
+}
+
+//Code returns the episode numbers in the usual "S01E02" form.
+func (e Episode) Code() string {
+	return fmt.Sprintf("S%02dE%02d", e.Season, e.Episode)
+}
+
+//EventKind tells what changed about an episode.
+type EventKind int
+
+const (
+	//NewEpisode is an episode which wasn't listed before.
+	NewEpisode EventKind = iota
+	//AirDateChanged is an episode whose air date changed, or became known.
+	AirDateChanged
+	//SeriesChanged is a series whose fields changed, like its ratings or
+	//poster, see Event.Changes.
+	SeriesChanged
+)
+
+//Event is a change of a followed series found by Check.
+type Event struct {
+	Kind        EventKind
+	SeriesID    string
+	SeriesTitle string
+	Episode     Episode
+	//PreviousReleased is the air date before an AirDateChanged event.
+	PreviousReleased string
+	//Changes are the fields of the series which changed in a SeriesChanged
+	//event, see omdb.Diff.
+	Changes []omdb.FieldChange
+}
+
+//Option configures a Tracker.
+type Option func(*Tracker)
+
+//OnNewEpisode calls f with every NewEpisode event.
+func OnNewEpisode(f func(Event)) Option {
+	return func(t *Tracker) {
+		t.onNew = f
+	}
+}
+
+//OnAirDateChange calls f with every AirDateChanged event.
+func OnAirDateChange(f func(Event)) Option {
+	return func(t *Tracker) {
+		t.onAirDate = f
+	}
+}
+
+//OnSeriesChange calls f with every SeriesChanged event.
+func OnSeriesChange(f func(Event)) Option {
+	return func(t *Tracker) {
+		t.onSeries = f
+	}
+}
+
+//OnError calls f with the errors of the checks of Run, which otherwise
+//ignores them.
+func OnError(f func(error)) Option {
+	return func(t *Tracker) {
+		t.onError = f
+	}
+}
+
+//Tracker follows series. It is safe for concurrent use, the callbacks are
+//called one at a time from the goroutine running Check.
+type Tracker struct {
+	store  omdb.Cache
+	key    string
+	client omdb.API
+
+	onNew     func(Event)
+	onAirDate func(Event)
+	onSeries  func(Event)
+	onError   func(error)
+
+	mu     sync.Mutex
+	series map[string]*Series
+	//checking serializes Check.
+	checking sync.Mutex
+}
+
+//Open loads the series followed under name in store, none when there are no
+//series stored yet. The series are looked up with c.
+func Open(ctx context.Context, store omdb.Cache, name string, c omdb.API, opts ...Option) (*Tracker, error) {
+
+	t := &Tracker{store: store, key: "tracker:" + name, client: c, series: make(map[string]*Series)}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	data, err := store.Get(ctx, t.key)
+	if errors.Is(err, omdb.ErrCacheMiss) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []*Series
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, errors.New("tracker: Invalid tracker " + name + ": " + err.Error())
+	}
+	for _, s := range list {
+		t.series[s.ImdbID] = s
+	}
+	return t, nil
+}
+
+//Follow starts following the series of id, listing its episodes so far
+//without reporting them as new. A series already followed is returned as it
+//is.
+func (t *Tracker) Follow(ctx context.Context, id string) (Series, error) {
+
+	series, err := t.client.GetSeriesByID(ctx, id)
+	if err != nil {
+		return Series{}, err
+	}
+	t.mu.Lock()
+	if s, ok := t.series[series.ImdbID]; ok {
+		t.mu.Unlock()
+		return s.clone(), nil
+	}
+	t.mu.Unlock()
+
+	s := &Series{ImdbID: series.ImdbID, Title: series.Title, Result: series}
+	s.TotalSeasons, _ = strconv.Atoi(series.TotalSeasons)
+	for season := 1; season <= s.TotalSeasons; season++ {
+		episodes, err := t.season(ctx, s.ImdbID, season)
+		if err != nil {
+			return Series{}, err
+		}
+		s.Episodes = append(s.Episodes, episodes...)
+	}
+	s.Checked = time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.series[s.ImdbID] = s
+	return s.clone(), t.save(ctx)
+}
+
+//Unfollow stops following the series of id.
+func (t *Tracker) Unfollow(ctx context.Context, id string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n, err := omdb.NormalizeImdbID(id); err == nil {
+		id = n
+	}
+	if _, ok := t.series[id]; !ok {
+		return nil
+	}
+	delete(t.series, id)
+	return t.save(ctx)
+}
+
+//List returns the followed series, ordered by title.
+func (t *Tracker) List() []Series {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list := make([]Series, 0, len(t.series))
+	for _, s := range t.series {
+		list = append(list, s.clone())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Title < list[j].Title })
+	return list
+}
+
+//Check looks every followed series up again, bypassing the cache, and calls
+//the callbacks with the episodes which appeared, the air dates which changed
+//and the fields of the series which changed since the last check, see
+//omdb.Diff. Only the last season known and the seasons added since are
+//requested, earlier seasons being settled. Failed lookups leave their series
+//as it was and are joined into the returned error.
+func (t *Tracker) Check(ctx context.Context) error {
+
+	t.checking.Lock()
+	defer t.checking.Unlock()
+
+	var errs []error
+	list := t.List()
+	for i := range list {
+		s := &list[i]
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		events, err := t.check(ctx, s)
+		if err != nil {
+			errs = append(errs, &checkError{title: s.Title, err: err})
+			continue
+		}
+
+		t.mu.Lock()
+		if _, ok := t.series[s.ImdbID]; ok {
+			t.series[s.ImdbID] = s
+		}
+		t.mu.Unlock()
+
+		for _, e := range events {
+			t.emit(e)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.save(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+//check refreshes s, returning its events.
+func (t *Tracker) check(ctx context.Context, s *Series) ([]Event, error) {
+
+	series, err := t.client.GetSeriesByID(ctx, s.ImdbID, omdb.WithNoCache())
+	if err != nil {
+		return nil, err
+	}
+	total, _ := strconv.Atoi(series.TotalSeasons)
+	if total < s.TotalSeasons {
+		total = s.TotalSeasons
+	}
+	first := s.TotalSeasons
+	if first < 1 {
+		first = 1
+	}
+
+	known := make(map[[2]int]int, len(s.Episodes))
+	for i, e := range s.Episodes {
+		known[[2]int{e.Season, e.Episode}] = i
+	}
+
+	var events []Event
+	//series followed before their result was stored only get it now.
+	if s.Result != nil {
+		if changes := omdb.Diff(s.Result, series); len(changes) > 0 {
+			events = append(events, Event{Kind: SeriesChanged, Changes: changes})
+		}
+	}
+	for season := first; season <= total; season++ {
+		episodes, err := t.season(ctx, s.ImdbID, season, omdb.WithNoCache())
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range episodes {
+			i, ok := known[[2]int{e.Season, e.Episode}]
+			if !ok {
+				s.Episodes = append(s.Episodes, e)
+				events = append(events, Event{Kind: NewEpisode, Episode: e})
+				continue
+			}
+			old := s.Episodes[i]
+			if e.Released != "" && e.Released != old.Released {
+				events = append(events, Event{Kind: AirDateChanged, Episode: e, PreviousReleased: old.Released})
+			}
+			if e.Released == "" {
+				e.Released = old.Released
+			}
+			s.Episodes[i] = e
+		}
+	}
+
+	s.Title, s.TotalSeasons, s.Checked, s.Result = series.Title, total, time.Now(), series
+	sort.Slice(s.Episodes, func(i, j int) bool {
+		a, b := s.Episodes[i], s.Episodes[j]
+		return a.Season < b.Season || a.Season == b.Season && a.Episode < b.Episode
+	})
+	for i := range events {
+		events[i].SeriesID, events[i].SeriesTitle = s.ImdbID, s.Title
+	}
+	return events, nil
+}
+
+//Run checks the followed series every interval until ctx is done, starting
+//right away, and returns the error of ctx. The errors of the checks are
+//passed to the OnError callback.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) error {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.Check(ctx); err != nil && ctx.Err() == nil && t.onError != nil {
+			t.onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+//season returns the episodes of a season, with their numbers.
+func (t *Tracker) season(ctx context.Context, id string, season int, opts ...omdb.CallOption) ([]Episode, error) {
+
+	res, err := t.client.GetSeason(ctx, id, season, opts...)
+	if errors.Is(err, omdb.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]Episode, 0, len(res.Episodes))
+	for _, e := range res.Episodes {
+		n, err := strconv.Atoi(e.Episode)
+		if err != nil {
+			continue
+		}
+		episodes = append(episodes, Episode{
+			Season:   season,
+			Episode:  n,
+			Title:    e.Title,
+			ImdbID:   na(e.ImdbID),
+			Released: na(e.Released),
+		})
+	}
+	return episodes, nil
+}
+
+func (t *Tracker) emit(e Event) {
+	switch {
+	case e.Kind == NewEpisode && t.onNew != nil:
+		t.onNew(e)
+	case e.Kind == AirDateChanged && t.onAirDate != nil:
+		t.onAirDate(e)
+	case e.Kind == SeriesChanged && t.onSeries != nil:
+		t.onSeries(e)
+	}
+}
+
+//save stores the followed series, t.mu is held.
+func (t *Tracker) save(ctx context.Context) error {
+
+	list := make([]*Series, 0, len(t.series))
+	for _, s := range t.series {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ImdbID < list[j].ImdbID })
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return t.store.Set(ctx, t.key, data, retention)
+}
+
+func (s *Series) clone() Series {
+	c := *s
+	c.Episodes = append([]Episode(nil), s.Episodes...)
+	return c
+}
+
+//na maps OMDB's "N/A" placeholder to an empty string.
+func na(s string) string {
+	if s == "N/A" {
+		return ""
+	}
+	return s
+}
+
+//checkError is a failed check of a series, prefixed by its title.
+type checkError struct {
+	title string
+	err   error
+}
+
+func (e *checkError) Error() string { return "tracker: " + e.title + ": " + e.err.Error() }
+func (e *checkError) Unwrap() error { return e.err }
@@ -0,0 +1,80 @@
+package tracker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/tracker"
+)
+
+func TestCheckReportsChanges(t *testing.T) {
+
+	ctx := context.Background()
+	series := omdb.SeriesResult{Title: "Show", ImdbID: "tt0000001", TotalSeasons: "1", ImdbRating: "8.0", ImdbVotes: "100"}
+	votes, rating := series, series
+	votes.ImdbVotes = "150"
+	rating.ImdbVotes, rating.ImdbRating = "200", "8.3"
+	season := &omdb.SeasonResult{Episodes: []omdb.SeasonEpisode{
+		{Title: "Pilot", Episode: "1", Released: "01 Jan 2026", ImdbID: "tt0000002"},
+	}}
+	more := &omdb.SeasonResult{Episodes: []omdb.SeasonEpisode{
+		{Title: "Pilot", Episode: "1", Released: "02 Jan 2026", ImdbID: "tt0000002"},
+		{Title: "Second", Episode: "2", Released: "N/A", ImdbID: "tt0000003"},
+	}}
+
+	m := &omdb.MockClient{}
+	id := omdb.QueryData{ImdbID: series.ImdbID}
+	m.Handle(omdb.OpByID, id, series, nil)
+	m.Handle(omdb.OpByID, id, votes, nil)
+	m.Handle(omdb.OpByID, id, rating, nil)
+	s1 := omdb.QueryData{ImdbID: series.ImdbID, Season: "1"}
+	m.Handle(omdb.OpSeason, s1, season, nil)
+	m.Handle(omdb.OpSeason, s1, season, nil)
+	m.Handle(omdb.OpSeason, s1, more, nil)
+
+	var events []tracker.Event
+	record := func(e tracker.Event) { events = append(events, e) }
+	store := omdb.NewLRUCache(10)
+	tr, err := tracker.Open(ctx, store, "shows", m,
+		tracker.OnNewEpisode(record), tracker.OnAirDateChange(record), tracker.OnSeriesChange(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Follow(ctx, series.ImdbID); err != nil {
+		t.Fatal(err)
+	}
+
+	//only the votes changed.
+	if err := tr.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("got events %+v for a change of votes", events)
+	}
+
+	if err := tr.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events %+v, want 3", len(events), events)
+	}
+	if e := events[0]; e.Kind != tracker.SeriesChanged || len(e.Changes) != 1 || e.Changes[0].Field != "ImdbRating" || e.Changes[0].New != "8.3" {
+		t.Errorf("got %+v, want the rating change", e)
+	}
+	if e := events[1]; e.Kind != tracker.AirDateChanged || e.Episode.Released != "02 Jan 2026" || e.PreviousReleased != "01 Jan 2026" {
+		t.Errorf("got %+v, want the air date change", e)
+	}
+	if e := events[2]; e.Kind != tracker.NewEpisode || e.Episode.Code() != "S01E02" || e.Episode.Released != "" {
+		t.Errorf("got %+v, want the new episode", e)
+	}
+
+	//the followed series are stored.
+	reopened, err := tracker.Open(ctx, store, "shows", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list := reopened.List(); len(list) != 1 || len(list[0].Episodes) != 2 || list[0].Result.ImdbRating != "8.3" {
+		t.Errorf("reopened the series %+v", list)
+	}
+}
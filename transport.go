@@ -0,0 +1,36 @@
+package omdb
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+//defaultHTTPClient is shared by the clients created without an http.Client,
+//so they share its pool of connections.
+var defaultHTTPClient = DefaultHTTPClient()
+
+//DefaultHTTPClient returns the kind of http.Client used by NewClient and New
+//when none is given: connections are pooled and kept alive, connecting, the
+//TLS handshake and waiting for the response headers time out, and the proxy
+//is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+//variables. The http.Client itself has no Timeout, so DefaultTimeout or
+//WithTimeout bound the whole request.
+func DefaultHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 20 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package omdb
+
+import "context"
+
+//GetMovieByID looks up a movie by its imdb id. An error is returned when the
+//id belongs to a series or an episode.
+func (c *Client) GetMovieByID(ctx context.Context, id string, opts ...CallOption) (*MovieResult, error) {
+	res, err := c.SearchByImdbIDContext(ctx, QueryData{ImdbID: id}, opts...)
+	return asMovie(res, err)
+}
+
+//GetMovieByTitle looks up a movie by title, q.SearchType is ignored.
+func (c *Client) GetMovieByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*MovieResult, error) {
+	q.SearchType = TypeMovie
+	res, err := c.SearchByTitleContext(ctx, q, opts...)
+	return asMovie(res, err)
+}
+
+//GetSeriesByID looks up a series by its imdb id. An error is returned when the
+//id belongs to a movie or an episode.
+func (c *Client) GetSeriesByID(ctx context.Context, id string, opts ...CallOption) (*SeriesResult, error) {
+	res, err := c.SearchByImdbIDContext(ctx, QueryData{ImdbID: id}, opts...)
+	return asSeries(res, err)
+}
+
+//GetSeriesByTitle looks up a series by title, q.SearchType is ignored.
+func (c *Client) GetSeriesByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*SeriesResult, error) {
+	q.SearchType = TypeSeries
+	res, err := c.SearchByTitleContext(ctx, q, opts...)
+	return asSeries(res, err)
+}
+
+//GetEpisodeByID looks up an episode by its imdb id. An error is returned when
+//the id belongs to a movie or a series.
+func (c *Client) GetEpisodeByID(ctx context.Context, id string, opts ...CallOption) (*EpisodeResult, error) {
+	res, err := c.SearchByImdbIDContext(ctx, QueryData{ImdbID: id}, opts...)
+	return asEpisode(res, err)
+}
+
+//GetEpisodeByTitle looks up an episode by title, q.SearchType is ignored.
+func (c *Client) GetEpisodeByTitle(ctx context.Context, q QueryData, opts ...CallOption) (*EpisodeResult, error) {
+	q.SearchType = TypeEpisode
+	res, err := c.SearchByTitleContext(ctx, q, opts...)
+	return asEpisode(res, err)
+}
+
+//Get looks up a single movie, series or episode by q.ImdbID when set,
+//otherwise by q.Title, and returns it as a T. Title lookups are restricted to
+//the type of T; an id of another type is an error matching ErrTypeMismatch.
+func Get[T MovieResult | SeriesResult | EpisodeResult](ctx context.Context, c *Client, q QueryData, opts ...CallOption) (*T, error) {
+
+	want := TypeOf(new(T))
+	var (
+		res interface{}
+		err error
+	)
+	if q.ImdbID != "" {
+		res, err = c.SearchByImdbIDContext(ctx, q, opts...)
+	} else {
+		q.SearchType = SearchType(want)
+		res, err = c.SearchByTitleContext(ctx, q, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := res.(T)
+	if !ok {
+		return nil, typeMismatch(want, res)
+	}
+	return &val, nil
+}
+
+func asMovie(res interface{}, err error) (*MovieResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	movie, ok := res.(MovieResult)
+	if !ok {
+		return nil, typeMismatch(string(TypeMovie), res)
+	}
+	return &movie, nil
+}
+
+func asSeries(res interface{}, err error) (*SeriesResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	series, ok := res.(SeriesResult)
+	if !ok {
+		return nil, typeMismatch(string(TypeSeries), res)
+	}
+	return &series, nil
+}
+
+func asEpisode(res interface{}, err error) (*EpisodeResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	episode, ok := res.(EpisodeResult)
+	if !ok {
+		return nil, typeMismatch(string(TypeEpisode), res)
+	}
+	return &episode, nil
+}
+
+func typeMismatch(want string, res interface{}) error {
+	return &wrappedError{msg: "omdb: Expected a " + want + " but got " + TypeOf(res), err: ErrTypeMismatch}
+}
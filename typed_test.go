@@ -0,0 +1,95 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+//typedServer is a fake server with a movie, a series and an episode, the
+//series and the episode sharing their title.
+func typedServer(t *testing.T) *omdbtest.Server {
+
+	t.Helper()
+	srv := omdbtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Sherlock", Year: "2010–2017", ImdbID: "tt1475582", TotalSeasons: "4"})
+	srv.AddEpisode(omdb.EpisodeResult{Title: "Sherlock", Year: "2010", ImdbID: "tt9999999", SeriesID: "tt1475582"})
+	return srv
+}
+
+func TestGet(t *testing.T) {
+
+	ctx := context.Background()
+	client := typedServer(t).Client()
+
+	movie, err := omdb.Get[omdb.MovieResult](ctx, client, omdb.QueryData{ImdbID: "tt0133093"})
+	if err != nil || movie.Title != "The Matrix" {
+		t.Errorf("got %+v, %v", movie, err)
+	}
+	//title lookups are restricted to the type of T.
+	series, err := omdb.Get[omdb.SeriesResult](ctx, client, omdb.QueryData{Title: "Sherlock"})
+	if err != nil || series.ImdbID != "tt1475582" || series.TotalSeasons != "4" {
+		t.Errorf("got %+v, %v", series, err)
+	}
+	episode, err := omdb.Get[omdb.EpisodeResult](ctx, client, omdb.QueryData{Title: "Sherlock"})
+	if err != nil || episode.ImdbID != "tt9999999" {
+		t.Errorf("got %+v, %v", episode, err)
+	}
+
+	if _, err := omdb.Get[omdb.SeriesResult](ctx, client, omdb.QueryData{ImdbID: "tt0133093"}); !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("got error %v for the id of a movie, want ErrTypeMismatch", err)
+	}
+	if _, err := omdb.Get[omdb.MovieResult](ctx, client, omdb.QueryData{Title: "Sherlock"}); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v for the title of a series, want ErrNotFound", err)
+	}
+}
+
+func TestTypedLookups(t *testing.T) {
+
+	ctx := context.Background()
+	client := typedServer(t).Client()
+
+	if movie, err := client.GetMovieByID(ctx, "tt0133093"); err != nil || movie.Title != "The Matrix" {
+		t.Errorf("GetMovieByID: got %+v, %v", movie, err)
+	}
+	if _, err := client.GetMovieByID(ctx, "tt1475582"); !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("GetMovieByID: got error %v for a series, want ErrTypeMismatch", err)
+	}
+	if movie, err := client.GetMovieByTitle(ctx, omdb.QueryData{Title: "The Matrix", SearchType: omdb.TypeSeries}); err != nil || movie.ImdbID != "tt0133093" {
+		t.Errorf("GetMovieByTitle: got %+v, %v", movie, err)
+	}
+	if _, err := client.GetMovieByTitle(ctx, omdb.QueryData{Title: "Sherlock"}); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("GetMovieByTitle: got error %v, want ErrNotFound", err)
+	}
+
+	if series, err := client.GetSeriesByID(ctx, "tt1475582"); err != nil || series.Title != "Sherlock" {
+		t.Errorf("GetSeriesByID: got %+v, %v", series, err)
+	}
+	if _, err := client.GetSeriesByID(ctx, "tt9999999"); !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("GetSeriesByID: got error %v for an episode, want ErrTypeMismatch", err)
+	}
+	if series, err := client.GetSeriesByTitle(ctx, omdb.QueryData{Title: "Sherlock"}); err != nil || series.ImdbID != "tt1475582" {
+		t.Errorf("GetSeriesByTitle: got %+v, %v", series, err)
+	}
+	if _, err := client.GetSeriesByTitle(ctx, omdb.QueryData{Title: "The Matrix"}); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("GetSeriesByTitle: got error %v, want ErrNotFound", err)
+	}
+
+	if episode, err := client.GetEpisodeByID(ctx, "tt9999999"); err != nil || episode.SeriesID != "tt1475582" {
+		t.Errorf("GetEpisodeByID: got %+v, %v", episode, err)
+	}
+	if _, err := client.GetEpisodeByID(ctx, "tt0133093"); !errors.Is(err, omdb.ErrTypeMismatch) {
+		t.Errorf("GetEpisodeByID: got error %v for a movie, want ErrTypeMismatch", err)
+	}
+	if episode, err := client.GetEpisodeByTitle(ctx, omdb.QueryData{Title: "Sherlock"}); err != nil || episode.ImdbID != "tt9999999" {
+		t.Errorf("GetEpisodeByTitle: got %+v, %v", episode, err)
+	}
+	if _, err := client.GetEpisodeByTitle(ctx, omdb.QueryData{Title: "The Matrix"}); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("GetEpisodeByTitle: got error %v, want ErrNotFound", err)
+	}
+}
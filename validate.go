@@ -0,0 +1,128 @@
+package omdb
+
+import (
+	"strconv"
+	"strings"
+)
+
+//FieldError is an invalid field of a QueryData.
+type FieldError struct {
+	//Field is the name of the QueryData field, e.g. "Year".
+	Field   string
+	Message string
+}
+
+//ValidationError lists every invalid field of a QueryData, so they can all be
+//shown at once. It matches ErrInvalidRequest.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error { return ErrInvalidRequest }
+
+func (e *ValidationError) add(field, msg string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: msg})
+}
+
+//Validate checks every field set in q and returns a ValidationError listing
+//the invalid ones, or nil. q needs an ImdbID or a Title; which other fields
+//are required depends on the method q is passed to, which checks them again.
+func (q QueryData) Validate() error {
+	return q.validate("")
+}
+
+//validate checks the fields op requires and sends, or every field set when
+//op is "", the way the lookup methods do before requesting it.
+func (q QueryData) validate(op string) error {
+
+	e := &ValidationError{}
+	var filters, plot, page, season, episode bool
+	switch op {
+	case "":
+		if q.ImdbID == "" && q.Title == "" {
+			e.add("Title", "omdb: ImdbID or Title is missing")
+		}
+		filters, plot, page = true, true, true
+		season, episode = q.Season != "" || q.Episode != "", q.Episode != ""
+	case OpByID:
+		if q.ImdbID == "" {
+			e.add("ImdbID", "Missing ImdbID in query")
+		}
+		plot = true
+	case OpByTitle:
+		if q.Title == "" {
+			e.add("Title", "omdb: Title is missing")
+		}
+		filters, plot = true, true
+	case OpSearch:
+		if q.Title == "" {
+			e.add("Title", "omdb: Text to search (Title) is missing")
+		}
+		filters, page = true, true
+	case OpSeason:
+		if q.ImdbID == "" {
+			e.add("ImdbID", "omdb: Series ImdbID is missing")
+		}
+		season = true
+	case OpEpisode:
+		if q.ImdbID == "" {
+			e.add("ImdbID", "omdb: Series ImdbID is missing")
+		}
+		season, episode, plot = true, true, true
+	default:
+		return invalidRequest("omdb: Unknown operation " + op)
+	}
+
+	if q.ImdbID != "" && op != OpByTitle && op != OpSearch {
+		if _, err := NormalizeImdbID(q.ImdbID); err != nil {
+			e.add("ImdbID", err.Error())
+		}
+	}
+	if filters {
+		if q.SearchType != "" && !q.SearchType.Valid() {
+			e.add("SearchType", "omdb: Searchtype should be either blank or one of following: movie, series, episode")
+		}
+		if q.Year != "" {
+			if i, err := strconv.Atoi(q.Year); err != nil {
+				e.add("Year", "omdb: Year should be either blank or a valid number")
+			} else if i < 1888 {
+				e.add("Year", "omdb: Year should be either blank or greater than 1887")
+			}
+		}
+	}
+	if plot && q.Plot != "" && !q.Plot.Valid() {
+		e.add("Plot", "omdb: Plot should be either blank or one of following: short, full")
+	}
+	if page && q.Page != "" {
+		if i, err := strconv.Atoi(q.Page); err != nil {
+			e.add("Page", "omdb: Page should be either blank or a valid number")
+		} else if i < 1 || i > 100 {
+			e.add("Page", "omdb: Page should be either blank or between 1 to 100 (inclusive of both)")
+		}
+	}
+	if season && !positive(q.Season) {
+		e.add("Season", "omdb: Season should be greater than 0")
+	}
+	if episode && !positive(q.Episode) {
+		e.add("Episode", "omdb: Episode should be greater than 0")
+	}
+
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+//positive reports whether s is a number greater than 0.
+func positive(s string) bool {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	return err == nil && i > 0
+}
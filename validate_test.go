@@ -0,0 +1,56 @@
+package omdb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahin/omdb"
+)
+
+func TestValidate(t *testing.T) {
+
+	for _, tt := range []struct {
+		name   string
+		q      omdb.QueryData
+		fields string
+	}{
+		{"valid title", omdb.QueryData{Title: "Dune", Year: "2021", SearchType: omdb.TypeMovie, Plot: omdb.PlotFull, Page: "2"}, ""},
+		{"valid episode", omdb.QueryData{ImdbID: "tt0903747", Season: "1", Episode: "2"}, ""},
+		{"no id or title", omdb.QueryData{Year: "2021"}, "Title"},
+		{"invalid id", omdb.QueryData{ImdbID: "matrix"}, "ImdbID"},
+		{"type", omdb.QueryData{Title: "Dune", SearchType: "game"}, "SearchType"},
+		{"year not a number", omdb.QueryData{Title: "Dune", Year: "next"}, "Year"},
+		{"year too early", omdb.QueryData{Title: "Dune", Year: "1887"}, "Year"},
+		{"plot", omdb.QueryData{Title: "Dune", Plot: "long"}, "Plot"},
+		{"page not a number", omdb.QueryData{Title: "Dune", Page: "first"}, "Page"},
+		{"page out of range", omdb.QueryData{Title: "Dune", Page: "101"}, "Page"},
+		{"season", omdb.QueryData{ImdbID: "tt0903747", Season: "0"}, "Season"},
+		{"episode", omdb.QueryData{ImdbID: "tt0903747", Season: "1", Episode: "x"}, "Episode"},
+		{"episode without a season", omdb.QueryData{ImdbID: "tt0903747", Episode: "2"}, "Season"},
+		{"every invalid field", omdb.QueryData{Year: "0", Plot: "long", Page: "0"}, "Title, Year, Plot, Page"},
+	} {
+		err := tt.q.Validate()
+		if tt.fields == "" {
+			if err != nil {
+				t.Errorf("%s: got error %v", tt.name, err)
+			}
+			continue
+		}
+		var verr *omdb.ValidationError
+		if !errors.As(err, &verr) || !errors.Is(err, omdb.ErrInvalidRequest) {
+			t.Errorf("%s: got error %v, want a ValidationError", tt.name, err)
+			continue
+		}
+		var fields []string
+		for _, f := range verr.Fields {
+			fields = append(fields, f.Field)
+			if f.Message == "" {
+				t.Errorf("%s: %s has no message", tt.name, f.Field)
+			}
+		}
+		if got := strings.Join(fields, ", "); got != tt.fields {
+			t.Errorf("%s: got invalid fields %s, want %s", tt.name, got, tt.fields)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package omdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//Warm looks up every id so that the cache of the client holds them before
+//they are needed, e.g. ahead of a batch rendering job. Lookups are made one
+//after the other, waiting interval between two requests; failed lookups are
+//joined into the returned error. It requires WithCache.
+func (c *Client) Warm(ctx context.Context, ids []string, interval time.Duration) error {
+
+	queries := make([]QueryData, len(ids))
+	for i, id := range ids {
+		queries[i] = QueryData{ImdbID: id}
+	}
+	return c.Prefetch(ctx, queries, interval)
+}
+
+//Prefetch is like Warm for arbitrary lookups: a query with an ImdbID is looked
+//up like SearchByImdbID, any other like SearchByTitle.
+func (c *Client) Prefetch(ctx context.Context, queries []QueryData, interval time.Duration) error {
+
+	if c.cache == nil {
+		return errors.New("omdb: Prefetch requires a cache, see WithCache")
+	}
+
+	var errs []error
+	for i, q := range queries {
+		if i > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+		}
+
+		var err error
+		if q.ImdbID != "" {
+			_, err = c.SearchByImdbIDContext(ctx, q)
+		} else {
+			_, err = c.SearchByTitleContext(ctx, q)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
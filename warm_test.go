@@ -0,0 +1,55 @@
+package omdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestWarm(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "The Matrix", ImdbID: "tt0133093"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Breaking Bad", ImdbID: "tt0903747"})
+
+	ctx := context.Background()
+	cache := omdb.NewLRUCache(10)
+	client := srv.Client(omdb.WithCache(cache, 0))
+
+	err := client.Warm(ctx, []string{"tt0133093", "tt0903747", "tt0000001"}, time.Millisecond)
+	if !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want the unknown id reported", err)
+	}
+	for _, id := range []string{"tt0133093", "tt0903747"} {
+		if _, err := cache.Get(ctx, client.CacheKey(omdb.QueryData{ImdbID: id}, omdb.OpByID)); err != nil {
+			t.Errorf("%s not cached: %v", id, err)
+		}
+	}
+
+	err = client.Prefetch(ctx, []omdb.QueryData{{Title: "The Matrix"}, {ImdbID: "tt0133093"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, client.CacheKey(omdb.QueryData{Title: "The Matrix"}, omdb.OpByTitle)); err != nil {
+		t.Errorf("title not cached: %v", err)
+	}
+	if srv.Requests() != 4 {
+		t.Errorf("got %d requests, want the cached id not requested again", srv.Requests())
+	}
+
+	//a done context stops between two lookups.
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := client.Warm(done, []string{"tt0133093", "tt0903747"}, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+
+	if err := srv.Client().Warm(ctx, []string{"tt0133093"}, 0); err == nil {
+		t.Error("got no error without a cache")
+	}
+}
@@ -0,0 +1,84 @@
+package omdb
+
+import (
+	"context"
+	"reflect"
+)
+
+//SearchByImdbIDWithWarnings works like SearchByImdbID and also returns
+//non-fatal warnings about the result, see SearchByTitleWithWarnings.
+func (c *Client) SearchByImdbIDWithWarnings(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, []string, error) {
+
+	res, err := c.SearchByImdbIDContext(ctx, q, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, resultWarnings(q, res), nil
+}
+
+//SearchByTitleWithWarnings works like SearchByTitle and also returns non-fatal
+//warnings about the result: a Type which differs from the requested
+//SearchType, and fields such as Plot which OMDB reports as "N/A". A nil slice
+//means there is nothing to report.
+func (c *Client) SearchByTitleWithWarnings(ctx context.Context, q QueryData, opts ...CallOption) (interface{}, []string, error) {
+
+	if q.SearchType == "" {
+		q.SearchType = c.defaultSearchType
+	}
+	res, err := c.SearchByTitleContext(ctx, q, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, resultWarnings(q, res), nil
+}
+
+//SearchByTextWithWarnings works like SearchByText and also returns non-fatal
+//warnings about the results of the page: results of another Type than the
+//requested SearchType, and results without Title, Year or Poster. Each
+//warning names the ImdbID of its result.
+func (c *Client) SearchByTextWithWarnings(ctx context.Context, q QueryData, opts ...CallOption) (*SearchResponse, []string, error) {
+
+	if q.SearchType == "" {
+		q.SearchType = c.defaultSearchType
+	}
+	res, err := c.SearchByTextContext(ctx, q, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for _, r := range res.Search {
+		if q.SearchType != "" && string(q.SearchType) != r.Type {
+			warnings = append(warnings, "omdb: "+r.ImdbID+": requested type "+string(q.SearchType)+" but got "+r.Type)
+		}
+		for _, field := range []struct{ name, value string }{{"Title", r.Title}, {"Year", r.Year}, {"Poster", r.Poster}} {
+			if field.value == "N/A" || field.value == "" {
+				warnings = append(warnings, "omdb: "+r.ImdbID+": "+field.name+" is not available")
+			}
+		}
+	}
+	return res, warnings, nil
+}
+
+//resultWarnings lists the soft issues of res given the query it answers.
+func resultWarnings(q QueryData, res interface{}) []string {
+
+	var warnings []string
+
+	typ := TypeOf(res)
+	if typ == "unknown" {
+		return []string{"omdb: result has an unknown type"}
+	}
+	if q.SearchType != "" && string(q.SearchType) != typ {
+		warnings = append(warnings, "omdb: requested type "+string(q.SearchType)+" but got "+typ)
+	}
+
+	v := reflect.ValueOf(res)
+	for _, field := range []string{"Title", "Year", "Plot", "Poster"} {
+		if s := v.FieldByName(field).String(); s == "N/A" || s == "" {
+			warnings = append(warnings, "omdb: "+field+" is not available")
+		}
+	}
+
+	return warnings
+}
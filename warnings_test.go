@@ -0,0 +1,49 @@
+package omdb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+)
+
+func TestWarnings(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "Dune", Year: "2021", ImdbID: "tt1160419", Plot: "N/A", Poster: "https://example.com/dune.jpg"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Dune: Prophecy", Year: "2024-", ImdbID: "tt10466872", Plot: "Sisters.", Poster: "N/A"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	_, warnings, err := client.SearchByImdbIDWithWarnings(ctx, omdb.QueryData{ImdbID: "tt1160419"}, omdb.WithFullPlot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"omdb: Plot is not available"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+
+	//the call options apply.
+	if _, _, err := client.SearchByTitleWithWarnings(ctx, omdb.QueryData{Title: "Dune"}, omdb.WithCallAPIKey("wrong")); err == nil {
+		t.Error("got no error with a wrong API key")
+	}
+	if _, _, err := client.SearchByTextWithWarnings(ctx, omdb.QueryData{Title: "dune"}, omdb.WithCallAPIKey("wrong")); err == nil {
+		t.Error("got no error with a wrong API key")
+	}
+
+	_, warnings, err = client.SearchByTitleWithWarnings(ctx, omdb.QueryData{Title: "Dune"})
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("got warnings %q, %v", warnings, err)
+	}
+
+	res, warnings, err := client.SearchByTextWithWarnings(ctx, omdb.QueryData{Title: "dune"})
+	if err != nil || len(res.Search) != 2 {
+		t.Fatalf("got %+v, %v", res, err)
+	}
+	if want := []string{"omdb: tt10466872: Poster is not available"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+}
@@ -0,0 +1,381 @@
+//Package watchlist keeps a list of titles to watch, with tags, persisted in
+//any omdb.Cache, like those of the rediscache, boltcache or sqlitecache
+//packages, so the list lives next to the cached responses:
+//
+//	list, err := watchlist.Open(ctx, cache, "mine")
+//	...
+//	_, err = list.Add(ctx, client, "tt15239678", "sci-fi")
+//	...
+//	changes, err := list.Refresh(ctx, client)
+//	for _, c := range changes {
+//		if c.New() {
+//			fmt.Println(c.Title, c.Field, "is now", c.To)
+//		}
+//	}
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ahin/omdb"
+)
+
+//retention is how long the list is stored, the caches expire every entry.
+const retention = 100 * 365 * 24 * time.Hour
+
+//Fields of an Entry compared by Refresh.
+const (
+	FieldReleased       = "Released"
+	FieldDVD            = "DVD"
+	FieldImdbRating     = "ImdbRating"
+	FieldMetascore      = "Metascore"
+	FieldRottenTomatoes = "RottenTomatoes"
+	FieldPoster         = "Poster"
+)
+
+//Entry is a title of a watchlist with the metadata it had when it was last
+//refreshed. Fields OMDB reports as "N/A" are empty.
+type Entry struct {
+	ImdbID string
+	Title  string
+	Type   string
+	Year   string
+	Tags   []string `json:",omitempty"`
+	Added  time.Time
+
+	Released       string `json:",omitempty"`
+	DVD            string `json:",omitempty"`
+	ImdbRating     string `json:",omitempty"`
+	Metascore      string `json:",omitempty"`
+	RottenTomatoes string `json:",omitempty"`
+	Poster         string `json:",omitempty"`
+	Refreshed      time.Time
+}
+
+//HasTag reports whether the entry is tagged with tag.
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+//Change is a field of an entry which changed on Refresh.
+type Change struct {
+	ImdbID string
+	Title  string
+	Field  string
+	From   string
+	To     string
+}
+
+//New reports whether the field wasn't available before, like the first
+//ratings of a title after its release.
+func (c Change) New() bool {
+	return c.From == ""
+}
+
+//Watchlist is a list of titles stored under a name in a cache. It is safe
+//for concurrent use; changes are saved before the methods making them
+//return.
+type Watchlist struct {
+	mu      sync.Mutex
+	store   omdb.Cache
+	key     string
+	entries map[string]*Entry
+}
+
+//Open loads the watchlist stored in store under name, an empty one when
+//there is none yet.
+func Open(ctx context.Context, store omdb.Cache, name string) (*Watchlist, error) {
+
+	w := &Watchlist{store: store, key: "watchlist:" + name, entries: make(map[string]*Entry)}
+	data, err := store.Get(ctx, w.key)
+	if errors.Is(err, omdb.ErrCacheMiss) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, errors.New("watchlist: Invalid watchlist " + name + ": " + err.Error())
+	}
+	for _, e := range entries {
+		w.entries[e.ImdbID] = e
+	}
+	return w, nil
+}
+
+//Add looks up id with c and adds the title to the list with tags. A title
+//already in the list gets the tags added to its own and its metadata
+//refreshed.
+func (w *Watchlist) Add(ctx context.Context, c omdb.API, id string, tags ...string) (Entry, error) {
+
+	res, err := c.SearchByImdbIDContext(ctx, omdb.QueryData{ImdbID: id})
+	if err != nil {
+		return Entry{}, err
+	}
+	fresh := newEntry(res)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.entries[fresh.ImdbID]
+	if !ok {
+		e = &fresh
+		e.Added = fresh.Refreshed
+		w.entries[e.ImdbID] = e
+	} else {
+		e.update(fresh)
+	}
+	e.Tags = addTags(e.Tags, tags)
+	return *e, w.save(ctx)
+}
+
+//Remove removes the title of id from the list, if it is in it.
+func (w *Watchlist) Remove(ctx context.Context, id string) error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id = normalize(id)
+	if _, ok := w.entries[id]; !ok {
+		return nil
+	}
+	delete(w.entries, id)
+	return w.save(ctx)
+}
+
+//Get returns the entry of id, ok is false when it isn't in the list.
+func (w *Watchlist) Get(id string) (e Entry, ok bool) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if entry, ok := w.entries[normalize(id)]; ok {
+		return entry.clone(), true
+	}
+	return Entry{}, false
+}
+
+//List returns the entries tagged with every tag in tags, all of them when
+//there are none, in the order they were added.
+func (w *Watchlist) List(tags ...string) []Entry {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var list []Entry
+entries:
+	for _, e := range w.entries {
+		for _, tag := range tags {
+			if !e.HasTag(tag) {
+				continue entries
+			}
+		}
+		list = append(list, e.clone())
+	}
+	sortEntries(list)
+	return list
+}
+
+//Tag adds tags to the entry of id.
+func (w *Watchlist) Tag(ctx context.Context, id string, tags ...string) error {
+	return w.modify(ctx, id, func(e *Entry) {
+		e.Tags = addTags(e.Tags, tags)
+	})
+}
+
+//Untag removes tags from the entry of id.
+func (w *Watchlist) Untag(ctx context.Context, id string, tags ...string) error {
+	return w.modify(ctx, id, func(e *Entry) {
+		kept := e.Tags[:0]
+		for _, t := range e.Tags {
+			if !contains(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+		e.Tags = kept
+	})
+}
+
+func (w *Watchlist) modify(ctx context.Context, id string, f func(*Entry)) error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.entries[normalize(id)]
+	if !ok {
+		return errors.New("watchlist: " + id + " is not in the list")
+	}
+	f(e)
+	return w.save(ctx)
+}
+
+//Refresh looks up every entry again with c, bypassing its cache, and returns
+//the changes of their release dates, ratings and posters, see Change.New for
+//those which just became available. Failed lookups leave their entry as it
+//was and are joined into the returned error.
+func (w *Watchlist) Refresh(ctx context.Context, c omdb.API, opts ...omdb.CallOption) ([]Change, error) {
+
+	opts = append([]omdb.CallOption{omdb.WithNoCache()}, opts...)
+	var (
+		changes []Change
+		errs    []error
+	)
+	for _, e := range w.List() {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		res, err := c.SearchByImdbIDContext(ctx, omdb.QueryData{ImdbID: e.ImdbID}, opts...)
+		if err != nil {
+			errs = append(errs, &lookupError{id: e.ImdbID, err: err})
+			continue
+		}
+		fresh := newEntry(res)
+
+		w.mu.Lock()
+		if entry, ok := w.entries[e.ImdbID]; ok {
+			changes = append(changes, entry.update(fresh)...)
+		}
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.save(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return changes, errors.Join(errs...)
+}
+
+//save stores the list, w.mu is held.
+func (w *Watchlist) save(ctx context.Context) error {
+
+	list := make([]Entry, 0, len(w.entries))
+	for _, e := range w.entries {
+		list = append(list, *e)
+	}
+	sortEntries(list)
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return w.store.Set(ctx, w.key, data, retention)
+}
+
+//update sets the metadata of e to those of fresh, returning the changes.
+func (e *Entry) update(fresh Entry) []Change {
+
+	var changes []Change
+	for _, f := range []struct {
+		name     string
+		old, new *string
+	}{
+		{FieldReleased, &e.Released, &fresh.Released},
+		{FieldDVD, &e.DVD, &fresh.DVD},
+		{FieldImdbRating, &e.ImdbRating, &fresh.ImdbRating},
+		{FieldMetascore, &e.Metascore, &fresh.Metascore},
+		{FieldRottenTomatoes, &e.RottenTomatoes, &fresh.RottenTomatoes},
+		{FieldPoster, &e.Poster, &fresh.Poster},
+	} {
+		//a field which is no longer reported is kept.
+		if *f.new != "" && *f.new != *f.old {
+			changes = append(changes, Change{ImdbID: e.ImdbID, Title: fresh.Title, Field: f.name, From: *f.old, To: *f.new})
+			*f.old = *f.new
+		}
+	}
+	e.Title, e.Type, e.Year, e.Refreshed = fresh.Title, fresh.Type, fresh.Year, fresh.Refreshed
+	return changes
+}
+
+func (e *Entry) clone() Entry {
+	c := *e
+	c.Tags = append([]string(nil), e.Tags...)
+	return c
+}
+
+func newEntry(res interface{}) Entry {
+
+	e := Entry{Refreshed: time.Now()}
+	var ratings []omdb.Rating
+	switch r := res.(type) {
+	case omdb.MovieResult:
+		e.ImdbID, e.Title, e.Type, e.Year = r.ImdbID, r.Title, string(omdb.TypeMovie), r.Year
+		e.Released, e.DVD, e.ImdbRating, e.Metascore = r.Released, r.DVD, r.ImdbRating, r.Metascore
+		e.Poster, ratings = r.Poster, r.Ratings
+	case omdb.SeriesResult:
+		e.ImdbID, e.Title, e.Type, e.Year = r.ImdbID, r.Title, string(omdb.TypeSeries), r.Year
+		e.Released, e.ImdbRating, e.Metascore = r.Released, r.ImdbRating, r.Metascore
+		e.Poster, ratings = r.Poster, r.Ratings
+	case omdb.EpisodeResult:
+		e.ImdbID, e.Title, e.Type, e.Year = r.ImdbID, r.Title, string(omdb.TypeEpisode), r.Year
+		e.Released, e.ImdbRating, e.Metascore = r.Released, r.ImdbRating, r.Metascore
+		e.Poster, ratings = r.Poster, r.Ratings
+	}
+	for _, r := range ratings {
+		if r.Kind() == omdb.SourceRottenTomatoes {
+			e.RottenTomatoes = r.Value
+		}
+	}
+	for _, f := range []*string{&e.Released, &e.DVD, &e.ImdbRating, &e.Metascore, &e.RottenTomatoes, &e.Poster} {
+		if *f == "N/A" {
+			*f = ""
+		}
+	}
+	return e
+}
+
+func sortEntries(list []Entry) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Added.Equal(list[j].Added) {
+			return list[i].Added.Before(list[j].Added)
+		}
+		return list[i].ImdbID < list[j].ImdbID
+	})
+}
+
+func addTags(tags, add []string) []string {
+	for _, t := range add {
+		if t != "" && !contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+//normalize returns id as the entries are keyed, see omdb.NormalizeImdbID.
+func normalize(id string) string {
+	if n, err := omdb.NormalizeImdbID(id); err == nil {
+		return n
+	}
+	return id
+}
+
+//lookupError is a failed lookup of Refresh, prefixed by its id.
+type lookupError struct {
+	id  string
+	err error
+}
+
+func (e *lookupError) Error() string { return "watchlist: " + e.id + ": " + e.err.Error() }
+func (e *lookupError) Unwrap() error { return e.err }
@@ -0,0 +1,194 @@
+package watchlist_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/omdbtest"
+	"github.com/ahin/omdb/watchlist"
+)
+
+//ids returns the ImdbIDs of list.
+func ids(list []watchlist.Entry) []string {
+	var s []string
+	for _, e := range list {
+		s = append(s, e.ImdbID)
+	}
+	return s
+}
+
+func TestAddAndTags(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "Dune: Part Two", Year: "2024", ImdbID: "tt15239678", Released: "N/A", ImdbRating: "N/A"})
+	srv.AddSeries(omdb.SeriesResult{Title: "Severance", Year: "2022–", ImdbID: "tt11280740", ImdbRating: "8.7"})
+
+	ctx := context.Background()
+	client := srv.Client()
+	list, err := watchlist.Open(ctx, omdb.NewLRUCache(10), "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := list.Add(ctx, client, "tt15239678", "sci-fi", "cinema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Title != "Dune: Part Two" || e.Type != "movie" || e.Released != "" || e.ImdbRating != "" || e.Added.IsZero() {
+		t.Errorf("got %+v", e)
+	}
+	if _, err := list.Add(ctx, client, "11280740", "sci-fi"); err != nil {
+		t.Fatal(err)
+	}
+	//adding a title again merges its tags.
+	if e, err := list.Add(ctx, client, "tt15239678", "cinema", "imax"); err != nil || !reflect.DeepEqual(e.Tags, []string{"sci-fi", "cinema", "imax"}) {
+		t.Errorf("got tags %q, %v", e.Tags, err)
+	}
+	if _, err := list.Add(ctx, client, "tt0000001"); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+
+	if got := ids(list.List()); !reflect.DeepEqual(got, []string{"tt15239678", "tt11280740"}) {
+		t.Errorf("got %q in the order added", got)
+	}
+	if got := ids(list.List("sci-fi", "imax")); !reflect.DeepEqual(got, []string{"tt15239678"}) {
+		t.Errorf("got %q tagged sci-fi and imax", got)
+	}
+
+	if err := list.Tag(ctx, "tt11280740", "apple", "sci-fi"); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Untag(ctx, "tt15239678", "cinema", "drama"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := list.Get("tt11280740"); !reflect.DeepEqual(e.Tags, []string{"sci-fi", "apple"}) {
+		t.Errorf("got tags %q", e.Tags)
+	}
+	if e, _ := list.Get("tt15239678"); !reflect.DeepEqual(e.Tags, []string{"sci-fi", "imax"}) || !e.HasTag("imax") || e.HasTag("cinema") {
+		t.Errorf("got tags %q", e.Tags)
+	}
+	if err := list.Tag(ctx, "tt0000001", "drama"); err == nil {
+		t.Error("got no error tagging a title not in the list")
+	}
+
+	if err := list.Remove(ctx, "tt11280740"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := list.Get("tt11280740"); ok {
+		t.Error("got a removed entry")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	dune := omdb.MovieResult{Title: "Dune: Part Two", Year: "2024", ImdbID: "tt15239678", Released: "N/A", Poster: "https://m.media-amazon.com/dune.jpg", Metascore: "79"}
+	srv.AddMovie(dune)
+
+	ctx := context.Background()
+	cache := omdb.NewLRUCache(10)
+	client := srv.Client(omdb.WithCache(cache, 0))
+	list, err := watchlist.Open(ctx, cache, "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Add(ctx, client, "tt15239678"); err != nil {
+		t.Fatal(err)
+	}
+
+	//OMDB reports the release and first ratings, but no longer the
+	//Metascore and poster.
+	dune.Released, dune.ImdbRating, dune.Metascore, dune.Poster = "01 Mar 2024", "8.6", "N/A", "N/A"
+	dune.Ratings = []omdb.Rating{{Source: "Rotten Tomatoes", Value: "92%"}}
+	srv.AddMovie(dune)
+
+	changes, err := list.Refresh(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []watchlist.Change{
+		{ImdbID: "tt15239678", Title: "Dune: Part Two", Field: watchlist.FieldReleased, To: "01 Mar 2024"},
+		{ImdbID: "tt15239678", Title: "Dune: Part Two", Field: watchlist.FieldImdbRating, To: "8.6"},
+		{ImdbID: "tt15239678", Title: "Dune: Part Two", Field: watchlist.FieldRottenTomatoes, To: "92%"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got changes %+v, want %+v", changes, want)
+	}
+	for _, c := range changes {
+		if !c.New() {
+			t.Errorf("%s: got a change of a field which wasn't available", c.Field)
+		}
+	}
+	e, _ := list.Get("tt15239678")
+	if e.Metascore != "79" || e.Poster != "https://m.media-amazon.com/dune.jpg" || e.ImdbRating != "8.6" {
+		t.Errorf("got %+v, want the Metascore and poster kept", e)
+	}
+
+	//a refresh without change reports none, and the cache is bypassed.
+	requests := srv.Requests()
+	if changes, err := list.Refresh(ctx, client); err != nil || len(changes) != 0 {
+		t.Errorf("got %+v, %v", changes, err)
+	}
+	if srv.Requests() != requests+1 {
+		t.Errorf("got %d requests, want 1", srv.Requests()-requests)
+	}
+
+	dune.ImdbRating = "8.5"
+	srv.AddMovie(dune)
+	changes, err = list.Refresh(ctx, client)
+	if err != nil || len(changes) != 1 || changes[0].From != "8.6" || changes[0].To != "8.5" || changes[0].New() {
+		t.Errorf("got %+v, %v", changes, err)
+	}
+
+	//failed lookups are returned and leave their entry as it was.
+	empty := omdbtest.NewServer()
+	defer empty.Close()
+	if _, err := list.Refresh(ctx, empty.Client()); !errors.Is(err, omdb.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+	if e, ok := list.Get("tt15239678"); !ok || e.ImdbRating != "8.5" {
+		t.Errorf("got %+v, %v after a failed refresh", e, ok)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+
+	srv := omdbtest.NewServer()
+	defer srv.Close()
+	srv.AddMovie(omdb.MovieResult{Title: "Dune: Part Two", Year: "2024", ImdbID: "tt15239678", Metascore: "79"})
+
+	ctx := context.Background()
+	store := omdb.NewLRUCache(10)
+	list, err := watchlist.Open(ctx, store, "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Add(ctx, srv.Client(), "tt15239678", "sci-fi"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := watchlist.Open(ctx, store, "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := reopened.Get("tt15239678")
+	if !ok || e.Title != "Dune: Part Two" || e.Metascore != "79" || !e.HasTag("sci-fi") || e.Added.IsZero() {
+		t.Errorf("got %+v, %v from the store", e, ok)
+	}
+	//lists are stored under their name.
+	if other, err := watchlist.Open(ctx, store, "other"); err != nil || len(other.List()) != 0 {
+		t.Errorf("got %+v, %v for another name", other.List(), err)
+	}
+
+	if err := store.Set(ctx, "watchlist:broken", []byte("{"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watchlist.Open(ctx, store, "broken"); err == nil {
+		t.Error("got no error for an invalid list")
+	}
+}
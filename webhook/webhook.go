@@ -0,0 +1,301 @@
+//Package webhook notifies webhooks of the changes to the tracked titles: the
+//new episodes and air dates found by a tracker.Tracker and the ratings,
+//release dates and posters which changed on the Refresh of a
+//watchlist.Watchlist. Every change is POSTed as a JSON Payload to each URL,
+//signed with HMAC-SHA256 when a secret is set, and retried on failure:
+//
+//	n := webhook.New([]string{"https://example.com/hooks/omdb"},
+//		webhook.WithSecret(secret))
+//	t, err := tracker.Open(ctx, cache, "shows", client,
+//		tracker.OnNewEpisode(n.TrackerEvent), tracker.OnAirDateChange(n.TrackerEvent))
+//	...
+//	changes, err := list.Refresh(ctx, client)
+//	...
+//	err = n.WatchlistChanges(ctx, changes)
+//
+//The receiver checks the signature by computing the HMAC-SHA256 of the body
+//with the secret and comparing its hex encoding to the SignatureHeader,
+//after its "sha256=" prefix.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahin/omdb/tracker"
+	"github.com/ahin/omdb/watchlist"
+)
+
+//SignatureHeader holds the signature of a payload, EventHeader its Event.
+const (
+	SignatureHeader = "X-Omdb-Signature"
+	EventHeader     = "X-Omdb-Event"
+)
+
+//Events of a Payload.
+const (
+	EventNewEpisode     = "new_episode"
+	EventAirDateChanged = "air_date_changed"
+	EventRatingChanged  = "rating_changed"
+	EventReleaseChanged = "release_changed"
+	EventPosterChanged  = "poster_changed"
+	EventFieldChanged   = "field_changed"
+)
+
+//Payload is the JSON body POSTed to the webhooks.
+type Payload struct {
+	Event  string `json:"event"`
+	ImdbID string `json:"imdbID"`
+	Title  string `json:"title"`
+	//SeriesID, Season and Episode are set for the episodes of a series.
+	SeriesID string `json:"seriesID,omitempty"`
+	Season   int    `json:"season,omitempty"`
+	Episode  int    `json:"episode,omitempty"`
+	//Field is the field of a watchlist.Entry or of the series which changed,
+	//from From to To.
+	Field string    `json:"field,omitempty"`
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+//Option configures a Notifier.
+type Option func(*Notifier)
+
+//WithSecret signs the payloads with secret, see SignatureHeader.
+func WithSecret(secret string) Option {
+	return func(n *Notifier) {
+		n.secret = []byte(secret)
+	}
+}
+
+//DefaultTimeout bounds each POST of the default http.Client,
+//DefaultEventTimeout every delivery of a tracker event, retries included.
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultEventTimeout = 30 * time.Second
+)
+
+//WithHTTPClient sets the http.Client used to send the payloads, by default
+//one with a Timeout of DefaultTimeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+//WithRetry retries a failed delivery, a network error or a 429 or 5xx
+//response, up to attempts times after the first, waiting base before the
+//first retry and doubling the wait for each following one. The default is 3
+//retries from one second.
+func WithRetry(attempts int, base time.Duration) Option {
+	return func(n *Notifier) {
+		n.attempts, n.base = attempts, base
+	}
+}
+
+//WithEventTimeout bounds the deliveries of a tracker event, which block the
+//Check of the tracker, to timeout instead of DefaultEventTimeout.
+func WithEventTimeout(timeout time.Duration) Option {
+	return func(n *Notifier) {
+		n.eventTimeout = timeout
+	}
+}
+
+//OnError calls f with the errors of the deliveries made from the callbacks
+//of a tracker, which otherwise ignore them.
+func OnError(f func(error)) Option {
+	return func(n *Notifier) {
+		n.onError = f
+	}
+}
+
+//Notifier POSTs payloads to webhooks. It is safe for concurrent use.
+type Notifier struct {
+	urls         []string
+	secret       []byte
+	client       *http.Client
+	attempts     int
+	base         time.Duration
+	eventTimeout time.Duration
+	onError      func(error)
+}
+
+//New returns a Notifier POSTing to urls.
+func New(urls []string, opts ...Option) *Notifier {
+
+	n := &Notifier{
+		urls:         append([]string(nil), urls...),
+		client:       &http.Client{Timeout: DefaultTimeout},
+		attempts:     3,
+		base:         time.Second,
+		eventTimeout: DefaultEventTimeout,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+//Notify POSTs p to every webhook. Failed deliveries are joined into the
+//returned error, they don't stop the others.
+func (n *Notifier) Notify(ctx context.Context, p Payload) error {
+
+	if p.Time.IsZero() {
+		p.Time = time.Now()
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, url := range n.urls {
+		if err := n.deliver(ctx, url, p.Event, body); err != nil {
+			errs = append(errs, &deliveryError{url: url, err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+//TrackerEvent notifies e, it is meant as the callbacks of a tracker. The
+//changes of a SeriesChanged event are notified one by one. The deliveries
+//are bounded by the event timeout, see WithEventTimeout, and their errors
+//are passed to the OnError callback.
+func (n *Notifier) TrackerEvent(e tracker.Event) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.eventTimeout)
+	defer cancel()
+	if e.Kind == tracker.SeriesChanged {
+		var errs []error
+		for _, c := range e.Changes {
+			p := Payload{Event: fieldEvent(c.Field), ImdbID: e.SeriesID, Title: e.SeriesTitle, Field: c.Field, From: c.Old, To: c.New}
+			if err := n.Notify(ctx, p); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := errors.Join(errs...); err != nil && n.onError != nil {
+			n.onError(err)
+		}
+		return
+	}
+
+	p := Payload{
+		Event:    EventNewEpisode,
+		ImdbID:   e.Episode.ImdbID,
+		Title:    e.Episode.Title,
+		SeriesID: e.SeriesID,
+		Season:   e.Episode.Season,
+		Episode:  e.Episode.Episode,
+	}
+	if p.Title == "" {
+		p.Title = e.SeriesTitle + " " + e.Episode.Code()
+	}
+	if e.Kind == tracker.AirDateChanged {
+		p.Event, p.Field, p.From, p.To = EventAirDateChanged, "Released", e.PreviousReleased, e.Episode.Released
+	}
+	if err := n.Notify(ctx, p); err != nil && n.onError != nil {
+		n.onError(err)
+	}
+}
+
+//WatchlistChanges notifies the changes returned by the Refresh of a
+//watchlist.
+func (n *Notifier) WatchlistChanges(ctx context.Context, changes []watchlist.Change) error {
+
+	var errs []error
+	for _, c := range changes {
+		p := Payload{Event: fieldEvent(c.Field), ImdbID: c.ImdbID, Title: c.Title, Field: c.Field, From: c.From, To: c.To}
+		if err := n.Notify(ctx, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+//fieldEvent returns the event of a change of field, a field of a
+//watchlist.Entry or of an OMDB result.
+func fieldEvent(field string) string {
+	switch {
+	case field == watchlist.FieldPoster:
+		return EventPosterChanged
+	case field == watchlist.FieldReleased || field == watchlist.FieldDVD:
+		return EventReleaseChanged
+	case field == watchlist.FieldImdbRating || field == watchlist.FieldMetascore ||
+		field == watchlist.FieldRottenTomatoes || strings.HasPrefix(field, "Ratings."):
+		return EventRatingChanged
+	}
+	return EventFieldChanged
+}
+
+//deliver POSTs body to url, retrying as configured.
+func (n *Notifier) deliver(ctx context.Context, url, event string, body []byte) error {
+
+	var signature string
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	wait := n.base
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, url, event, signature, body)
+		if err == nil || !retry || attempt >= n.attempts {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+//post sends body once, retry tells whether a failure is worth retrying.
+func (n *Notifier) post(ctx context.Context, url, event, signature string, body []byte) (retry bool, err error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, errors.New("Unexpected status " + strconv.Itoa(resp.StatusCode))
+}
+
+//deliveryError is a failed delivery, prefixed by its URL.
+type deliveryError struct {
+	url string
+	err error
+}
+
+func (e *deliveryError) Error() string { return "webhook: " + e.url + ": " + e.err.Error() }
+func (e *deliveryError) Unwrap() error { return e.err }
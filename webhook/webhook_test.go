@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahin/omdb"
+	"github.com/ahin/omdb/tracker"
+	"github.com/ahin/omdb/watchlist"
+)
+
+//recorder is a webhook answering with the statuses in turn, then 204.
+type recorder struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newRecorder(statuses ...int) *recorder {
+	r := &recorder{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := http.StatusNoContent
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return r
+}
+
+func (r *recorder) payloads(t *testing.T) []Payload {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var payloads []Payload
+	for _, body := range r.bodies {
+		var p Payload
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Fatal(err)
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads
+}
+
+func TestSignature(t *testing.T) {
+
+	hook := newRecorder()
+	defer hook.Close()
+
+	n := New([]string{hook.URL}, WithSecret("secret"))
+	if err := n.Notify(context.Background(), Payload{Event: EventRatingChanged, ImdbID: "tt0133093"}); err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(hook.bodies[0])
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	req := hook.requests[0]
+	if got := req.Header.Get(SignatureHeader); got != want {
+		t.Errorf("got signature %q, want %q", got, want)
+	}
+	if got := req.Header.Get(EventHeader); got != EventRatingChanged {
+		t.Errorf("got event %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got content type %q", got)
+	}
+
+	//without a secret the payloads aren't signed.
+	if err := New([]string{hook.URL}).Notify(context.Background(), Payload{Event: EventRatingChanged}); err != nil {
+		t.Fatal(err)
+	}
+	if got := hook.requests[1].Header.Get(SignatureHeader); got != "" {
+		t.Errorf("got signature %q without a secret", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+
+	for _, tt := range []struct {
+		name     string
+		statuses []int
+		requests int
+		fails    bool
+	}{
+		{"5xx", []int{http.StatusBadGateway, http.StatusServiceUnavailable}, 3, false},
+		{"429", []int{http.StatusTooManyRequests}, 2, false},
+		{"4xx", []int{http.StatusBadRequest}, 1, true},
+		{"exhausted", []int{500, 500, 500, 500}, 3, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := newRecorder(tt.statuses...)
+			defer hook.Close()
+
+			n := New([]string{hook.URL}, WithRetry(2, time.Millisecond))
+			err := n.Notify(context.Background(), Payload{Event: EventNewEpisode})
+			if (err != nil) != tt.fails {
+				t.Errorf("got error %v", err)
+			}
+			if len(hook.requests) != tt.requests {
+				t.Errorf("got %d requests, want %d", len(hook.requests), tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+
+	hook := newRecorder(500, 500)
+	defer hook.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	n := New([]string{hook.URL}, WithRetry(3, time.Hour))
+	if err := n.Notify(ctx, Payload{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the deadline of the context", err)
+	}
+}
+
+func TestTrackerEvent(t *testing.T) {
+
+	hook := newRecorder(http.StatusBadRequest)
+	defer hook.Close()
+
+	var errs []error
+	n := New([]string{hook.URL}, WithRetry(0, 0), OnError(func(err error) { errs = append(errs, err) }))
+	n.TrackerEvent(tracker.Event{Kind: tracker.NewEpisode, SeriesID: "tt0903747", SeriesTitle: "Breaking Bad",
+		Episode: tracker.Episode{Season: 1, Episode: 2}})
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want the 400 response", errs)
+	}
+	n.TrackerEvent(tracker.Event{Kind: tracker.AirDateChanged, SeriesID: "tt0903747", SeriesTitle: "Breaking Bad", PreviousReleased: "N/A",
+		Episode: tracker.Episode{Season: 5, Episode: 16, Title: "Felina", ImdbID: "tt2301455", Released: "29 Sep 2013"}})
+	n.TrackerEvent(tracker.Event{Kind: tracker.SeriesChanged, SeriesID: "tt0903747", SeriesTitle: "Breaking Bad", Changes: []omdb.FieldChange{
+		{Field: "ImdbRating", Old: "9.4", New: "9.5"},
+		{Field: "Awards", Old: "", New: "Won 16 Primetime Emmys."},
+	}})
+
+	payloads := hook.payloads(t)
+	if len(payloads) != 4 {
+		t.Fatalf("got %d payloads, want 4", len(payloads))
+	}
+	if p := payloads[0]; p.Event != EventNewEpisode || p.Title != "Breaking Bad S01E02" || p.Season != 1 || p.Episode != 2 {
+		t.Errorf("got new episode %+v", p)
+	}
+	if p := payloads[1]; p.Event != EventAirDateChanged || p.ImdbID != "tt2301455" || p.Title != "Felina" || p.SeriesID != "tt0903747" ||
+		p.Field != "Released" || p.From != "N/A" || p.To != "29 Sep 2013" {
+		t.Errorf("got air date change %+v", p)
+	}
+	if p := payloads[2]; p.Event != EventRatingChanged || p.ImdbID != "tt0903747" || p.From != "9.4" || p.To != "9.5" {
+		t.Errorf("got rating change %+v", p)
+	}
+	if p := payloads[3]; p.Event != EventFieldChanged || p.Field != "Awards" {
+		t.Errorf("got field change %+v", p)
+	}
+}
+
+func TestFieldEvent(t *testing.T) {
+
+	for field, want := range map[string]string{
+		watchlist.FieldPoster:         EventPosterChanged,
+		watchlist.FieldReleased:       EventReleaseChanged,
+		watchlist.FieldDVD:            EventReleaseChanged,
+		watchlist.FieldImdbRating:     EventRatingChanged,
+		watchlist.FieldMetascore:      EventRatingChanged,
+		watchlist.FieldRottenTomatoes: EventRatingChanged,
+		"Ratings.Metacritic":          EventRatingChanged,
+		"Plot":                        EventFieldChanged,
+	} {
+		if got := fieldEvent(field); got != want {
+			t.Errorf("fieldEvent(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package omdb
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+//Response formats accepted by WithResponseFormat.
+const (
+	FormatJSON = "json"
+	FormatXML  = "xml"
+)
+
+//xmlRoot is the document OMDB returns with r=xml. Results are elements (movie,
+//series, episode or result) whose attributes are named like the JSON fields.
+type xmlRoot struct {
+	Response string     `xml:"response,attr"`
+	Error    string     `xml:"error"`
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Items    []xmlItem  `xml:",any"`
+}
+
+type xmlItem struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+}
+
+//decodeXML unmarshals data into an xmlRoot, an error response is returned as
+//an APIError.
+func decodeXML(data []byte) (*xmlRoot, error) {
+
+	root := xmlRoot{}
+	err := xml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	if root.Response == "False" {
+		return nil, &APIError{Message: strings.TrimSpace(root.Error)}
+	}
+
+	return &root, nil
+}
+
+//xmlResult returns the MovieResult, SeriesResult or EpisodeResult held by
+//root, or nil for an unknown type.
+func xmlResult(root *xmlRoot) interface{} {
+
+	if len(root.Items) == 0 {
+		return nil
+	}
+	attrs := root.Items[0].Attrs
+
+	var typ string
+	for _, a := range attrs {
+		if a.Name.Local == "type" {
+			typ = a.Value
+		}
+	}
+
+	switch typ {
+	case "movie":
+		movie := MovieResult{}
+		setAttrs(&movie, attrs)
+		return movie
+	case "series":
+		series := SeriesResult{}
+		setAttrs(&series, attrs)
+		return series
+	case "episode":
+		episode := EpisodeResult{}
+		setAttrs(&episode, attrs)
+		return episode
+	}
+	return nil
+}
+
+//xmlSearch converts a search document to a SearchResponse.
+func xmlSearch(root *xmlRoot) *SearchResponse {
+
+	res := &SearchResponse{Response: root.Response}
+	setAttrs(res, root.Attrs)
+	for _, item := range root.Items {
+		result := SearchResult{}
+		setAttrs(&result, item.Attrs)
+		res.Search = append(res.Search, result)
+	}
+	return res
+}
+
+//xmlSeason converts a season document to a SeasonResult.
+func xmlSeason(root *xmlRoot) *SeasonResult {
+
+	res := &SeasonResult{}
+	setAttrs(res, root.Attrs)
+	for _, item := range root.Items {
+		episode := SeasonEpisode{}
+		setAttrs(&episode, item.Attrs)
+		res.Episodes = append(res.Episodes, episode)
+	}
+	return res
+}
+
+//setAttrs copies attrs into the string fields of the struct pointed to by v
+//whose name matches the attribute name, ignoring case.
+func setAttrs(v interface{}, attrs []xml.Attr) {
+
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for _, a := range attrs {
+		for i := 0; i < rt.NumField(); i++ {
+			if rt.Field(i).Type.Kind() == reflect.String && strings.EqualFold(rt.Field(i).Name, a.Name.Local) {
+				rv.Field(i).SetString(a.Value)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package omdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestXMLDecode(t *testing.T) {
+
+	srv := fixtureServer(t)
+	client := NewClient("key", nil, WithBaseURL(srv.URL), WithResponseFormat(FormatXML))
+
+	res, err := client.SearchByTitle(QueryData{Title: "movie"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	movie, ok := res.(MovieResult)
+	if !ok || movie.Title != "The Matrix" || movie.Year != "1999" || movie.ImdbID != "tt0133093" || movie.ImdbRating != "8.7" ||
+		movie.Metascore != "73" || movie.Awards != "Won 4 Oscars. 42 wins & 52 nominations total" {
+		t.Errorf("got movie %+v", res)
+	}
+
+	res, err = client.SearchByTitle(QueryData{Title: "series"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, ok := res.(SeriesResult)
+	if !ok || series.Title != "Breaking Bad" || series.Year != "2008–2013" || series.TotalSeasons != "5" || series.ImdbID != "tt0903747" {
+		t.Errorf("got series %+v", res)
+	}
+
+	res, err = client.SearchByTitle(QueryData{Title: "episode"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	episode, ok := res.(EpisodeResult)
+	if !ok || episode.Title != "Cat's in the Bag..." || episode.SeriesID != "tt0903747" || episode.Season != "1" || episode.Episode != "2" {
+		t.Errorf("got episode %+v", res)
+	}
+
+	search, err := client.SearchByText(QueryData{Title: "search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.TotalResults != "3" || search.Response != "True" || len(search.Search) != 3 {
+		t.Fatalf("got search %+v", search)
+	}
+	if r := search.Search[1]; r.Title != "The Matrix Reloaded" || r.Year != "2003" || r.ImdbID != "tt0234215" || r.Type != "movie" || r.Poster == "" {
+		t.Errorf("got search result %+v", r)
+	}
+
+	_, err = client.SearchByTitle(QueryData{Title: "notfound"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Movie not found!" || !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want Movie not found!", err)
+	}
+}